@@ -0,0 +1,1028 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/db"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+// registerClicsHandlers registers handlers of read-only CLICS Contest API
+// that is used by ICPC tools (resolver, CDS, balloon printers).
+func (v *View) registerClicsHandlers(g *echo.Group) {
+	auth := v.extractAuth(v.sessionAuth, v.basicAuth, v.guestAuth)
+	g.GET("/v0/clics/contests", v.observeClicsContests, auth)
+	g.GET(
+		"/v0/clics/contests/:contest", v.observeClicsContest,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/judgement-types",
+		v.observeClicsJudgementTypes, auth, v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/languages", v.observeClicsLanguages,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/problems", v.observeClicsProblems,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/teams", v.observeClicsTeams,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/scoreboard", v.observeClicsScoreboard,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/submissions", v.observeClicsSubmissions,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestFullStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/judgements", v.observeClicsJudgements,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestFullStandingsRole),
+	)
+	g.GET(
+		"/v0/clics/contests/:contest/event-feed", v.observeClicsEventFeed,
+		auth, v.extractContest,
+		v.requirePermission(models.ObserveContestFullStandingsRole),
+	)
+}
+
+// clicsPenaltyTime contains penalty in minutes for rejected solution.
+const clicsPenaltyTime = 20
+
+type ClicsContest struct {
+	ID                       string  `json:"id"`
+	Name                     string  `json:"name"`
+	FormalName               string  `json:"formal_name"`
+	StartTime                *string `json:"start_time"`
+	Duration                 string  `json:"duration"`
+	ScoreboardFreezeDuration *string `json:"scoreboard_freeze_duration,omitempty"`
+	// ScoreboardType contains "pass-fail" for ICPC standings and "score"
+	// for IOI standings.
+	ScoreboardType string `json:"scoreboard_type"`
+	PenaltyTime    int    `json:"penalty_time"`
+}
+
+type ClicsJudgementType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Penalty bool   `json:"penalty"`
+	Solved  bool   `json:"solved"`
+}
+
+type ClicsLanguage struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ClicsProblem struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Name    string `json:"name"`
+	Ordinal int    `json:"ordinal"`
+}
+
+type ClicsTeam struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type ClicsSubmission struct {
+	ID          string `json:"id"`
+	LanguageID  string `json:"language_id"`
+	ProblemID   string `json:"problem_id"`
+	TeamID      string `json:"team_id"`
+	Time        string `json:"time"`
+	ContestTime string `json:"contest_time"`
+}
+
+type ClicsJudgement struct {
+	ID               string  `json:"id"`
+	SubmissionID     string  `json:"submission_id"`
+	JudgementTypeID  *string `json:"judgement_type_id"`
+	StartTime        string  `json:"start_time"`
+	StartContestTime string  `json:"start_contest_time"`
+	EndTime          *string `json:"end_time"`
+	EndContestTime   *string `json:"end_contest_time"`
+}
+
+type ClicsScoreboardScore struct {
+	NumSolved int `json:"num_solved"`
+	// TotalTime contains penalty in minutes.
+	TotalTime int64 `json:"total_time"`
+	// Score contains points of IOI standings.
+	Score *float64 `json:"score,omitempty"`
+}
+
+type ClicsScoreboardProblem struct {
+	ProblemID  string `json:"problem_id"`
+	NumJudged  int    `json:"num_judged"`
+	NumPending int    `json:"num_pending"`
+	Solved     bool   `json:"solved"`
+	// Time contains minutes from begin of contest to accepted solution.
+	Time *int64 `json:"time,omitempty"`
+}
+
+type ClicsScoreboardRow struct {
+	Rank     int                      `json:"rank"`
+	TeamID   string                   `json:"team_id"`
+	Score    ClicsScoreboardScore     `json:"score"`
+	Problems []ClicsScoreboardProblem `json:"problems"`
+}
+
+type ClicsScoreboard struct {
+	Time        string               `json:"time"`
+	ContestTime string               `json:"contest_time"`
+	Rows        []ClicsScoreboardRow `json:"rows"`
+}
+
+// ClicsEvent represents line of CLICS event feed.
+type ClicsEvent struct {
+	Type string `json:"type"`
+	// ID contains ID of changed object or nil for singleton objects.
+	ID *string `json:"id"`
+	// Data contains object or nil when object is deleted.
+	Data  any    `json:"data"`
+	Token string `json:"token,omitempty"`
+}
+
+// formatClicsTime formats absolute time in CLICS format.
+func formatClicsTime(t int64) string {
+	return time.Unix(t, 0).UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// formatClicsRelTime formats duration in seconds in CLICS format
+// "(-)h:mm:ss.uuu".
+func formatClicsRelTime(seconds int64) string {
+	sign := ""
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+	return fmt.Sprintf(
+		"%s%d:%02d:%02d.000",
+		sign, seconds/3600, seconds/60%60, seconds%60,
+	)
+}
+
+func formatClicsID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+func makeClicsContest(contest models.Contest) ClicsContest {
+	resp := ClicsContest{
+		ID:             formatClicsID(contest.ID),
+		Name:           contest.Title,
+		FormalName:     contest.Title,
+		ScoreboardType: "pass-fail",
+		PenaltyTime:    clicsPenaltyTime,
+	}
+	config, err := contest.GetConfig()
+	if err != nil {
+		return resp
+	}
+	if config.BeginTime != 0 {
+		resp.StartTime = getPtr(formatClicsTime(int64(config.BeginTime)))
+	}
+	resp.Duration = formatClicsRelTime(int64(config.Duration))
+	if config.FreezeBeginDuration > 0 &&
+		config.FreezeBeginDuration < config.Duration {
+		resp.ScoreboardFreezeDuration = getPtr(formatClicsRelTime(
+			int64(config.Duration - config.FreezeBeginDuration),
+		))
+	}
+	if config.StandingsKind == models.IOIStandings {
+		resp.ScoreboardType = "score"
+	}
+	return resp
+}
+
+// clicsJudgementTypes contains judgement types in order of verdicts.
+var clicsJudgementTypes = []ClicsJudgementType{
+	{ID: "AC", Name: "Accepted", Solved: true},
+	{ID: "RJ", Name: "Rejected", Penalty: true},
+	{ID: "CE", Name: "Compile Error"},
+	{ID: "TLE", Name: "Time Limit Exceeded", Penalty: true},
+	{ID: "MLE", Name: "Memory Limit Exceeded", Penalty: true},
+	{ID: "RTE", Name: "Run-Time Error", Penalty: true},
+	{ID: "WA", Name: "Wrong Answer", Penalty: true},
+	{ID: "PE", Name: "Presentation Error", Penalty: true},
+	{ID: "PA", Name: "Partially Accepted", Penalty: true},
+	{ID: "JE", Name: "Judging Error"},
+	{ID: "SV", Name: "Security Violation", Penalty: true},
+}
+
+// getClicsJudgementTypeID returns ID of judgement type for verdict.
+func getClicsJudgementTypeID(verdict models.Verdict) string {
+	if verdict >= models.Accepted &&
+		int(verdict) <= len(clicsJudgementTypes) {
+		return clicsJudgementTypes[verdict-models.Accepted].ID
+	}
+	return "JE"
+}
+
+func (v *View) makeClicsProblem(
+	c echo.Context, problem models.ContestProblem, ordinal int,
+) ClicsProblem {
+	resp := ClicsProblem{
+		ID:      formatClicsID(problem.ID),
+		Label:   problem.Code,
+		Name:    problem.Code,
+		Ordinal: ordinal,
+	}
+	if base, err := v.core.Problems.Get(problem.ProblemID); err == nil {
+		resp.Name = v.makeProblem(
+			c, base, managers.PermissionSet{}, false,
+		).Title
+	}
+	return resp
+}
+
+func (v *View) makeClicsTeam(participant models.ContestParticipant) ClicsTeam {
+	return ClicsTeam{
+		ID: formatClicsID(participant.ID),
+		Name: getContestParticipantName(
+			makeContestParticipant(participant, v.core),
+		),
+	}
+}
+
+// getClicsContestBeginTime returns begin time of contest.
+func getClicsContestBeginTime(contest models.Contest) int64 {
+	config, err := contest.GetConfig()
+	if err != nil {
+		return 0
+	}
+	return int64(config.BeginTime)
+}
+
+func makeClicsSubmission(
+	contestSolution models.ContestSolution, solution models.Solution,
+	beginTime int64,
+) ClicsSubmission {
+	return ClicsSubmission{
+		ID:          formatClicsID(contestSolution.ID),
+		LanguageID:  formatClicsID(solution.CompilerID),
+		ProblemID:   formatClicsID(contestSolution.ProblemID),
+		TeamID:      formatClicsID(contestSolution.ParticipantID),
+		Time:        formatClicsTime(solution.CreateTime),
+		ContestTime: formatClicsRelTime(solution.CreateTime - beginTime),
+	}
+}
+
+// makeClicsJudgement returns judgement of solution.
+//
+// Judgements are identified by submissions, so rejudging updates judgement.
+// Returns false if solution is not judged yet.
+func makeClicsJudgement(
+	contestSolution models.ContestSolution, solution models.Solution,
+	beginTime, endTime int64,
+) (ClicsJudgement, bool) {
+	report, err := solution.GetReport()
+	if err != nil || report == nil {
+		return ClicsJudgement{}, false
+	}
+	return ClicsJudgement{
+		ID:               formatClicsID(contestSolution.ID),
+		SubmissionID:     formatClicsID(contestSolution.ID),
+		JudgementTypeID:  getPtr(getClicsJudgementTypeID(report.Verdict)),
+		StartTime:        formatClicsTime(solution.CreateTime),
+		StartContestTime: formatClicsRelTime(solution.CreateTime - beginTime),
+		EndTime:          getPtr(formatClicsTime(endTime)),
+		EndContestTime:   getPtr(formatClicsRelTime(endTime - beginTime)),
+	}, true
+}
+
+func (v *View) observeClicsContests(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	if err := syncStore(c, v.core.Contests); err != nil {
+		return err
+	}
+	contests, err := v.core.Contests.All()
+	if err != nil {
+		return err
+	}
+	resp := []ClicsContest{}
+	for _, contest := range contests {
+		contestCtx, err := v.contests.BuildContext(accountCtx, contest)
+		if err != nil {
+			return err
+		}
+		if contestCtx.HasPermission(models.ObserveContestStandingsRole) {
+			resp = append(resp, makeClicsContest(contest))
+		}
+	}
+	sortFunc(resp, func(l, r ClicsContest) bool {
+		return l.ID < r.ID
+	})
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) observeClicsContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	return c.JSON(http.StatusOK, makeClicsContest(contestCtx.Contest))
+}
+
+func (v *View) observeClicsJudgementTypes(c echo.Context) error {
+	return c.JSON(http.StatusOK, clicsJudgementTypes)
+}
+
+func (v *View) getClicsLanguages(c echo.Context) ([]ClicsLanguage, error) {
+	if err := syncStore(c, v.core.Compilers); err != nil {
+		return nil, err
+	}
+	compilers, err := v.core.Compilers.All()
+	if err != nil {
+		return nil, err
+	}
+	resp := []ClicsLanguage{}
+	for _, compiler := range compilers {
+		// Solutions can not be submitted with broken compilers.
+		if compiler.IsBroken() {
+			continue
+		}
+		resp = append(resp, ClicsLanguage{
+			ID:   formatClicsID(compiler.ID),
+			Name: compiler.Name,
+		})
+	}
+	sortFunc(resp, func(l, r ClicsLanguage) bool {
+		return l.Name < r.Name
+	})
+	return resp, nil
+}
+
+func (v *View) observeClicsLanguages(c echo.Context) error {
+	resp, err := v.getClicsLanguages(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// getClicsProblemOrdinals returns ordinals of contest problems ordered
+// by codes.
+func getClicsProblemOrdinals(problems []models.ContestProblem) map[int64]int {
+	sortFunc(problems, func(l, r models.ContestProblem) bool {
+		return l.Code < r.Code
+	})
+	ordinals := map[int64]int{}
+	for i, problem := range problems {
+		ordinals[problem.ID] = i
+	}
+	return ordinals
+}
+
+func (v *View) observeClicsProblems(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if !contestCtx.HasPermission(models.ObserveContestProblemsRole) {
+		return c.JSON(http.StatusOK, []ClicsProblem{})
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Problems); err != nil {
+		return err
+	}
+	problems, err := v.core.ContestProblems.FindByContest(contestCtx.Contest.ID)
+	if err != nil {
+		return err
+	}
+	ordinals := getClicsProblemOrdinals(problems)
+	resp := []ClicsProblem{}
+	for _, problem := range problems {
+		resp = append(resp, v.makeClicsProblem(c, problem, ordinals[problem.ID]))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// getClicsParticipants returns regular participants of contest.
+//
+// Only regular participants are exported as teams.
+func (v *View) getClicsParticipants(
+	contest models.Contest,
+) ([]models.ContestParticipant, error) {
+	participants, err := v.core.ContestParticipants.FindByContest(contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	var resp []models.ContestParticipant
+	for _, participant := range participants {
+		if participant.Kind == models.RegularParticipant {
+			resp = append(resp, participant)
+		}
+	}
+	sortFunc(resp, func(l, r models.ContestParticipant) bool {
+		return l.ID < r.ID
+	})
+	return resp, nil
+}
+
+func (v *View) observeClicsTeams(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.Users); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ScopeUsers); err != nil {
+		return err
+	}
+	participants, err := v.getClicsParticipants(contestCtx.Contest)
+	if err != nil {
+		return err
+	}
+	resp := []ClicsTeam{}
+	for _, participant := range participants {
+		resp = append(resp, v.makeClicsTeam(participant))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// makeClicsScoreboard converts standings to CLICS scoreboard.
+//
+// Only regular participants are exported and frozen cells are exported
+// as pending.
+func makeClicsScoreboard(
+	standings ContestStandings, problemIDs []int64,
+	contest models.Contest, now int64,
+) ClicsScoreboard {
+	resp := ClicsScoreboard{
+		Time:        formatClicsTime(now),
+		ContestTime: formatClicsRelTime(now - getClicsContestBeginTime(contest)),
+		Rows:        []ClicsScoreboardRow{},
+	}
+	regular := standings
+	regular.Rows = nil
+	for _, row := range standings.Rows {
+		if row.Participant.Kind == models.RegularParticipant {
+			regular.Rows = append(regular.Rows, row)
+		}
+	}
+	ranks := getContestStandingsRanks(regular)
+	for i, row := range regular.Rows {
+		rowResp := ClicsScoreboardRow{
+			Rank:     ranks[i],
+			TeamID:   formatClicsID(row.Participant.ID),
+			Score:    ClicsScoreboardScore{TotalTime: getValue(row.Penalty)},
+			Problems: []ClicsScoreboardProblem{},
+		}
+		if regular.Kind == models.IOIStandings {
+			rowResp.Score.Score = getPtr(getValue(row.Points))
+		}
+		for j, cell := range getStandingsCells(regular, row) {
+			if cell == nil || j >= len(problemIDs) {
+				continue
+			}
+			problem := ClicsScoreboardProblem{
+				ProblemID: formatClicsID(problemIDs[j]),
+				NumJudged: cell.Attempt,
+			}
+			switch {
+			case cell.Frozen:
+				problem.NumJudged, problem.NumPending = 0, cell.Attempt
+			case cell.Verdict == "":
+				problem.NumJudged, problem.NumPending = cell.Attempt-1, 1
+			default:
+				problem.Solved = isStandingsCellSolved(regular, j, *cell)
+			}
+			if problem.Solved {
+				rowResp.Score.NumSolved++
+				problem.Time = getPtr(getValue(cell.Time) / 60)
+			}
+			rowResp.Problems = append(rowResp.Problems, problem)
+		}
+		resp.Rows = append(resp.Rows, rowResp)
+	}
+	return resp
+}
+
+func (v *View) observeClicsScoreboard(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	standings, err := v.makeContestStandings(
+		c, contestCtx, contestStandingsFilter{},
+	)
+	if err != nil {
+		return err
+	}
+	problems, err := v.core.ContestProblems.FindByContest(contestCtx.Contest.ID)
+	if err != nil {
+		return err
+	}
+	problemIDs := make([]int64, len(standings.Columns))
+	for i, column := range standings.Columns {
+		for _, problem := range problems {
+			if problem.Code == column.Code {
+				problemIDs[i] = problem.ID
+			}
+		}
+	}
+	return c.JSON(http.StatusOK, makeClicsScoreboard(
+		standings, problemIDs, contestCtx.Contest, contestCtx.Now.Unix(),
+	))
+}
+
+// getClicsSolutions returns solutions of regular participants.
+func (v *View) getClicsSolutions(
+	c echo.Context, contest models.Contest,
+) ([]models.ContestSolution, error) {
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return nil, err
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return nil, err
+	}
+	participants, err := v.getClicsParticipants(contest)
+	if err != nil {
+		return nil, err
+	}
+	regular := map[int64]struct{}{}
+	for _, participant := range participants {
+		regular[participant.ID] = struct{}{}
+	}
+	solutions, err := v.core.ContestSolutions.FindByContest(contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	var resp []models.ContestSolution
+	for _, solution := range solutions {
+		if _, ok := regular[solution.ParticipantID]; ok {
+			resp = append(resp, solution)
+		}
+	}
+	sortFunc(resp, func(l, r models.ContestSolution) bool {
+		return l.ID < r.ID
+	})
+	return resp, nil
+}
+
+func (v *View) observeClicsSubmissions(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	solutions, err := v.getClicsSolutions(c, contestCtx.Contest)
+	if err != nil {
+		return err
+	}
+	beginTime := getClicsContestBeginTime(contestCtx.Contest)
+	resp := []ClicsSubmission{}
+	for _, contestSolution := range solutions {
+		solution, err := v.core.Solutions.Get(contestSolution.SolutionID)
+		if err != nil {
+			continue
+		}
+		resp = append(resp, makeClicsSubmission(
+			contestSolution, solution, beginTime,
+		))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// observeClicsJudgements returns judgements of solutions.
+//
+// Time of judging is not stored, so time of submission is used as end
+// time of judgement.
+func (v *View) observeClicsJudgements(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	solutions, err := v.getClicsSolutions(c, contestCtx.Contest)
+	if err != nil {
+		return err
+	}
+	beginTime := getClicsContestBeginTime(contestCtx.Contest)
+	resp := []ClicsJudgement{}
+	for _, contestSolution := range solutions {
+		solution, err := v.core.Solutions.Get(contestSolution.SolutionID)
+		if err != nil {
+			continue
+		}
+		if judgement, ok := makeClicsJudgement(
+			contestSolution, solution, beginTime, solution.CreateTime,
+		); ok {
+			resp = append(resp, judgement)
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type clicsEventFeedFilter struct {
+	// SinceToken contains token of last received event.
+	SinceToken string `query:"since_token"`
+}
+
+// clicsFeedEvent represents event of feed with its position.
+type clicsFeedEvent struct {
+	Time int64
+	// Order contains order of object type for events with equal time.
+	Order   int
+	EventID int64
+	Event   ClicsEvent
+}
+
+func clicsFeedEventLess(l, r clicsFeedEvent) bool {
+	if l.Time != r.Time {
+		return l.Time < r.Time
+	}
+	if l.Order != r.Order {
+		return l.Order < r.Order
+	}
+	return l.EventID < r.EventID
+}
+
+// clicsFeedCacheSize contains maximal amount of cached event feeds.
+const clicsFeedCacheSize = 64
+
+type clicsFeedCacheKey struct {
+	ContestID int64
+	Locale    string
+}
+
+// clicsFeedCache contains event feeds of contests.
+//
+// Feeds are updated incrementally, so event tables of stores are not
+// replayed on every request.
+type clicsFeedCache struct {
+	feeds map[clicsFeedCacheKey]*clicsFeed
+	limit int
+	mutex sync.Mutex
+}
+
+func newClicsFeedCache(limit int) *clicsFeedCache {
+	return &clicsFeedCache{
+		feeds: map[clicsFeedCacheKey]*clicsFeed{},
+		limit: limit,
+	}
+}
+
+// Get returns feed for specified key.
+//
+// Least recently used feed is evicted when cache is full.
+func (s *clicsFeedCache) Get(key clicsFeedCacheKey, now time.Time) *clicsFeed {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if feed, ok := s.feeds[key]; ok {
+		feed.accessTime = now
+		return feed
+	}
+	if len(s.feeds) >= s.limit {
+		var oldest clicsFeedCacheKey
+		var oldestTime time.Time
+		for key, feed := range s.feeds {
+			if oldestTime.IsZero() || feed.accessTime.Before(oldestTime) {
+				oldest, oldestTime = key, feed.accessTime
+			}
+		}
+		delete(s.feeds, oldest)
+	}
+	feed := &clicsFeed{accessTime: now}
+	s.feeds[key] = feed
+	return feed
+}
+
+// clicsFeed represents incrementally built event feed of contest.
+type clicsFeed struct {
+	mutex      sync.Mutex
+	accessTime time.Time
+	// beginTime contains begin time of contest that was used for
+	// building of feed.
+	beginTime        int64
+	problems         db.EventConsumer[models.ContestProblemEvent, *models.ContestProblemEvent]
+	participants     db.EventConsumer[models.ContestParticipantEvent, *models.ContestParticipantEvent]
+	contestSolutions db.EventConsumer[models.ContestSolutionEvent, *models.ContestSolutionEvent]
+	solutions        db.EventConsumer[models.SolutionEvent, *models.SolutionEvent]
+	problemIDs       map[int64]struct{}
+	teamIDs          map[int64]struct{}
+	submissionIDs    map[int64]struct{}
+	// submissions contains contest solutions by IDs of solutions.
+	submissions map[int64]models.ContestSolution
+	events      []ClicsEvent
+}
+
+func (f *clicsFeed) reset(v *View, beginTime int64) {
+	f.beginTime = beginTime
+	f.problems = db.NewEventConsumer[models.ContestProblemEvent, *models.ContestProblemEvent](
+		v.core.ContestProblems.Events(), 1,
+	)
+	f.participants = db.NewEventConsumer[models.ContestParticipantEvent, *models.ContestParticipantEvent](
+		v.core.ContestParticipants.Events(), 1,
+	)
+	f.contestSolutions = db.NewEventConsumer[models.ContestSolutionEvent, *models.ContestSolutionEvent](
+		v.core.ContestSolutions.Events(), 1,
+	)
+	f.solutions = db.NewEventConsumer[models.SolutionEvent, *models.SolutionEvent](
+		v.core.Solutions.Events(), 1,
+	)
+	f.problemIDs = map[int64]struct{}{}
+	f.teamIDs = map[int64]struct{}{}
+	f.submissionIDs = map[int64]struct{}{}
+	f.submissions = map[int64]models.ContestSolution{}
+	f.events = nil
+}
+
+// Update consumes new events of stores and appends them to feed.
+//
+// Returns copy of all events of feed.
+func (f *clicsFeed) Update(
+	c echo.Context, v *View, contest models.Contest,
+) ([]ClicsEvent, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	beginTime := getClicsContestBeginTime(contest)
+	// Times of submissions depend on begin time of contest.
+	if f.problems == nil || f.beginTime != beginTime {
+		f.reset(v, beginTime)
+	}
+	if err := f.update(c, v, contest); err != nil {
+		// Feed can be partially updated, so it should be built again.
+		f.problems = nil
+		return nil, err
+	}
+	return append([]ClicsEvent(nil), f.events...), nil
+}
+
+func (f *clicsFeed) update(
+	c echo.Context, v *View, contest models.Contest,
+) error {
+	ctx := getContext(c)
+	problems, err := v.core.ContestProblems.FindByContest(contest.ID)
+	if err != nil {
+		return err
+	}
+	ordinals := getClicsProblemOrdinals(problems)
+	var feed []clicsFeedEvent
+	if err := f.problems.ConsumeEvents(ctx, func(
+		event models.ContestProblemEvent,
+	) error {
+		if event.EventKind() == models.DeleteEvent {
+			if _, ok := f.problemIDs[event.ID]; ok {
+				feed = append(feed, newClicsFeedEvent(
+					"problems", 0, event.EventID(), event.BaseEventTime,
+					event.ID, nil,
+				))
+			}
+			return nil
+		}
+		if event.ContestID != contest.ID {
+			return nil
+		}
+		f.problemIDs[event.ID] = struct{}{}
+		feed = append(feed, newClicsFeedEvent(
+			"problems", 0, event.EventID(), event.BaseEventTime, event.ID,
+			v.makeClicsProblem(
+				c, event.ContestProblem, ordinals[event.ID],
+			),
+		))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := f.participants.ConsumeEvents(ctx, func(
+		event models.ContestParticipantEvent,
+	) error {
+		if event.EventKind() == models.DeleteEvent {
+			if _, ok := f.teamIDs[event.ID]; ok {
+				feed = append(feed, newClicsFeedEvent(
+					"teams", 1, event.EventID(), event.BaseEventTime,
+					event.ID, nil,
+				))
+			}
+			return nil
+		}
+		if event.ContestID != contest.ID ||
+			event.Kind != models.RegularParticipant {
+			return nil
+		}
+		f.teamIDs[event.ID] = struct{}{}
+		feed = append(feed, newClicsFeedEvent(
+			"teams", 1, event.EventID(), event.BaseEventTime, event.ID,
+			v.makeClicsTeam(event.ContestParticipant),
+		))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := f.contestSolutions.ConsumeEvents(ctx, func(
+		event models.ContestSolutionEvent,
+	) error {
+		if event.EventKind() == models.DeleteEvent {
+			if _, ok := f.submissionIDs[event.ID]; ok {
+				feed = append(feed, newClicsFeedEvent(
+					"submissions", 2, event.EventID(), event.BaseEventTime,
+					event.ID, nil,
+				))
+			}
+			return nil
+		}
+		if event.ContestID != contest.ID {
+			return nil
+		}
+		if _, ok := f.teamIDs[event.ParticipantID]; !ok {
+			return nil
+		}
+		// Solution is fetched only once for every submission.
+		solution, err := v.core.Solutions.Get(event.SolutionID)
+		if err != nil {
+			return nil
+		}
+		f.submissionIDs[event.ID] = struct{}{}
+		f.submissions[event.SolutionID] = event.ContestSolution
+		feed = append(feed, newClicsFeedEvent(
+			"submissions", 2, event.EventID(), event.BaseEventTime, event.ID,
+			makeClicsSubmission(event.ContestSolution, solution, f.beginTime),
+		))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if err := f.solutions.ConsumeEvents(ctx, func(
+		event models.SolutionEvent,
+	) error {
+		if event.EventKind() == models.DeleteEvent {
+			return nil
+		}
+		contestSolution, ok := f.submissions[event.ID]
+		if !ok {
+			return nil
+		}
+		judgement, ok := makeClicsJudgement(
+			contestSolution, event.Solution, f.beginTime, event.BaseEventTime,
+		)
+		if !ok {
+			return nil
+		}
+		feed = append(feed, newClicsFeedEvent(
+			"judgements", 3, event.EventID(), event.BaseEventTime,
+			contestSolution.ID, judgement,
+		))
+		return nil
+	}); err != nil {
+		return err
+	}
+	// New events are always appended to the end of feed, so tokens
+	// of received events remain valid.
+	sort.SliceStable(feed, func(i, j int) bool {
+		return clicsFeedEventLess(feed[i], feed[j])
+	})
+	for _, event := range feed {
+		f.events = append(f.events, event.Event)
+	}
+	return nil
+}
+
+// newClicsFeedEvent creates event of feed from store event.
+//
+// Token of event is unique in feed and consists of type and ID of
+// store event.
+func newClicsFeedEvent(
+	kind string, order int, eventID, eventTime int64, id int64, data any,
+) clicsFeedEvent {
+	return clicsFeedEvent{
+		Time:    eventTime,
+		Order:   order,
+		EventID: eventID,
+		Event: ClicsEvent{
+			Type:  kind,
+			ID:    getPtr(formatClicsID(id)),
+			Data:  data,
+			Token: fmt.Sprintf("%s-%d", kind, eventID),
+		},
+	}
+}
+
+// buildClicsEventFeed builds event feed of contest.
+//
+// Feed starts with current state of contest, judgement types and
+// languages. Changes of problems, teams, submissions and judgements are
+// derived from event tables of corresponding stores.
+func (v *View) buildClicsEventFeed(
+	c echo.Context, contest models.Contest,
+) ([]ClicsEvent, error) {
+	contestID := formatClicsID(contest.ID)
+	events := []ClicsEvent{
+		{Type: "contest", ID: &contestID, Data: makeClicsContest(contest)},
+	}
+	for _, judgementType := range clicsJudgementTypes {
+		events = append(events, ClicsEvent{
+			Type: "judgement-types",
+			ID:   getPtr(judgementType.ID),
+			Data: judgementType,
+		})
+	}
+	languages, err := v.getClicsLanguages(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, language := range languages {
+		events = append(events, ClicsEvent{
+			Type: "languages",
+			ID:   getPtr(language.ID),
+			Data: language,
+		})
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return nil, err
+	}
+	if err := syncStore(c, v.core.Problems); err != nil {
+		return nil, err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return nil, err
+	}
+	feed := v.clicsFeeds.Get(clicsFeedCacheKey{
+		ContestID: contest.ID,
+		Locale:    getLocale(c).Name(),
+	}, time.Now())
+	feedEvents, err := feed.Update(c, v, contest)
+	if err != nil {
+		return nil, err
+	}
+	return append(events, feedEvents...), nil
+}
+
+// observeClicsEventFeed returns event feed of contest in NDJSON format.
+//
+// Feed is not streamed, so clients should poll it with token of last
+// received event.
+func (v *View) observeClicsEventFeed(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var filter clicsEventFeedFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid filter."),
+		}
+	}
+	if err := syncStore(c, v.core.Users); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ScopeUsers); err != nil {
+		return err
+	}
+	events, err := v.buildClicsEventFeed(c, contestCtx.Contest)
+	if err != nil {
+		return err
+	}
+	if filter.SinceToken != "" {
+		found := false
+		for i, event := range events {
+			if event.Token == filter.SinceToken {
+				events, found = events[i+1:], true
+				break
+			}
+		}
+		if !found {
+			return errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Invalid token."),
+			}
+		}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return c.Blob(http.StatusOK, "application/x-ndjson", buf.Bytes())
+}
@@ -0,0 +1,314 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestFormatClicsRelTime(t *testing.T) {
+	for seconds, expected := range map[int64]string{
+		0:     "0:00:00.000",
+		61:    "0:01:01.000",
+		18000: "5:00:00.000",
+		-3661: "-1:01:01.000",
+	} {
+		if value := formatClicsRelTime(seconds); value != expected {
+			t.Fatalf("Expected %q, got %q", expected, value)
+		}
+	}
+}
+
+func TestClicsJudgementTypeID(t *testing.T) {
+	for verdict, expected := range map[models.Verdict]string{
+		models.Accepted:          "AC",
+		models.CompilationError:  "CE",
+		models.WrongAnswer:       "WA",
+		models.TimeLimitExceeded: "TLE",
+		models.SecurityViolation: "SV",
+		0:                        "JE",
+	} {
+		if value := getClicsJudgementTypeID(verdict); value != expected {
+			t.Fatalf("Expected %q, got %q", expected, value)
+		}
+	}
+}
+
+func TestMakeClicsScoreboard(t *testing.T) {
+	standings := testExportStandings
+	standings.Rows = nil
+	for _, row := range testExportStandings.Rows {
+		row.Participant.Kind = models.RegularParticipant
+		standings.Rows = append(standings.Rows, row)
+	}
+	standings.Rows[2].Participant.Kind = models.UpsolvingParticipant
+	scoreboard := makeClicsScoreboard(
+		standings, []int64{10, 20}, models.Contest{}, 60,
+	)
+	if len(scoreboard.Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(scoreboard.Rows))
+	}
+	first := scoreboard.Rows[0]
+	if first.Rank != 1 || first.TeamID != "1" || first.Score.NumSolved != 1 ||
+		first.Score.TotalTime != 41 {
+		t.Fatalf("Invalid row: %+v", first)
+	}
+	if len(first.Problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %d", len(first.Problems))
+	}
+	if problem := first.Problems[0]; problem.ProblemID != "10" ||
+		!problem.Solved || problem.NumJudged != 2 || getValue(problem.Time) != 21 {
+		t.Fatalf("Invalid problem: %+v", problem)
+	}
+	if problem := first.Problems[1]; problem.Solved ||
+		problem.NumPending != 1 || problem.NumJudged != 0 {
+		t.Fatalf("Invalid problem: %+v", problem)
+	}
+	if second := scoreboard.Rows[1]; second.Rank != 1 || second.TeamID != "2" {
+		t.Fatalf("Invalid row: %+v", second)
+	}
+}
+
+func TestClicsContest(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	owner := NewTestUser(e)
+	owner.AddRoles("observe_contest", "create_contest", "update_contest")
+	participant := NewTestUser(e)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(ctx, &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateContestProblem(
+		contest.ID, createContestProblemForm{
+			Code:      getPtr("A"),
+			ProblemID: getPtr(problem.ID),
+		},
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestParticipant, err := e.Client.CreateContestParticipant(
+		contest.ID, createContestParticipantForm{
+			UserID: getPtr(participant.User.ID),
+			Kind:   models.RegularParticipant,
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "g++", ImageID: fakeFile.ID}
+	if err := e.Core.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	brokenCompiler := models.Compiler{Name: "broken", ImageID: fakeFile.ID}
+	if err := brokenCompiler.SetState(models.CompilerState{
+		Status: models.BrokenCompiler,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Compilers.Create(ctx, &brokenCompiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	author, err := e.Core.Users.Get(participant.User.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   author.AccountID,
+		CreateTime: e.Now.Add(-30 * time.Minute).Unix(),
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problems, err := e.Client.ObserveClicsProblems(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(problems) != 1 || problems[0].Label != "A" ||
+		problems[0].Name != "Test problem" {
+		t.Fatalf("Invalid problems: %+v", problems)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     contest.ID,
+		SolutionID:    solution.ID,
+		ParticipantID: contestParticipant.ID,
+		ProblemID:     mustParseClicsID(t, problems[0].ID),
+	}
+	if err := e.Core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.Accepted,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(ctx, solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	clicsContest, err := e.Client.ObserveClicsContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if clicsContest.Name != "Test contest" || clicsContest.Duration != "2:00:00.000" ||
+		getValue(clicsContest.StartTime) != "2020-01-01T09:00:00.000Z" {
+		t.Fatalf("Invalid contest: %+v", clicsContest)
+	}
+	teams, err := e.Client.ObserveClicsTeams(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(teams) != 1 || teams[0].Name != participant.User.Login {
+		t.Fatalf("Invalid teams: %+v", teams)
+	}
+	submissions, err := e.Client.ObserveClicsSubmissions(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(submissions) != 1 || submissions[0].ContestTime != "0:30:00.000" ||
+		submissions[0].TeamID != teams[0].ID {
+		t.Fatalf("Invalid submissions: %+v", submissions)
+	}
+	judgements, err := e.Client.ObserveClicsJudgements(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(judgements) != 1 || getValue(judgements[0].JudgementTypeID) != "AC" {
+		t.Fatalf("Invalid judgements: %+v", judgements)
+	}
+	scoreboard, err := e.Client.ObserveClicsScoreboard(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(scoreboard.Rows) != 1 || scoreboard.Rows[0].Score.NumSolved != 1 ||
+		scoreboard.Rows[0].Score.TotalTime != 30 {
+		t.Fatalf("Invalid scoreboard: %+v", scoreboard)
+	}
+	events := readClicsEventFeed(t, e, contest.ID, "")
+	types := map[string]int{}
+	for _, event := range events {
+		types[event.Type]++
+	}
+	if types["contest"] != 1 || types["problems"] != 1 || types["teams"] != 1 ||
+		types["submissions"] != 1 || types["judgements"] != 1 ||
+		types["languages"] != 1 {
+		t.Fatalf("Invalid events: %v", types)
+	}
+	last := events[len(events)-1]
+	if last.Type != "judgements" {
+		t.Fatalf("Expected judgement, got %q", last.Type)
+	}
+	if events := readClicsEventFeed(
+		t, e, contest.ID, events[len(events)-2].Token,
+	); len(events) != 1 || events[0].Token != last.Token {
+		t.Fatalf("Invalid events: %+v", events)
+	}
+	// Rejudge of solution should be appended to the end of feed.
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.WrongAnswer,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Update(ctx, solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if events := readClicsEventFeed(
+		t, e, contest.ID, last.Token,
+	); len(events) != 1 || events[0].Type != "judgements" {
+		t.Fatalf("Invalid events: %+v", events)
+	}
+	if _, err := e.Client.ObserveClicsEventFeed(
+		ctx, contest.ID, "unknown-1",
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+}
+
+func TestClicsBasicAuth(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	req, err := http.NewRequest(
+		http.MethodGet, e.Client.getURL("/v0/clics/contests"), nil,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	req.Header.Set("X-Solve-Sync", "1")
+	req.SetBasicAuth(user.User.Login, user.Password+"invalid")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	_ = resp.Body.Close()
+	expectStatus(t, http.StatusForbidden, resp.StatusCode)
+	req.SetBasicAuth(user.User.Login, user.Password)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	_ = resp.Body.Close()
+	expectStatus(t, http.StatusOK, resp.StatusCode)
+}
+
+func mustParseClicsID(tb testing.TB, id string) int64 {
+	value, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	return value
+}
+
+func readClicsEventFeed(
+	tb testing.TB, e *TestEnv, id int64, sinceToken string,
+) []ClicsEvent {
+	reader, err := e.Client.ObserveClicsEventFeed(
+		context.Background(), id, sinceToken,
+	)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	defer func() { _ = reader.Close() }()
+	var events []ClicsEvent
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var event ClicsEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			tb.Fatal("Error:", err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		tb.Fatal("Error:", err)
+	}
+	return events
+}
@@ -257,6 +257,11 @@ func (c *Client) CreateProblem(ctx context.Context, form CreateProblemForm) (Pro
 			return Problem{}, err
 		}
 	}
+	if form.Changelog != nil {
+		if err := w.WriteField("changelog", *form.Changelog); err != nil {
+			return Problem{}, err
+		}
+	}
 	if form.PackageFile != nil {
 		if fw, err := w.CreateFormFile("file", form.PackageFile.Name); err != nil {
 			return Problem{}, err
@@ -288,6 +293,11 @@ func (c *Client) UpdateProblem(ctx context.Context, id int64, form UpdateProblem
 			return Problem{}, err
 		}
 	}
+	if form.Changelog != nil {
+		if err := w.WriteField("changelog", *form.Changelog); err != nil {
+			return Problem{}, err
+		}
+	}
 	if form.PackageFile != nil {
 		if fw, err := w.CreateFormFile("file", form.PackageFile.Name); err != nil {
 			return Problem{}, err
@@ -310,6 +320,117 @@ func (c *Client) UpdateProblem(ctx context.Context, id int64, form UpdateProblem
 	return respData, err
 }
 
+func (c *Client) ExportProblem(
+	ctx context.Context, id int64, kind ProblemExportKind,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/problems/%d/export?kind=%s", id, kind), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) ObserveSolutionTestArtifact(
+	ctx context.Context, id int64, test int, kind TestArtifactKind,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/solutions/%d/tests/%d/%s", id, test, kind), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) ObserveProblemRevisions(
+	ctx context.Context, id int64,
+) (ProblemRevisions, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/problems/%d/revisions", id), nil,
+	)
+	if err != nil {
+		return ProblemRevisions{}, err
+	}
+	var respData ProblemRevisions
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) RollbackProblemRevision(
+	ctx context.Context, id int64, revision int64,
+) (ProblemRevision, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/problems/%d/revisions/%d/rollback", id, revision), nil,
+	)
+	if err != nil {
+		return ProblemRevision{}, err
+	}
+	var respData ProblemRevision
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CalibrateProblem(
+	ctx context.Context, id int64, form CalibrateProblemForm,
+) (Problem, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Problem{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/problems/%d/calibrate", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Problem{}, err
+	}
+	var respData Problem
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveProblemCalibration(
+	ctx context.Context, id int64,
+) (ProblemCalibration, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/problems/%d/calibration", id), nil,
+	)
+	if err != nil {
+		return ProblemCalibration{}, err
+	}
+	var respData ProblemCalibration
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ApplyProblemCalibration(
+	ctx context.Context, id int64,
+) (Problem, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/problems/%d/calibration/apply", id), nil,
+	)
+	if err != nil {
+		return Problem{}, err
+	}
+	var respData Problem
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) DeleteProblem(ctx context.Context, id int64) (Problem, error) {
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodDelete, c.getURL("/v0/problems/%d", id), nil,
@@ -322,6 +443,81 @@ func (c *Client) DeleteProblem(ctx context.Context, id int64) (Problem, error) {
 	return respData, err
 }
 
+func (c *Client) CreateTeam(ctx context.Context, form CreateTeamForm) (Team, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Team{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/teams"),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Team{}, err
+	}
+	var respData Team
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteTeam(ctx context.Context, id int64) (Team, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete, c.getURL("/v0/teams/%d", id), nil,
+	)
+	if err != nil {
+		return Team{}, err
+	}
+	var respData Team
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveTeamMembers(ctx context.Context, id int64) (TeamMembers, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/teams/%d/members", id), nil,
+	)
+	if err != nil {
+		return TeamMembers{}, err
+	}
+	var respData TeamMembers
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateTeamMember(
+	ctx context.Context, id int64, form CreateTeamMemberForm,
+) (TeamMember, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return TeamMember{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/teams/%d/members", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return TeamMember{}, err
+	}
+	var respData TeamMember
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) DeleteTeamMember(
+	ctx context.Context, id int64, memberID int64,
+) (TeamMember, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodDelete,
+		c.getURL("/v0/teams/%d/members/%d", id, memberID), nil,
+	)
+	if err != nil {
+		return TeamMember{}, err
+	}
+	var respData TeamMember
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) ObserveRoles(ctx context.Context) (Roles, error) {
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodGet, c.getURL("/v0/roles"), nil,
@@ -365,6 +561,42 @@ func (c *Client) SubmitContestSolution(
 	return respData, err
 }
 
+func (c *Client) StressContestSolution(
+	ctx context.Context, contestID, solutionID int64, form StressSolutionForm,
+) (StressSolution, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return StressSolution{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/solutions/%d/stress", contestID, solutionID),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return StressSolution{}, err
+	}
+	var respData StressSolution
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestSolutionStress(
+	ctx context.Context, contestID, solutionID int64,
+) (StressSolution, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/solutions/%d/stress", contestID, solutionID),
+		nil,
+	)
+	if err != nil {
+		return StressSolution{}, err
+	}
+	var respData StressSolution
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) CreateRole(
 	ctx context.Context, name string,
 ) (Role, error) {
@@ -458,6 +690,345 @@ func (c *Client) ObserveContest(
 	return respData, err
 }
 
+func (c *Client) CloneContest(
+	ctx context.Context, id int64, form CloneContestForm,
+) (Contest, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return Contest{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/clone", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return Contest{}, err
+	}
+	var respData Contest
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) RegisterContest(
+	ctx context.Context, id int64,
+) (ContestParticipant, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/register", id), nil,
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) StartContest(
+	ctx context.Context, id int64,
+) (ContestParticipant, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/start", id), nil,
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) StartVirtualContest(
+	ctx context.Context, id int64,
+) (ContestParticipant, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.getURL("/v0/contests/%d/virtual", id), nil,
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestStandings(
+	ctx context.Context, id int64,
+) (ContestStandings, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/standings", id), nil,
+	)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+	var respData ContestStandings
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ExportContestStandings(
+	ctx context.Context, id int64, kind ContestStandingsExportKind,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/standings/export?kind=%s", id, kind), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) ObserveClicsContest(
+	ctx context.Context, id int64,
+) (ClicsContest, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/clics/contests/%d", id), nil,
+	)
+	if err != nil {
+		return ClicsContest{}, err
+	}
+	var respData ClicsContest
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveClicsProblems(
+	ctx context.Context, id int64,
+) ([]ClicsProblem, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/clics/contests/%d/problems", id), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var respData []ClicsProblem
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveClicsTeams(
+	ctx context.Context, id int64,
+) ([]ClicsTeam, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/clics/contests/%d/teams", id), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var respData []ClicsTeam
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveClicsSubmissions(
+	ctx context.Context, id int64,
+) ([]ClicsSubmission, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/clics/contests/%d/submissions", id), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var respData []ClicsSubmission
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveClicsJudgements(
+	ctx context.Context, id int64,
+) ([]ClicsJudgement, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/clics/contests/%d/judgements", id), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var respData []ClicsJudgement
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveClicsScoreboard(
+	ctx context.Context, id int64,
+) (ClicsScoreboard, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/clics/contests/%d/scoreboard", id), nil,
+	)
+	if err != nil {
+		return ClicsScoreboard{}, err
+	}
+	var respData ClicsScoreboard
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+// ObserveClicsEventFeed returns event feed in NDJSON format.
+func (c *Client) ObserveClicsEventFeed(
+	ctx context.Context, id int64, sinceToken string,
+) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL(
+			"/v0/clics/contests/%d/event-feed?since_token=%s",
+			id, url.QueryEscape(sinceToken),
+		), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.doRequest(req, http.StatusOK, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (c *Client) UnfreezeContestStandings(
+	ctx context.Context, id int64, form UnfreezeContestStandingsForm,
+) (ContestStandingsUnfreeze, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestStandingsUnfreeze{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/standings/unfreeze", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestStandingsUnfreeze{}, err
+	}
+	var respData ContestStandingsUnfreeze
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestClarifications(
+	ctx context.Context, id int64,
+) (ContestClarifications, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/clarifications", id), nil,
+	)
+	if err != nil {
+		return ContestClarifications{}, err
+	}
+	var respData ContestClarifications
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestClarification(
+	ctx context.Context, id int64, form CreateContestClarificationForm,
+) (ContestClarification, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestClarification{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/clarifications", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestClarification{}, err
+	}
+	var respData ContestClarification
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) AnswerContestClarification(
+	ctx context.Context, contestID int64, clarificationID int64,
+	form AnswerContestClarificationForm,
+) (ContestClarification, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestClarification{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL(
+			"/v0/contests/%d/clarifications/%d/answer",
+			contestID, clarificationID,
+		),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestClarification{}, err
+	}
+	var respData ContestClarification
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ReadContestClarifications(
+	ctx context.Context, id int64,
+) (ContestClarifications, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/clarifications/read", id), nil,
+	)
+	if err != nil {
+		return ContestClarifications{}, err
+	}
+	var respData ContestClarifications
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestAnnouncements(
+	ctx context.Context, id int64,
+) (ContestAnnouncements, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.getURL("/v0/contests/%d/announcements", id), nil,
+	)
+	if err != nil {
+		return ContestAnnouncements{}, err
+	}
+	var respData ContestAnnouncements
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
+func (c *Client) CreateContestAnnouncement(
+	ctx context.Context, id int64, form CreateContestAnnouncementForm,
+) (ContestAnnouncement, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestAnnouncement{}, err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost,
+		c.getURL("/v0/contests/%d/announcements", id),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestAnnouncement{}, err
+	}
+	var respData ContestAnnouncement
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
+func (c *Client) ObserveContestNotifications(
+	ctx context.Context, id int64, since int64,
+) (ContestNotifications, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet,
+		c.getURL("/v0/contests/%d/notifications?since=%d", id, since), nil,
+	)
+	if err != nil {
+		return ContestNotifications{}, err
+	}
+	var respData ContestNotifications
+	_, err = c.doRequest(req, http.StatusOK, &respData)
+	return respData, err
+}
+
 func (c *Client) ObserveSettings(ctx context.Context) (Settings, error) {
 	req, err := http.NewRequestWithContext(
 		ctx, http.MethodGet, c.getURL("/v0/settings"), nil,
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -35,10 +36,20 @@ func (v *View) registerCompilerHandlers(g *echo.Group) {
 	)
 }
 
+type CompilerState struct {
+	Status      string `json:"status,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Error       string `json:"error,omitempty"`
+	CompileTime int64  `json:"compile_time,omitempty"`
+	ExecuteTime int64  `json:"execute_time,omitempty"`
+	TestTime    int64  `json:"test_time,omitempty"`
+}
+
 type Compiler struct {
-	ID     int64  `json:"id"`
-	Name   string `json:"name"`
-	Config JSON   `json:"config"`
+	ID     int64          `json:"id"`
+	Name   string         `json:"name"`
+	Config JSON           `json:"config"`
+	State  *CompilerState `json:"state,omitempty"`
 }
 
 type Compilers struct {
@@ -61,6 +72,12 @@ func (v *View) ObserveCompilers(c echo.Context) error {
 	var resp Compilers
 	for _, compiler := range compilers {
 		permissions := v.getCompilerPermissions(accountCtx, compiler)
+		// Broken compilers are visible only for compiler managers,
+		// so they are hidden from submission forms.
+		if compiler.IsBroken() &&
+			!permissions.HasPermission(models.UpdateCompilerRole) {
+			continue
+		}
 		if permissions.HasPermission(models.ObserveCompilerRole) {
 			resp.Compilers = append(resp.Compilers, makeCompiler(compiler))
 		}
@@ -188,7 +205,15 @@ func (v *View) createCompiler(c echo.Context) error {
 			return err
 		}
 		compiler.ImageID = file.ID
-		return v.core.Compilers.Create(ctx, &compiler)
+		if err := compiler.SetState(models.CompilerState{
+			Status: models.PendingCompiler,
+		}); err != nil {
+			return err
+		}
+		if err := v.core.Compilers.Create(ctx, &compiler); err != nil {
+			return err
+		}
+		return v.createCheckCompilerTask(ctx, compiler)
 	}, sqlRepeatableRead); err != nil {
 		return err
 	}
@@ -223,13 +248,34 @@ func (v *View) updateCompiler(c echo.Context) error {
 			}
 			compiler.ImageID = formFile.ID
 		}
-		return v.core.Compilers.Update(ctx, compiler)
+		if err := compiler.SetState(models.CompilerState{
+			Status: models.PendingCompiler,
+		}); err != nil {
+			return err
+		}
+		if err := v.core.Compilers.Update(ctx, compiler); err != nil {
+			return err
+		}
+		return v.createCheckCompilerTask(ctx, compiler)
 	}, sqlRepeatableRead); err != nil {
 		return err
 	}
 	return c.JSON(http.StatusOK, makeCompiler(compiler))
 }
 
+// createCheckCompilerTask creates task for compiler self-test.
+func (v *View) createCheckCompilerTask(
+	ctx context.Context, compiler models.Compiler,
+) error {
+	task := models.Task{}
+	if err := task.SetConfig(models.CheckCompilerTaskConfig{
+		CompilerID: compiler.ID,
+	}); err != nil {
+		return err
+	}
+	return v.core.Tasks.Create(ctx, &task)
+}
+
 func (v *View) deleteCompiler(c echo.Context) error {
 	compiler, ok := c.Get(compilerKey).(models.Compiler)
 	if !ok {
@@ -242,11 +288,22 @@ func (v *View) deleteCompiler(c echo.Context) error {
 }
 
 func makeCompiler(compiler models.Compiler) Compiler {
-	return Compiler{
+	resp := Compiler{
 		ID:     compiler.ID,
 		Name:   compiler.Name,
 		Config: JSON{compiler.Config},
 	}
+	if state, err := compiler.GetState(); err == nil && state.Status != "" {
+		resp.State = &CompilerState{
+			Status:      string(state.Status),
+			Version:     state.Version,
+			Error:       state.Error,
+			CompileTime: state.CompileTime,
+			ExecuteTime: state.ExecuteTime,
+			TestTime:    state.TestTime,
+		}
+	}
+	return resp
 }
 
 func (v *View) extractCompiler(next echo.HandlerFunc) echo.HandlerFunc {
@@ -290,6 +347,26 @@ func (v *View) getCompilerPermissions(
 	return permissions
 }
 
+// parseCompilerLimits parses compiler limits overrides from form field.
+func parseCompilerLimits(
+	c echo.Context, value JSON, name string, errors errorFields,
+) models.CompilerLimitsOverrides {
+	var limits models.CompilerLimitsOverrides
+	if err := json.Unmarshal(value.JSON, &limits); err != nil {
+		errors[name] = errorField{Message: localize(c, "Invalid compiler limits.")}
+		return nil
+	}
+	for _, config := range limits {
+		if config.TimeMultiplier < 0 || config.TimeMultiplier > 100 ||
+			config.MemoryMultiplier < 0 || config.MemoryMultiplier > 100 ||
+			config.TimeOverhead < 0 || config.MemoryOverhead < 0 {
+			errors[name] = errorField{Message: localize(c, "Invalid compiler limits.")}
+			return nil
+		}
+	}
+	return limits
+}
+
 func compilerGreater(l, r Compiler) bool {
 	return l.ID > r.ID
 }
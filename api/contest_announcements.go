@@ -0,0 +1,313 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+func (v *View) registerContestAnnouncementHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/announcements", v.observeContestAnnouncements,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(models.ObserveContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/announcements", v.createContestAnnouncement,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.CreateContestAnnouncementRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/notifications", v.observeContestNotifications,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.ObserveContestRole),
+	)
+}
+
+type ContestAnnouncement struct {
+	ID int64 `json:"id"`
+	// ProblemCode contains code of problem or empty for general
+	// announcement.
+	ProblemCode string `json:"problem_code,omitempty"`
+	CreateTime  int64  `json:"create_time"`
+	Text        string `json:"text"`
+}
+
+type ContestAnnouncements struct {
+	Announcements []ContestAnnouncement `json:"announcements"`
+}
+
+func contestAnnouncementGreater(l, r ContestAnnouncement) bool {
+	return l.ID > r.ID
+}
+
+func (v *View) makeContestAnnouncement(
+	announcement models.ContestAnnouncement,
+) ContestAnnouncement {
+	resp := ContestAnnouncement{
+		ID:         announcement.ID,
+		CreateTime: announcement.CreateTime,
+		Text:       announcement.Text,
+	}
+	if announcement.ProblemID != 0 {
+		problem, err := v.core.ContestProblems.Get(int64(announcement.ProblemID))
+		if err == nil {
+			resp.ProblemCode = problem.Code
+		}
+	}
+	return resp
+}
+
+func (v *View) observeContestAnnouncements(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestAnnouncements); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	announcements, err := v.core.ContestAnnouncements.FindByContest(
+		contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	resp := ContestAnnouncements{Announcements: []ContestAnnouncement{}}
+	for _, announcement := range announcements {
+		resp.Announcements = append(
+			resp.Announcements, v.makeContestAnnouncement(announcement),
+		)
+	}
+	sortFunc(resp.Announcements, contestAnnouncementGreater)
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateContestAnnouncementForm struct {
+	// ProblemCode contains code of problem or empty for general
+	// announcement.
+	ProblemCode string `json:"problem_code" form:"problem_code"`
+	Text        string `json:"text" form:"text"`
+}
+
+func (f *CreateContestAnnouncementForm) Update(
+	c echo.Context, problems []models.ContestProblem,
+	announcement *models.ContestAnnouncement,
+) error {
+	errors := errorFields{}
+	if len(f.ProblemCode) > 0 {
+		found := false
+		for _, problem := range problems {
+			if problem.Code == f.ProblemCode {
+				announcement.ProblemID = models.NInt64(problem.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors["problem_code"] = errorField{
+				Message: localize(c, "Problem not found."),
+			}
+		}
+	}
+	if len(f.Text) < 1 {
+		errors["text"] = errorField{
+			Message: localize(c, "Text is too short."),
+		}
+	} else if len(f.Text) > 4096 {
+		errors["text"] = errorField{
+			Message: localize(c, "Text is too long."),
+		}
+	}
+	announcement.Text = f.Text
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) createContestAnnouncement(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CreateContestAnnouncementForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid form."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	problems, err := v.core.ContestProblems.FindByContest(contestCtx.Contest.ID)
+	if err != nil {
+		return err
+	}
+	announcement := models.ContestAnnouncement{
+		ContestID:  contestCtx.Contest.ID,
+		CreateTime: contestCtx.Now.Unix(),
+	}
+	if err := form.Update(c, problems, &announcement); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.ContestAnnouncements.Create(
+			ctx, &announcement,
+		); err != nil {
+			return err
+		}
+		notification := models.ContestNotification{
+			ContestID:  announcement.ContestID,
+			Kind:       models.AnnouncementNotification,
+			TargetID:   announcement.ID,
+			CreateTime: announcement.CreateTime,
+		}
+		return v.core.ContestNotifications.Create(ctx, &notification)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, v.makeContestAnnouncement(announcement))
+}
+
+type ContestNotification struct {
+	ID            int64                   `json:"id"`
+	Kind          models.NotificationKind `json:"kind"`
+	CreateTime    int64                   `json:"create_time"`
+	Announcement  *ContestAnnouncement    `json:"announcement,omitempty"`
+	Clarification *ContestClarification   `json:"clarification,omitempty"`
+	Solution      *ContestSolution        `json:"solution,omitempty"`
+}
+
+type ContestNotifications struct {
+	Notifications []ContestNotification `json:"notifications"`
+}
+
+type contestNotificationsFilter struct {
+	// Since contains ID of last received notification.
+	Since int64 `query:"since"`
+}
+
+func contestNotificationLess(l, r ContestNotification) bool {
+	return l.ID < r.ID
+}
+
+// isContestNotificationVisible returns true if notification is broadcasted
+// or addressed to one of account participants.
+func isContestNotificationVisible(
+	ctx *managers.ContestContext, notification models.ContestNotification,
+) bool {
+	if notification.ParticipantID == 0 {
+		return true
+	}
+	for _, participant := range ctx.Participants {
+		if participant.ID == int64(notification.ParticipantID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *View) makeContestNotification(
+	c echo.Context, notification models.ContestNotification,
+) (ContestNotification, bool) {
+	resp := ContestNotification{
+		ID:         notification.ID,
+		Kind:       notification.Kind,
+		CreateTime: notification.CreateTime,
+	}
+	switch notification.Kind {
+	case models.AnnouncementNotification:
+		announcement, err := v.core.ContestAnnouncements.Get(notification.TargetID)
+		if err != nil {
+			return resp, false
+		}
+		resp.Announcement = getPtr(v.makeContestAnnouncement(announcement))
+	case models.ClarificationNotification:
+		clarification, err := v.core.ContestClarifications.Get(notification.TargetID)
+		if err != nil {
+			return resp, false
+		}
+		resp.Clarification = getPtr(v.makeContestClarification(
+			clarification, notification.ParticipantID != 0,
+		))
+	case models.SolutionNotification:
+		solution, err := v.core.ContestSolutions.Get(notification.TargetID)
+		if err != nil {
+			return resp, false
+		}
+		resp.Solution = getPtr(v.makeContestSolution(c, solution, false))
+	default:
+		return resp, false
+	}
+	return resp, true
+}
+
+// observeContestNotifications returns notifications of current account
+// that were created after specified notification.
+func (v *View) observeContestNotifications(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var filter contestNotificationsFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid filter."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestNotifications); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestAnnouncements); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestClarifications); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.Solutions); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestSolutions); err != nil {
+		return err
+	}
+	notifications, err := v.core.ContestNotifications.FindByContest(
+		contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	resp := ContestNotifications{Notifications: []ContestNotification{}}
+	for _, notification := range notifications {
+		if notification.ID <= filter.Since {
+			continue
+		}
+		if !isContestNotificationVisible(contestCtx, notification) {
+			continue
+		}
+		if notificationResp, ok := v.makeContestNotification(
+			c, notification,
+		); ok {
+			resp.Notifications = append(resp.Notifications, notificationResp)
+		}
+	}
+	sortFunc(resp.Notifications, contestNotificationLess)
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestContestNotifications(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	owner := NewTestUser(e)
+	owner.AddRoles("observe_contest", "create_contest", "update_contest")
+	participant := NewTestUser(e)
+	other := NewTestUser(e)
+	var contest Contest
+	func() {
+		owner.LoginClient()
+		defer owner.LogoutClient()
+		contestForm := createContestForm{
+			Title:     getPtr("Test contest"),
+			BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+			Duration:  getPtr(7200),
+		}
+		var err error
+		if contest, err = e.Client.CreateContest(contestForm); err != nil {
+			t.Fatal("Error:", err)
+		}
+		for _, user := range []*TestUser{participant, other} {
+			if _, err := e.Client.CreateContestParticipant(
+				contest.ID, createContestParticipantForm{
+					UserID: getPtr(user.User.ID),
+					Kind:   models.RegularParticipant,
+				},
+			); err != nil {
+				t.Fatal("Error:", err)
+			}
+		}
+		if _, err := e.Client.CreateContestAnnouncement(
+			ctx, contest.ID, CreateContestAnnouncementForm{
+				ProblemCode: "Z",
+				Text:        "Statement is fixed",
+			},
+		); err == nil {
+			t.Fatal("Expected error")
+		}
+		if _, err := e.Client.CreateContestAnnouncement(
+			ctx, contest.ID, CreateContestAnnouncementForm{
+				Text: "Statement is fixed",
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	var clarification ContestClarification
+	func() {
+		participant.LoginClient()
+		defer participant.LogoutClient()
+		if _, err := e.Client.CreateContestAnnouncement(
+			ctx, contest.ID, CreateContestAnnouncementForm{Text: "Test"},
+		); err == nil {
+			t.Fatal("Expected error")
+		}
+		var err error
+		if clarification, err = e.Client.CreateContestClarification(
+			ctx, contest.ID, CreateContestClarificationForm{
+				Question: "What is the answer?",
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	func() {
+		owner.LoginClient()
+		defer owner.LogoutClient()
+		if _, err := e.Client.AnswerContestClarification(
+			ctx, contest.ID, clarification.ID,
+			AnswerContestClarificationForm{Answer: "42"},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	func() {
+		participant.LoginClient()
+		defer participant.LogoutClient()
+		announcements, err := e.Client.ObserveContestAnnouncements(ctx, contest.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if len(announcements.Announcements) != 1 {
+			t.Fatalf("Invalid announcements: %+v", announcements)
+		}
+		resp, err := e.Client.ObserveContestNotifications(ctx, contest.ID, 0)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if len(resp.Notifications) != 2 ||
+			resp.Notifications[0].Kind != models.AnnouncementNotification ||
+			resp.Notifications[1].Kind != models.ClarificationNotification ||
+			resp.Notifications[1].Clarification == nil ||
+			resp.Notifications[1].Clarification.Answer != "42" {
+			t.Fatalf("Invalid notifications: %+v", resp)
+		}
+		since := resp.Notifications[1].ID
+		if resp, err := e.Client.ObserveContestNotifications(
+			ctx, contest.ID, since,
+		); err != nil {
+			t.Fatal("Error:", err)
+		} else if len(resp.Notifications) != 0 {
+			t.Fatalf("Invalid notifications: %+v", resp)
+		}
+	}()
+	func() {
+		other.LoginClient()
+		defer other.LogoutClient()
+		resp, err := e.Client.ObserveContestNotifications(ctx, contest.ID, 0)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if len(resp.Notifications) != 1 ||
+			resp.Notifications[0].Announcement == nil {
+			t.Fatalf("Invalid notifications: %+v", resp)
+		}
+	}()
+}
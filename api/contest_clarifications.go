@@ -0,0 +1,483 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+func (v *View) registerContestClarificationHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/contests/:contest/clarifications", v.observeContestClarifications,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.ObserveContestClarificationsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/clarifications", v.createContestClarification,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.CreateContestClarificationRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/clarifications/read", v.readContestClarifications,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.ObserveContestClarificationsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/clarifications/:clarification/answer",
+		v.answerContestClarification,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.extractContestClarification,
+		v.requirePermission(models.UpdateContestClarificationRole),
+	)
+}
+
+type ContestClarification struct {
+	ID int64 `json:"id"`
+	// Participant contains participant that asked question.
+	//
+	// Participant is hidden for public clarifications of other
+	// participants.
+	Participant *ContestParticipant `json:"participant,omitempty"`
+	// ProblemCode contains code of problem or empty for general question.
+	ProblemCode string `json:"problem_code,omitempty"`
+	Public      bool   `json:"public"`
+	CreateTime  int64  `json:"create_time"`
+	Question    string `json:"question"`
+	Answer      string `json:"answer,omitempty"`
+	AnswerTime  int64  `json:"answer_time,omitempty"`
+	// Unread means that clarification was changed after last read.
+	Unread bool `json:"unread,omitempty"`
+}
+
+type ContestClarifications struct {
+	Clarifications []ContestClarification `json:"clarifications"`
+	// Unread contains amount of unread clarifications.
+	Unread int `json:"unread"`
+}
+
+func contestClarificationGreater(l, r ContestClarification) bool {
+	return l.ID > r.ID
+}
+
+// isOwnContestClarification returns true if clarification was asked
+// by one of account participants.
+func isOwnContestClarification(
+	ctx *managers.ContestContext, clarification models.ContestClarification,
+) bool {
+	if clarification.ParticipantID == 0 {
+		return false
+	}
+	for _, participant := range ctx.Participants {
+		if participant.ID == int64(clarification.ParticipantID) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnreadContestClarification returns true if clarification was changed
+// after specified read time.
+//
+// Jury is interested in new questions, participants are interested
+// in new answers.
+func isUnreadContestClarification(
+	clarification models.ContestClarification, readTime int64, jury bool,
+) bool {
+	if jury {
+		return clarification.AnswerTime == 0 && clarification.CreateTime > readTime
+	}
+	return clarification.AnswerTime != 0 && int64(clarification.AnswerTime) > readTime
+}
+
+func (v *View) makeContestClarification(
+	clarification models.ContestClarification,
+	showParticipant bool,
+) ContestClarification {
+	resp := ContestClarification{
+		ID:         clarification.ID,
+		Public:     clarification.Kind == models.PublicClarification,
+		CreateTime: clarification.CreateTime,
+		Question:   clarification.Question,
+		Answer:     string(clarification.Answer),
+		AnswerTime: int64(clarification.AnswerTime),
+	}
+	if showParticipant && clarification.ParticipantID != 0 {
+		participant, err := v.core.ContestParticipants.Get(
+			int64(clarification.ParticipantID),
+		)
+		if err == nil {
+			resp.Participant = getPtr(makeContestParticipant(participant, v.core))
+		}
+	}
+	if clarification.ProblemID != 0 {
+		problem, err := v.core.ContestProblems.Get(int64(clarification.ProblemID))
+		if err == nil {
+			resp.ProblemCode = problem.Code
+		}
+	}
+	return resp
+}
+
+func (v *View) getContestClarificationsReadTime(
+	ctx *managers.ContestContext,
+) (int64, error) {
+	if ctx.Account == nil {
+		return 0, nil
+	}
+	read, err := v.core.ContestClarificationReads.GetByContestAccount(
+		ctx.Contest.ID, ctx.Account.ID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return read.ReadTime, nil
+}
+
+func (v *View) buildContestClarifications(
+	ctx *managers.ContestContext,
+) (ContestClarifications, error) {
+	resp := ContestClarifications{
+		Clarifications: []ContestClarification{},
+	}
+	clarifications, err := v.core.ContestClarifications.FindByContest(
+		ctx.Contest.ID,
+	)
+	if err != nil {
+		return resp, err
+	}
+	readTime, err := v.getContestClarificationsReadTime(ctx)
+	if err != nil {
+		return resp, err
+	}
+	jury := ctx.HasPermission(models.UpdateContestClarificationRole)
+	for _, clarification := range clarifications {
+		own := isOwnContestClarification(ctx, clarification)
+		public := clarification.Kind == models.PublicClarification
+		if !jury && !own && !public {
+			continue
+		}
+		clarificationResp := v.makeContestClarification(
+			clarification, jury || own,
+		)
+		if isUnreadContestClarification(clarification, readTime, jury) {
+			clarificationResp.Unread = true
+			resp.Unread++
+		}
+		resp.Clarifications = append(resp.Clarifications, clarificationResp)
+	}
+	sortFunc(resp.Clarifications, contestClarificationGreater)
+	return resp, nil
+}
+
+func (v *View) observeContestClarifications(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	if err := syncStore(c, v.core.ContestClarifications); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestClarificationReads); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	resp, err := v.buildContestClarifications(contestCtx)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateContestClarificationForm struct {
+	// ProblemCode contains code of problem or empty for general question.
+	ProblemCode string `json:"problem_code" form:"problem_code"`
+	Question    string `json:"question" form:"question"`
+}
+
+func (f *CreateContestClarificationForm) Update(
+	c echo.Context, problems []models.ContestProblem,
+	clarification *models.ContestClarification,
+) error {
+	errors := errorFields{}
+	if len(f.ProblemCode) > 0 {
+		found := false
+		for _, problem := range problems {
+			if problem.Code == f.ProblemCode {
+				clarification.ProblemID = models.NInt64(problem.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors["problem_code"] = errorField{
+				Message: localize(c, "Problem not found."),
+			}
+		}
+	}
+	if len(f.Question) < 1 {
+		errors["question"] = errorField{
+			Message: localize(c, "Question is too short."),
+		}
+	} else if len(f.Question) > 1024 {
+		errors["question"] = errorField{
+			Message: localize(c, "Question is too long."),
+		}
+	}
+	clarification.Question = f.Question
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) createContestClarification(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	// Clarifications created by jury are broadcasted to everyone.
+	jury := contestCtx.HasPermission(models.UpdateContestClarificationRole)
+	clarification := models.ContestClarification{
+		ContestID:  contestCtx.Contest.ID,
+		Kind:       models.PrivateClarification,
+		CreateTime: contestCtx.Now.Unix(),
+	}
+	if jury {
+		clarification.Kind = models.PublicClarification
+		clarification.AnswerTime = models.NInt64(clarification.CreateTime)
+	} else {
+		participant := contestCtx.GetEffectiveParticipant()
+		if participant == nil || participant.ID == 0 {
+			return errorResponse{
+				Code:    http.StatusForbidden,
+				Message: localize(c, "Participant not found."),
+			}
+		}
+		if !contestCtx.HasEffectivePermission(models.CreateContestClarificationRole) {
+			return errorResponse{
+				Code:               http.StatusForbidden,
+				Message:            localize(c, "Account missing permissions."),
+				MissingPermissions: []string{models.CreateContestClarificationRole},
+			}
+		}
+		clarification.ParticipantID = models.NInt64(participant.ID)
+	}
+	var form CreateContestClarificationForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid form."),
+		}
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	problems, err := v.core.ContestProblems.FindByContest(contestCtx.Contest.ID)
+	if err != nil {
+		return err
+	}
+	if err := form.Update(c, problems, &clarification); err != nil {
+		return err
+	}
+	if err := v.core.ContestClarifications.Create(
+		getContext(c), &clarification,
+	); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusCreated,
+		v.makeContestClarification(clarification, true),
+	)
+}
+
+type AnswerContestClarificationForm struct {
+	Answer string `json:"answer" form:"answer"`
+	// Public means that answer should be broadcasted to everyone.
+	Public bool `json:"public" form:"public"`
+}
+
+func (f *AnswerContestClarificationForm) Update(
+	c echo.Context, clarification *models.ContestClarification,
+) error {
+	errors := errorFields{}
+	if len(f.Answer) < 1 {
+		errors["answer"] = errorField{
+			Message: localize(c, "Answer is too short."),
+		}
+	} else if len(f.Answer) > 4096 {
+		errors["answer"] = errorField{
+			Message: localize(c, "Answer is too long."),
+		}
+	}
+	clarification.Answer = models.NString(f.Answer)
+	clarification.Kind = models.PrivateClarification
+	if f.Public {
+		clarification.Kind = models.PublicClarification
+	}
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+func (v *View) answerContestClarification(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	clarification, ok := c.Get(contestClarificationKey).(models.ContestClarification)
+	if !ok {
+		return fmt.Errorf("clarification not extracted")
+	}
+	var form AnswerContestClarificationForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Update(c, &clarification); err != nil {
+		return err
+	}
+	clarification.AnswerTime = models.NInt64(contestCtx.Now.Unix())
+	notification := models.ContestNotification{
+		ContestID:  clarification.ContestID,
+		Kind:       models.ClarificationNotification,
+		TargetID:   clarification.ID,
+		CreateTime: int64(clarification.AnswerTime),
+	}
+	// Private answers are sent only to participant that asked question.
+	if clarification.Kind != models.PublicClarification {
+		notification.ParticipantID = clarification.ParticipantID
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.ContestClarifications.Update(
+			ctx, clarification,
+		); err != nil {
+			return err
+		}
+		return v.core.ContestNotifications.Create(ctx, &notification)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusOK,
+		v.makeContestClarification(clarification, true),
+	)
+}
+
+// readContestClarifications marks all clarifications of contest as read
+// for current account.
+func (v *View) readContestClarifications(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	account := contestCtx.Account
+	if account == nil {
+		return fmt.Errorf("account not extracted")
+	}
+	if err := syncStore(c, v.core.ContestClarifications); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestClarificationReads); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	read, err := v.core.ContestClarificationReads.GetByContestAccount(
+		contestCtx.Contest.ID, account.ID,
+	)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			return err
+		}
+		read = models.ContestClarificationRead{
+			ContestID: contestCtx.Contest.ID,
+			AccountID: account.ID,
+			ReadTime:  contestCtx.Now.Unix(),
+		}
+		if err := v.core.ContestClarificationReads.Create(
+			getContext(c), &read,
+		); err != nil {
+			return err
+		}
+	} else {
+		read.ReadTime = contestCtx.Now.Unix()
+		if err := v.core.ContestClarificationReads.Update(
+			getContext(c), read,
+		); err != nil {
+			return err
+		}
+	}
+	if err := syncStore(c, v.core.ContestClarificationReads); err != nil {
+		return err
+	}
+	resp, err := v.buildContestClarifications(contestCtx)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) extractContestClarification(
+	next echo.HandlerFunc,
+) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("clarification"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Invalid clarification ID."),
+			}
+		}
+		if err := syncStore(c, v.core.ContestClarifications); err != nil {
+			return err
+		}
+		clarification, err := v.core.ContestClarifications.Get(id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:    http.StatusNotFound,
+					Message: localize(c, "Clarification not found."),
+				}
+			}
+			return err
+		}
+		contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+		if !ok {
+			return fmt.Errorf("contest not extracted")
+		}
+		if contestCtx.Contest.ID != clarification.ContestID {
+			return errorResponse{
+				Code:    http.StatusNotFound,
+				Message: localize(c, "Clarification not found."),
+			}
+		}
+		c.Set(contestClarificationKey, clarification)
+		return next(c)
+	}
+}
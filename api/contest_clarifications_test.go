@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestContestClarifications(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	owner := NewTestUser(e)
+	owner.AddRoles("observe_contest", "create_contest", "update_contest")
+	participant := NewTestUser(e)
+	other := NewTestUser(e)
+	var contest Contest
+	func() {
+		owner.LoginClient()
+		defer owner.LogoutClient()
+		contestForm := createContestForm{
+			Title:     getPtr("Test contest"),
+			BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+			Duration:  getPtr(7200),
+		}
+		var err error
+		if contest, err = e.Client.CreateContest(contestForm); err != nil {
+			t.Fatal("Error:", err)
+		}
+		for _, user := range []*TestUser{participant, other} {
+			if _, err := e.Client.CreateContestParticipant(
+				contest.ID, createContestParticipantForm{
+					UserID: getPtr(user.User.ID),
+					Kind:   models.RegularParticipant,
+				},
+			); err != nil {
+				t.Fatal("Error:", err)
+			}
+		}
+	}()
+	var clarification ContestClarification
+	func() {
+		participant.LoginClient()
+		defer participant.LogoutClient()
+		if _, err := e.Client.CreateContestClarification(
+			ctx, contest.ID, CreateContestClarificationForm{},
+		); err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+		}
+		var err error
+		if clarification, err = e.Client.CreateContestClarification(
+			ctx, contest.ID, CreateContestClarificationForm{
+				Question: "Is it allowed to use C++20?",
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	observeUnread := func(expected int) ContestClarifications {
+		resp, err := e.Client.ObserveContestClarifications(ctx, contest.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if resp.Unread != expected {
+			t.Fatalf("Expected %d unread, got %+v", expected, resp)
+		}
+		return resp
+	}
+	func() {
+		other.LoginClient()
+		defer other.LogoutClient()
+		if resp := observeUnread(0); len(resp.Clarifications) != 0 {
+			t.Fatalf("Private clarification is visible: %+v", resp)
+		}
+	}()
+	e.Now = e.Now.Add(time.Minute)
+	func() {
+		owner.LoginClient()
+		defer owner.LogoutClient()
+		observeUnread(1)
+		if _, err := e.Client.ReadContestClarifications(ctx, contest.ID); err != nil {
+			t.Fatal("Error:", err)
+		}
+		observeUnread(0)
+		if _, err := e.Client.AnswerContestClarification(
+			ctx, contest.ID, clarification.ID,
+			AnswerContestClarificationForm{Answer: "Yes", Public: true},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	e.Now = e.Now.Add(time.Minute)
+	func() {
+		participant.LoginClient()
+		defer participant.LogoutClient()
+		resp := observeUnread(1)
+		if len(resp.Clarifications) != 1 ||
+			resp.Clarifications[0].Answer != "Yes" ||
+			resp.Clarifications[0].Participant == nil {
+			t.Fatalf("Invalid clarifications: %+v", resp)
+		}
+		if _, err := e.Client.ReadContestClarifications(ctx, contest.ID); err != nil {
+			t.Fatal("Error:", err)
+		}
+		observeUnread(0)
+	}()
+	func() {
+		other.LoginClient()
+		defer other.LogoutClient()
+		resp := observeUnread(1)
+		if len(resp.Clarifications) != 1 ||
+			resp.Clarifications[0].Participant != nil {
+			t.Fatalf("Invalid clarifications: %+v", resp)
+		}
+		if _, err := e.Client.AnswerContestClarification(
+			ctx, contest.ID, clarification.ID,
+			AnswerContestClarificationForm{Answer: "No"},
+		); err == nil {
+			t.Fatal("Expected error")
+		}
+	}()
+	e.Now = e.Now.Add(time.Minute)
+	func() {
+		owner.LoginClient()
+		defer owner.LogoutClient()
+		broadcast, err := e.Client.CreateContestClarification(
+			ctx, contest.ID, CreateContestClarificationForm{
+				Question: "Problem A statement is fixed.",
+			},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if !broadcast.Public || broadcast.Participant != nil {
+			t.Fatalf("Invalid clarification: %+v", broadcast)
+		}
+	}()
+	func() {
+		participant.LoginClient()
+		defer participant.LogoutClient()
+		resp := observeUnread(1)
+		if len(resp.Clarifications) != 2 ||
+			resp.Clarifications[0].Question != "Problem A statement is fixed." ||
+			resp.Clarifications[0].Participant != nil {
+			t.Fatalf("Invalid clarifications: %+v", resp)
+		}
+	}()
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+func (v *View) registerContestCloneHandlers(g *echo.Group) {
+	g.POST(
+		"/v0/contests/:contest/clone", v.cloneContest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.UpdateContestRole, models.CreateContestRole),
+	)
+}
+
+type CloneContestForm struct {
+	// Title contains title of new contest or empty for title of
+	// cloned contest.
+	Title *string `json:"title" form:"title"`
+	// BeginTime contains begin time of new contest.
+	//
+	// All times of contest are relative to begin time, so they are
+	// shifted with begin time. Empty begin time means that new contest
+	// is not planned.
+	BeginTime *NInt64 `json:"begin_time" form:"begin_time"`
+	// Template means that new contest is template.
+	Template *bool `json:"template" form:"template"`
+	// CopyParticipants means that regular participants should be copied.
+	CopyParticipants bool `json:"copy_participants" form:"copy_participants"`
+	// CopyManagers means that managers should be copied.
+	CopyManagers bool `json:"copy_managers" form:"copy_managers"`
+	// CopyRevisions means that pinned problem revisions should be
+	// copied. Otherwise problems of new contest use current revisions.
+	CopyRevisions bool `json:"copy_revisions" form:"copy_revisions"`
+	// ScopeID contains ID of scope whose users should be added to new
+	// contest as regular participants.
+	ScopeID *int64 `json:"scope_id" form:"scope_id"`
+}
+
+// Update prepares clone of contest.
+//
+// State of contest such as system testing and unfrozen cells of
+// standings is not copied.
+func (f *CloneContestForm) Update(
+	c echo.Context, contest *models.Contest,
+) error {
+	config, err := contest.GetConfig()
+	if err != nil {
+		return err
+	}
+	config.BeginTime = 0
+	config.SystemTestTime = 0
+	config.Unfrozen = false
+	config.UnfrozenCells = nil
+	config.Template = false
+	if err := contest.SetConfig(config); err != nil {
+		return err
+	}
+	form := updateContestForm{
+		Title:     f.Title,
+		BeginTime: f.BeginTime,
+		Template:  f.Template,
+	}
+	return form.Update(c, contest)
+}
+
+// isClonedParticipant returns true if participant should be copied.
+func (f *CloneContestForm) isClonedParticipant(
+	participant models.ContestParticipant,
+) bool {
+	switch participant.Kind {
+	case models.RegularParticipant:
+		return f.CopyParticipants
+	case models.ManagerParticipant:
+		return f.CopyManagers
+	default:
+		return false
+	}
+}
+
+// updateProblem prepares clone of contest problem.
+func (f *CloneContestForm) updateProblem(problem *models.ContestProblem) error {
+	if f.CopyRevisions {
+		return nil
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	config.RevisionID = 0
+	return problem.SetConfig(config)
+}
+
+func (v *View) cloneContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form CloneContestForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid form."),
+		}
+	}
+	contest := contestCtx.Contest.Clone()
+	contest.ID = 0
+	contest.OwnerID = 0
+	if account := contestCtx.Account; account != nil {
+		contest.OwnerID = NInt64(account.ID)
+	}
+	if err := form.Update(c, &contest); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestProblems); err != nil {
+		return err
+	}
+	problems, err := v.core.ContestProblems.FindByContest(contestCtx.Contest.ID)
+	if err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ContestParticipants); err != nil {
+		return err
+	}
+	participants, err := v.core.ContestParticipants.FindByContest(
+		contestCtx.Contest.ID,
+	)
+	if err != nil {
+		return err
+	}
+	var scopeUsers []models.ScopeUser
+	if form.ScopeID != nil {
+		users, err := v.getCloneScopeUsers(c, contestCtx, *form.ScopeID)
+		if err != nil {
+			return err
+		}
+		scopeUsers = users
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.Contests.Create(ctx, &contest); err != nil {
+			return err
+		}
+		for _, problem := range problems {
+			problem.ID = 0
+			problem.ContestID = contest.ID
+			if err := form.updateProblem(&problem); err != nil {
+				return err
+			}
+			if err := v.core.ContestProblems.Create(ctx, &problem); err != nil {
+				return err
+			}
+		}
+		regulars := map[int64]struct{}{}
+		for _, participant := range participants {
+			if !form.isClonedParticipant(participant) {
+				continue
+			}
+			if participant.Kind == models.RegularParticipant {
+				regulars[participant.AccountID] = struct{}{}
+			}
+			// Personal begin time of participant is not copied.
+			participant.ID = 0
+			participant.ContestID = contest.ID
+			participant.Config = nil
+			if err := v.core.ContestParticipants.Create(
+				ctx, &participant,
+			); err != nil {
+				return err
+			}
+		}
+		for _, user := range scopeUsers {
+			if _, ok := regulars[user.AccountID]; ok {
+				continue
+			}
+			participant := models.ContestParticipant{
+				ContestID: contest.ID,
+				AccountID: user.AccountID,
+				Kind:      models.RegularParticipant,
+			}
+			if err := v.core.ContestParticipants.Create(
+				ctx, &participant,
+			); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusCreated, makeContest(contest, contestCtx.AccountContext, nil),
+	)
+}
+
+// getCloneScopeUsers returns users of scope that should be added to
+// cloned contest.
+func (v *View) getCloneScopeUsers(
+	c echo.Context, contestCtx *managers.ContestContext, scopeID int64,
+) ([]models.ScopeUser, error) {
+	if err := syncStore(c, v.core.Scopes); err != nil {
+		return nil, err
+	}
+	scope, err := v.core.Scopes.Get(scopeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorResponse{
+				Code: http.StatusBadRequest,
+				Message: localize(
+					c, "Scope {id} does not exists.",
+					replaceField("id", scopeID),
+				),
+			}
+		}
+		return nil, err
+	}
+	permissions := v.getScopePermissions(contestCtx.AccountContext, scope)
+	if !permissions.HasPermission(models.ObserveScopeRole) {
+		return nil, errorResponse{
+			Code:               http.StatusForbidden,
+			Message:            localize(c, "Account missing permissions."),
+			MissingPermissions: []string{models.ObserveScopeRole},
+		}
+	}
+	if err := syncStore(c, v.core.ScopeUsers); err != nil {
+		return nil, err
+	}
+	return v.core.ScopeUsers.FindByScope(scope.ID)
+}
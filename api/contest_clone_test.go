@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestCloneContest(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	owner := NewTestUser(e)
+	owner.AddRoles("observe_contest", "create_contest", "update_contest")
+	participant := NewTestUser(e)
+	manager := NewTestUser(e)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:               getPtr("Test contest"),
+		BeginTime:           getPtr(NInt64(e.Now.Add(-3 * time.Hour).Unix())),
+		Duration:            getPtr(7200),
+		StandingsKind:       getPtr(string(models.IOIStandings)),
+		FreezeBeginDuration: getPtr(3600),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(ctx, &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem, err := e.Client.CreateContestProblem(
+		contest.ID, createContestProblemForm{
+			Code:      getPtr("A"),
+			ProblemID: getPtr(problem.ID),
+			Points:    getPtr(100),
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Pin problem revision of source contest.
+	if err := e.Core.ContestProblems.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problemModel, err := e.Core.ContestProblems.Get(contestProblem.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := problemModel.SetConfig(models.ContestProblemConfig{
+		Points:     getPtr(100),
+		RevisionID: 42,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestProblems.Update(ctx, problemModel); err != nil {
+		t.Fatal("Error:", err)
+	}
+	ownerUser, err := e.Core.Users.Get(owner.User.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	scope := models.Scope{
+		OwnerID: NInt64(ownerUser.AccountID),
+		Title:   "Test scope",
+	}
+	if err := e.Core.Scopes.Create(ctx, &scope); err != nil {
+		t.Fatal("Error:", err)
+	}
+	scopeUser := models.ScopeUser{
+		ScopeID: scope.ID,
+		Login:   "scope_user",
+	}
+	scopeAccount := models.Account{Kind: scopeUser.AccountKind()}
+	if err := e.Core.Accounts.Create(ctx, &scopeAccount); err != nil {
+		t.Fatal("Error:", err)
+	}
+	scopeUser.AccountID = scopeAccount.ID
+	if err := e.Core.ScopeUsers.Create(ctx, &scopeUser); err != nil {
+		t.Fatal("Error:", err)
+	}
+	for user, kind := range map[*TestUser]models.ParticipantKind{
+		participant: models.RegularParticipant,
+		manager:     models.ManagerParticipant,
+	} {
+		if _, err := e.Client.CreateContestParticipant(
+			contest.ID, createContestParticipantForm{
+				UserID: getPtr(user.User.ID),
+				Kind:   kind,
+			},
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	beginTime := NInt64(e.Now.Add(time.Hour).Unix())
+	cloned, err := e.Client.CloneContest(ctx, contest.ID, CloneContestForm{
+		Title:            getPtr("Cloned contest"),
+		BeginTime:        &beginTime,
+		CopyParticipants: true,
+		ScopeID:          getPtr(scope.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if cloned.ID == contest.ID || cloned.Title != "Cloned contest" ||
+		cloned.BeginTime != beginTime || cloned.Duration != 7200 ||
+		cloned.StandingsKind != models.IOIStandings ||
+		cloned.FreezeBeginDuration != 3600 || cloned.Template {
+		t.Fatalf("Invalid contest: %+v", cloned)
+	}
+	if err := e.Core.ContestProblems.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestParticipants.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problems, err := e.Core.ContestProblems.FindByContest(cloned.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(problems) != 1 || problems[0].Code != "A" ||
+		problems[0].ProblemID != problem.ID {
+		t.Fatalf("Invalid problems: %+v", problems)
+	}
+	if config, err := problems[0].GetConfig(); err != nil {
+		t.Fatal("Error:", err)
+	} else if getValue(config.Points) != 100 || config.RevisionID != 0 {
+		t.Fatalf("Invalid problem config: %+v", config)
+	}
+	participants, err := e.Core.ContestParticipants.FindByContest(cloned.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(participants) != 2 {
+		t.Fatalf("Invalid participants: %+v", participants)
+	}
+	for _, participant := range participants {
+		if participant.Kind != models.RegularParticipant {
+			t.Fatalf("Invalid participants: %+v", participants)
+		}
+	}
+	template, err := e.Client.CloneContest(ctx, contest.ID, CloneContestForm{
+		Template:         getPtr(true),
+		BeginTime:        &beginTime,
+		CopyParticipants: true,
+		CopyManagers:     true,
+		CopyRevisions:    true,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !template.Template || template.Title != "Test contest" {
+		t.Fatalf("Invalid template: %+v", template)
+	}
+	if err := e.Core.ContestParticipants.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	participants, err = e.Core.ContestParticipants.FindByContest(template.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(participants) != 2 {
+		t.Fatalf("Invalid participants: %+v", participants)
+	}
+	if err := e.Core.ContestProblems.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problems, err = e.Core.ContestProblems.FindByContest(template.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("Invalid problems: %+v", problems)
+	}
+	if config, err := problems[0].GetConfig(); err != nil {
+		t.Fatal("Error:", err)
+	} else if config.RevisionID != 42 {
+		t.Fatalf("Invalid problem config: %+v", config)
+	}
+	if _, err := e.Client.CloneContest(ctx, contest.ID, CloneContestForm{
+		Title: getPtr("A"),
+	}); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	func() {
+		owner.LogoutClient()
+		defer owner.LoginClient()
+		participant.LoginClient()
+		defer participant.LogoutClient()
+		// Templates are never started.
+		resp, err := e.Client.ObserveContest(ctx, template.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if resp.State == nil || resp.State.Stage != "not_planned" {
+			t.Fatalf("Invalid contest: %+v", resp)
+		}
+		if _, err := e.Client.CloneContest(
+			ctx, contest.ID, CloneContestForm{},
+		); err == nil {
+			t.Fatal("Expected error")
+		} else if resp, ok := err.(statusCodeResponse); !ok {
+			t.Fatal("Invalid error:", err)
+		} else {
+			expectStatus(t, http.StatusForbidden, resp.StatusCode())
+		}
+	}()
+}
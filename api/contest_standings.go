@@ -1,10 +1,13 @@
 package api
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
+	"github.com/udovin/gosql"
 	"github.com/udovin/solve/managers"
 	"github.com/udovin/solve/models"
 )
@@ -15,6 +18,16 @@ func (v *View) registerContestStandingsHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
 		v.requirePermission(models.ObserveContestStandingsRole),
 	)
+	g.GET(
+		"/v0/contests/:contest/standings/export", v.exportContestStandings,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
+		v.requirePermission(models.ObserveContestStandingsRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/standings/unfreeze", v.unfreezeContestStandings,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.UpdateContestRole),
+	)
 }
 
 type ContestStandingsColumn struct {
@@ -27,18 +40,34 @@ type ContestStandingsCell struct {
 	Verdict string `json:"verdict"`
 	Attempt int    `json:"attempt"`
 	Time    *int64 `json:"time,omitempty"`
+	// Pretests means that verdict is based on pretests.
+	Pretests bool `json:"pretests,omitempty"`
+	// Points contains points of cell in IOI standings.
+	Points *float64 `json:"points,omitempty"`
+	// Frozen means that verdict of cell is hidden by freeze.
+	Frozen bool `json:"frozen,omitempty"`
 }
 
 type ContestStandingsRow struct {
-	Participant ContestParticipant     `json:"participant,omitempty"`
-	Score       int                    `json:"score"`
-	Penalty     *int64                 `json:"penalty,omitempty"`
-	Cells       []ContestStandingsCell `json:"cells,omitempty"`
+	Participant ContestParticipant `json:"participant,omitempty"`
+	Score       int                `json:"score"`
+	Penalty     *int64             `json:"penalty,omitempty"`
+	// Points contains sum of points in IOI standings.
+	Points *float64               `json:"points,omitempty"`
+	Cells  []ContestStandingsCell `json:"cells,omitempty"`
 }
 
 type ContestStandings struct {
-	Columns []ContestStandingsColumn `json:"columns,omitempty"`
-	Rows    []ContestStandingsRow    `json:"rows,omitempty"`
+	Kind    models.ContestStandingsKind `json:"kind,omitempty"`
+	Columns []ContestStandingsColumn    `json:"columns,omitempty"`
+	Rows    []ContestStandingsRow       `json:"rows,omitempty"`
+	// Frozen means that standings are frozen.
+	Frozen bool `json:"frozen,omitempty"`
+}
+
+type contestStandingsFilter struct {
+	// Public means that standings should be built as for participants.
+	Public bool `query:"public"`
 }
 
 func (v *View) observeContestStandings(c echo.Context) error {
@@ -46,12 +75,39 @@ func (v *View) observeContestStandings(c echo.Context) error {
 	if !ok {
 		return fmt.Errorf("contest not extracted")
 	}
-	contest := contestCtx.Contest
-	standings, err := v.standings.BuildStandings(getContext(c), contest, contestCtx.Now)
+	var filter contestStandingsFilter
+	if err := c.Bind(&filter); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid filter."),
+		}
+	}
+	resp, err := v.makeContestStandings(c, contestCtx, filter)
 	if err != nil {
 		return err
 	}
-	resp := ContestStandings{}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// makeContestStandings builds standings with respect to freeze and
+// permissions of current account.
+func (v *View) makeContestStandings(
+	c echo.Context, contestCtx *managers.ContestContext,
+	filter contestStandingsFilter,
+) (ContestStandings, error) {
+	observeFullStandings := contestCtx.HasPermission(models.ObserveContestFullStandingsRole)
+	contest := contestCtx.Contest
+	standings, err := v.standings.BuildStandings(
+		getContext(c), contest, contestCtx.Now,
+		managers.BuildStandingsOptions{
+			IgnoreFreeze: observeFullStandings && !filter.Public,
+		},
+	)
+	if err != nil {
+		return ContestStandings{}, err
+	}
+	resp := ContestStandings{Kind: standings.Kind, Frozen: standings.Frozen}
 	for _, column := range standings.Columns {
 		columnResp := ContestStandingsColumn{
 			Code: column.Problem.Code,
@@ -62,12 +118,11 @@ func (v *View) observeContestStandings(c echo.Context) error {
 		}
 		resp.Columns = append(resp.Columns, columnResp)
 	}
-	observeFullStandings := contestCtx.HasPermission(models.ObserveContestFullStandingsRole)
 	for _, row := range standings.Rows {
 		if !observeFullStandings {
 			switch row.Participant.Kind {
 			case models.RegularParticipant:
-			case models.UpsolvingParticipant:
+			case models.UpsolvingParticipant, models.VirtualParticipant:
 				if contestCtx.Stage != managers.ContestFinished {
 					continue
 				}
@@ -79,15 +134,23 @@ func (v *View) observeContestStandings(c echo.Context) error {
 			Participant: makeContestParticipant(row.Participant, v.core),
 			Score:       row.Score,
 		}
-		if row.Participant.Kind == models.RegularParticipant {
+		isIOI := standings.Kind == models.IOIStandings
+		if isIOI {
+			rowResp.Points = getPtr(row.Points)
+		} else if hasParticipantTime(row.Participant.Kind) {
 			rowResp.Penalty = getPtr(row.Penalty)
 		}
 		for _, cell := range row.Cells {
 			cellResp := ContestStandingsCell{
-				Column:  cell.Column,
-				Attempt: cell.Attempt,
+				Column:   cell.Column,
+				Attempt:  cell.Attempt,
+				Pretests: cell.Pretests,
+				Frozen:   cell.Frozen,
 			}
-			if row.Participant.Kind == models.RegularParticipant {
+			if isIOI && !cell.Frozen {
+				cellResp.Points = getPtr(cell.Points)
+			}
+			if hasParticipantTime(row.Participant.Kind) {
 				cellResp.Time = getPtr(cell.Time)
 			}
 			if cell.Verdict != 0 {
@@ -101,5 +164,216 @@ func (v *View) observeContestStandings(c echo.Context) error {
 		}
 		resp.Rows = append(resp.Rows, rowResp)
 	}
+	return resp, nil
+}
+
+// hasParticipantTime returns true if participant has begin time, so
+// time of cells can be shown.
+func hasParticipantTime(kind models.ParticipantKind) bool {
+	switch kind {
+	case models.RegularParticipant, models.VirtualParticipant:
+		return true
+	default:
+		return false
+	}
+}
+
+type ContestStandingsCellKey struct {
+	ParticipantID int64 `json:"participant_id"`
+	ProblemID     int64 `json:"problem_id"`
+}
+
+type ContestStandingsUnfreeze struct {
+	// Unfrozen means that all cells of standings are unfrozen.
+	Unfrozen bool `json:"unfrozen"`
+	// Cell contains unfrozen cell.
+	Cell *ContestStandingsCellKey `json:"cell,omitempty"`
+}
+
+type ContestStandingsUnfreezeMode string
+
+const (
+	// UnfreezeAllCells unfreezes all cells at once.
+	UnfreezeAllCells ContestStandingsUnfreezeMode = "all"
+	// UnfreezeNextCell unfreezes leftmost frozen cell of lowest
+	// ranked participant like resolver does.
+	UnfreezeNextCell ContestStandingsUnfreezeMode = "next"
+	// UnfreezeCell unfreezes specified cell.
+	UnfreezeCell ContestStandingsUnfreezeMode = "cell"
+)
+
+type UnfreezeContestStandingsForm struct {
+	Mode          ContestStandingsUnfreezeMode `json:"mode"`
+	ParticipantID int64                        `json:"participant_id"`
+	ProblemID     int64                        `json:"problem_id"`
+}
+
+func (f UnfreezeContestStandingsForm) Validate(c echo.Context) error {
+	errors := errorFields{}
+	switch f.Mode {
+	case UnfreezeAllCells, UnfreezeNextCell:
+	case UnfreezeCell:
+		if f.ParticipantID == 0 {
+			errors["participant_id"] = errorField{
+				Message: localize(c, "Participant is required."),
+			}
+		}
+		if f.ProblemID == 0 {
+			errors["problem_id"] = errorField{
+				Message: localize(c, "Problem is required."),
+			}
+		}
+	default:
+		errors["mode"] = errorField{
+			Message: localize(c, "Invalid unfreeze mode."),
+		}
+	}
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+// findNextFrozenCell returns leftmost frozen cell of lowest ranked
+// regular participant.
+func findNextFrozenCell(
+	standings *managers.ContestStandings,
+) (models.ContestStandingsCellKey, bool) {
+	for i := len(standings.Rows) - 1; i >= 0; i-- {
+		row := standings.Rows[i]
+		if row.Participant.Kind != models.RegularParticipant {
+			continue
+		}
+		for _, cell := range row.Cells {
+			if cell.Frozen {
+				return models.ContestStandingsCellKey{
+					ParticipantID: row.Participant.ID,
+					ProblemID:     standings.Columns[cell.Column].Problem.ID,
+				}, true
+			}
+		}
+	}
+	return models.ContestStandingsCellKey{}, false
+}
+
+func (v *View) unfreezeContestStandings(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form UnfreezeContestStandingsForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid form."),
+		}
+	}
+	if err := form.Validate(c); err != nil {
+		return err
+	}
+	contest := contestCtx.Contest
+	unfreezeAll := false
+	var cell models.ContestStandingsCellKey
+	var resp ContestStandingsUnfreeze
+	switch form.Mode {
+	case UnfreezeAllCells:
+		unfreezeAll = true
+	case UnfreezeNextCell:
+		standings, err := v.standings.BuildStandings(
+			getContext(c), contest, contestCtx.Now,
+			managers.BuildStandingsOptions{},
+		)
+		if err != nil {
+			return err
+		}
+		key, ok := findNextFrozenCell(standings)
+		if ok {
+			cell = key
+		} else {
+			// There are no frozen cells, so standings are unfrozen.
+			unfreezeAll = true
+		}
+	case UnfreezeCell:
+		if err := syncStore(c, v.core.ContestParticipants); err != nil {
+			return err
+		}
+		if err := syncStore(c, v.core.ContestProblems); err != nil {
+			return err
+		}
+		participant, err := v.core.ContestParticipants.Get(form.ParticipantID)
+		if err != nil || participant.ContestID != contest.ID {
+			return errorResponse{
+				Code:    http.StatusNotFound,
+				Message: localize(c, "Participant not found."),
+			}
+		}
+		problem, err := v.core.ContestProblems.Get(form.ProblemID)
+		if err != nil || problem.ContestID != contest.ID {
+			return errorResponse{
+				Code:    http.StatusNotFound,
+				Message: localize(c, "Problem not found."),
+			}
+		}
+		cell = models.ContestStandingsCellKey{
+			ParticipantID: participant.ID,
+			ProblemID:     problem.ID,
+		}
+	}
+	if !unfreezeAll {
+		resp.Cell = &ContestStandingsCellKey{
+			ParticipantID: cell.ParticipantID,
+			ProblemID:     cell.ProblemID,
+		}
+	}
+	// Unfrozen cells are updated concurrently during award ceremony,
+	// so config of contest should be read inside of transaction.
+	var config models.ContestConfig
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		contest, err := v.findContestForUpdate(ctx, contest.ID)
+		if err != nil {
+			return err
+		}
+		config, err = contest.GetConfig()
+		if err != nil {
+			return err
+		}
+		if unfreezeAll {
+			config.Unfrozen = true
+			config.UnfrozenCells = nil
+		} else if !config.IsCellUnfrozen(cell) {
+			config.UnfrozenCells = append(config.UnfrozenCells, cell)
+		}
+		if err := contest.SetConfig(config); err != nil {
+			return err
+		}
+		return v.core.Contests.Update(ctx, contest)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	resp.Unfrozen = config.Unfrozen
 	return c.JSON(http.StatusOK, resp)
 }
+
+// findContestForUpdate reads contest from database inside of
+// transaction, so concurrent updates of contest are not lost.
+func (v *View) findContestForUpdate(
+	ctx context.Context, id int64,
+) (models.Contest, error) {
+	reader, err := v.core.Contests.Find(ctx, gosql.Column("id").Equal(id))
+	if err != nil {
+		return models.Contest{}, err
+	}
+	defer func() { _ = reader.Close() }()
+	if !reader.Next() {
+		if err := reader.Err(); err != nil {
+			return models.Contest{}, err
+		}
+		return models.Contest{}, sql.ErrNoRows
+	}
+	return reader.Row(), nil
+}
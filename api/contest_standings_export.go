@@ -0,0 +1,398 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+// ContestStandingsExportKind represents format of exported standings.
+type ContestStandingsExportKind string
+
+const (
+	// CSVStandingsExport represents standings as CSV table with one row
+	// per participant and one column per problem.
+	CSVStandingsExport ContestStandingsExportKind = "csv"
+	// JSONStandingsExport represents standings as list of flat objects.
+	JSONStandingsExport ContestStandingsExportKind = "json"
+	// XMLStandingsExport represents standings in PC^2 XML format that is
+	// supported by ICPC tools.
+	XMLStandingsExport ContestStandingsExportKind = "xml"
+)
+
+type exportContestStandingsForm struct {
+	Kind ContestStandingsExportKind `query:"kind"`
+	// Public means that standings should be exported as for participants.
+	Public bool `query:"public"`
+}
+
+func (v *View) exportContestStandings(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var form exportContestStandingsForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Form has invalid fields."),
+		}
+	}
+	var export func(ContestStandings, models.Contest) ([]byte, error)
+	var contentType string
+	switch form.Kind {
+	case "", CSVStandingsExport:
+		form.Kind = CSVStandingsExport
+		export, contentType = exportStandingsCSV, "text/csv; charset=utf-8"
+	case JSONStandingsExport:
+		export, contentType = exportStandingsJSON, echo.MIMEApplicationJSONCharsetUTF8
+	case XMLStandingsExport:
+		export, contentType = exportStandingsXML, echo.MIMEApplicationXMLCharsetUTF8
+	default:
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"kind": errorField{
+					Message: localize(c, "Invalid export kind."),
+				},
+			},
+		}
+	}
+	standings, err := v.makeContestStandings(
+		c, contestCtx, contestStandingsFilter{Public: form.Public},
+	)
+	if err != nil {
+		return err
+	}
+	data, err := export(getOfficialStandings(standings), contestCtx.Contest)
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf(
+			"attachment; filename=\"standings-%d.%s\"",
+			contestCtx.Contest.ID, form.Kind,
+		),
+	)
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// getContestParticipantName returns display name of participant.
+func getContestParticipantName(participant ContestParticipant) string {
+	switch {
+	case participant.Team != nil:
+		return participant.Team.Title
+	case participant.ScopeUser != nil:
+		if participant.ScopeUser.Title != "" {
+			return participant.ScopeUser.Title
+		}
+		return participant.ScopeUser.Login
+	case participant.User != nil:
+		return participant.User.Login
+	default:
+		return fmt.Sprintf("Participant %d", participant.ID)
+	}
+}
+
+// getOfficialStandings returns standings with regular participants only.
+//
+// Managers, virtual and upsolving participants are not ranked, so they
+// are excluded from exported standings.
+func getOfficialStandings(standings ContestStandings) ContestStandings {
+	official := standings
+	official.Rows = nil
+	for _, row := range standings.Rows {
+		if row.Participant.Kind == models.RegularParticipant {
+			official.Rows = append(official.Rows, row)
+		}
+	}
+	return official
+}
+
+// getContestStandingsRanks returns ranks of standings rows.
+//
+// Rows with equal results share the same rank.
+func getContestStandingsRanks(standings ContestStandings) []int {
+	isEqual := func(lhs, rhs ContestStandingsRow) bool {
+		if standings.Kind == models.IOIStandings {
+			return getValue(lhs.Points) == getValue(rhs.Points)
+		}
+		return lhs.Score == rhs.Score &&
+			getValue(lhs.Penalty) == getValue(rhs.Penalty)
+	}
+	ranks := make([]int, len(standings.Rows))
+	for i, row := range standings.Rows {
+		ranks[i] = i + 1
+		if i > 0 && isEqual(standings.Rows[i-1], row) {
+			ranks[i] = ranks[i-1]
+		}
+	}
+	return ranks
+}
+
+func getValue[T any](ptr *T) T {
+	if ptr == nil {
+		var empty T
+		return empty
+	}
+	return *ptr
+}
+
+// formatStandingsCell returns short text representation of cell.
+//
+// ICPC cells are formatted as "+", "+2", "-3" or "?1" for frozen cells.
+// IOI cells contain points or "?" for frozen cells.
+func formatStandingsCell(
+	kind models.ContestStandingsKind, cell ContestStandingsCell,
+) string {
+	if kind == models.IOIStandings {
+		if cell.Frozen {
+			return "?"
+		}
+		return strconv.FormatFloat(getValue(cell.Points), 'f', -1, 64)
+	}
+	switch {
+	case cell.Frozen:
+		return fmt.Sprintf("?%d", cell.Attempt)
+	case cell.Verdict == models.Accepted.String():
+		if cell.Attempt > 1 {
+			return fmt.Sprintf("+%d", cell.Attempt-1)
+		}
+		return "+"
+	default:
+		return fmt.Sprintf("-%d", cell.Attempt)
+	}
+}
+
+// getStandingsCells returns cells of row indexed by column.
+func getStandingsCells(
+	standings ContestStandings, row ContestStandingsRow,
+) []*ContestStandingsCell {
+	cells := make([]*ContestStandingsCell, len(standings.Columns))
+	for i := range row.Cells {
+		if column := row.Cells[i].Column; column < len(cells) {
+			cells[column] = &row.Cells[i]
+		}
+	}
+	return cells
+}
+
+func exportStandingsCSV(
+	standings ContestStandings, contest models.Contest,
+) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	isIOI := standings.Kind == models.IOIStandings
+	header := []string{"rank", "participant"}
+	if isIOI {
+		header = append(header, "points")
+	} else {
+		header = append(header, "score", "penalty")
+	}
+	for _, column := range standings.Columns {
+		header = append(header, column.Code)
+	}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+	ranks := getContestStandingsRanks(standings)
+	for i, row := range standings.Rows {
+		record := []string{
+			strconv.Itoa(ranks[i]),
+			getContestParticipantName(row.Participant),
+		}
+		if isIOI {
+			record = append(record, strconv.FormatFloat(
+				getValue(row.Points), 'f', -1, 64,
+			))
+		} else {
+			record = append(
+				record,
+				strconv.Itoa(row.Score),
+				strconv.FormatInt(getValue(row.Penalty), 10),
+			)
+		}
+		for _, cell := range getStandingsCells(standings, row) {
+			if cell == nil {
+				record = append(record, "")
+			} else {
+				record = append(record, formatStandingsCell(standings.Kind, *cell))
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportStandingsJSON exports standings as list of flat objects.
+//
+// Every object contains the same fields as CSV row, so results of
+// problems are stored in fields named by problem codes.
+func exportStandingsJSON(
+	standings ContestStandings, contest models.Contest,
+) ([]byte, error) {
+	rows := []map[string]any{}
+	isIOI := standings.Kind == models.IOIStandings
+	ranks := getContestStandingsRanks(standings)
+	for i, row := range standings.Rows {
+		record := map[string]any{
+			"rank":           ranks[i],
+			"participant_id": row.Participant.ID,
+			"participant":    getContestParticipantName(row.Participant),
+		}
+		if isIOI {
+			record["points"] = getValue(row.Points)
+		} else {
+			record["score"] = row.Score
+			record["penalty"] = getValue(row.Penalty)
+		}
+		for j, cell := range getStandingsCells(standings, row) {
+			if cell == nil {
+				record[standings.Columns[j].Code] = ""
+			} else {
+				record[standings.Columns[j].Code] = formatStandingsCell(
+					standings.Kind, *cell,
+				)
+			}
+		}
+		rows = append(rows, record)
+	}
+	return json.Marshal(rows)
+}
+
+type pc2ContestStandings struct {
+	XMLName   xml.Name           `xml:"contestStandings"`
+	Header    pc2StandingsHeader `xml:"standingsHeader"`
+	Standings []pc2TeamStanding  `xml:"teamStanding"`
+}
+
+type pc2StandingsHeader struct {
+	Title         string             `xml:"title,attr"`
+	ProblemCount  int                `xml:"problemCount,attr"`
+	TotalAttempts int                `xml:"totalAttempts,attr"`
+	TotalSolved   int                `xml:"totalSolved,attr"`
+	SystemName    string             `xml:"systemName,attr"`
+	Problems      []pc2ProblemHeader `xml:"problem"`
+}
+
+type pc2ProblemHeader struct {
+	ID            int    `xml:"id,attr"`
+	Title         string `xml:"title,attr"`
+	Attempts      int    `xml:"attempts,attr"`
+	NumberSolved  int    `xml:"numberSolved,attr"`
+	BestSolveTime *int64 `xml:"bestSolutionTime,attr,omitempty"`
+}
+
+type pc2TeamStanding struct {
+	Index          int                 `xml:"index,attr"`
+	Rank           int                 `xml:"rank,attr"`
+	TeamName       string              `xml:"teamName,attr"`
+	TeamID         int64               `xml:"teamId,attr"`
+	Solved         int                 `xml:"solved,attr"`
+	Points         int64               `xml:"points,attr"`
+	TotalAttempts  int                 `xml:"totalAttempts,attr"`
+	ProblemSummary []pc2ProblemSummary `xml:"problemSummaryInfo"`
+}
+
+type pc2ProblemSummary struct {
+	Index        int   `xml:"index,attr"`
+	Attempts     int   `xml:"attempts,attr"`
+	IsSolved     bool  `xml:"isSolved,attr"`
+	IsPending    bool  `xml:"isPending,attr"`
+	SolutionTime int64 `xml:"solutionTime,attr"`
+}
+
+// exportStandingsXML exports standings in PC^2 XML format.
+//
+// Penalty is exported as points and times are exported in minutes.
+// IOI standings are exported with solved problems that have full points.
+func exportStandingsXML(
+	standings ContestStandings, contest models.Contest,
+) ([]byte, error) {
+	resp := pc2ContestStandings{
+		Header: pc2StandingsHeader{
+			Title:        contest.Title,
+			ProblemCount: len(standings.Columns),
+			SystemName:   "Solve",
+		},
+	}
+	for i, column := range standings.Columns {
+		resp.Header.Problems = append(resp.Header.Problems, pc2ProblemHeader{
+			ID:    i + 1,
+			Title: column.Code,
+		})
+	}
+	ranks := getContestStandingsRanks(standings)
+	for i, row := range standings.Rows {
+		team := pc2TeamStanding{
+			Index:    i + 1,
+			Rank:     ranks[i],
+			TeamName: getContestParticipantName(row.Participant),
+			TeamID:   row.Participant.ID,
+			Points:   getValue(row.Penalty),
+		}
+		for j, cell := range getStandingsCells(standings, row) {
+			summary := pc2ProblemSummary{Index: j + 1}
+			if cell != nil {
+				summary.Attempts = cell.Attempt
+				summary.IsPending = cell.Frozen
+				summary.IsSolved = !cell.Frozen && isStandingsCellSolved(
+					standings, j, *cell,
+				)
+				if summary.IsSolved {
+					summary.SolutionTime = getValue(cell.Time) / 60
+				}
+			}
+			team.TotalAttempts += summary.Attempts
+			problem := &resp.Header.Problems[j]
+			problem.Attempts += summary.Attempts
+			if summary.IsSolved {
+				team.Solved++
+				problem.NumberSolved++
+				if problem.BestSolveTime == nil ||
+					*problem.BestSolveTime > summary.SolutionTime {
+					problem.BestSolveTime = getPtr(summary.SolutionTime)
+				}
+			}
+			team.ProblemSummary = append(team.ProblemSummary, summary)
+		}
+		resp.Header.TotalAttempts += team.TotalAttempts
+		resp.Header.TotalSolved += team.Solved
+		resp.Standings = append(resp.Standings, team)
+	}
+	data, err := xml.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// isStandingsCellSolved returns true if cell contains accepted solution
+// or full points for IOI standings.
+func isStandingsCellSolved(
+	standings ContestStandings, column int, cell ContestStandingsCell,
+) bool {
+	if standings.Kind != models.IOIStandings {
+		return cell.Verdict == models.Accepted.String()
+	}
+	points := standings.Columns[column].Points
+	return points != nil && cell.Points != nil &&
+		*cell.Points >= float64(*points)
+}
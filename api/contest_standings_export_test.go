@@ -0,0 +1,257 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+var testExportStandings = ContestStandings{
+	Kind: models.ICPCStandings,
+	Columns: []ContestStandingsColumn{
+		{Code: "A"}, {Code: "B"},
+	},
+	Rows: []ContestStandingsRow{
+		{
+			Participant: ContestParticipant{
+				ID: 1, Team: &Team{ID: 1, Title: "Team, first"},
+			},
+			Score:   1,
+			Penalty: getPtr(int64(41)),
+			Cells: []ContestStandingsCell{
+				{Column: 0, Verdict: "accepted", Attempt: 2, Time: getPtr(int64(1260))},
+				{Column: 1, Attempt: 1, Frozen: true},
+			},
+		},
+		{
+			Participant: ContestParticipant{
+				ID: 2, User: &User{ID: 2, Login: "second"},
+			},
+			Score:   1,
+			Penalty: getPtr(int64(41)),
+			Cells: []ContestStandingsCell{
+				{Column: 1, Verdict: "accepted", Attempt: 1, Time: getPtr(int64(2460))},
+			},
+		},
+		{
+			Participant: ContestParticipant{
+				ID: 3, User: &User{ID: 3, Login: "third"},
+			},
+			Penalty: getPtr(int64(0)),
+			Cells: []ContestStandingsCell{
+				{Column: 0, Verdict: "rejected", Attempt: 3},
+			},
+		},
+	},
+}
+
+func TestExportStandingsCSV(t *testing.T) {
+	data, err := exportStandingsCSV(testExportStandings, models.Contest{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	expected := "rank,participant,score,penalty,A,B\n" +
+		"1,\"Team, first\",1,41,+1,?1\n" +
+		"1,second,1,41,,+\n" +
+		"3,third,0,0,-3,\n"
+	if string(data) != expected {
+		t.Fatalf("Expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestExportStandingsJSON(t *testing.T) {
+	data, err := exportStandingsJSON(testExportStandings, models.Contest{})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var rows []map[string]any
+	if err := json.Unmarshal(data, &rows); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("Expected 3 rows, got %d", len(rows))
+	}
+	if rows[0]["participant"] != "Team, first" || rows[0]["A"] != "+1" ||
+		rows[0]["rank"] != float64(1) || rows[2]["rank"] != float64(3) {
+		t.Fatalf("Invalid rows: %v", rows)
+	}
+}
+
+func TestExportStandingsXML(t *testing.T) {
+	data, err := exportStandingsXML(
+		testExportStandings, models.Contest{Title: "Test contest"},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	for _, part := range []string{
+		`<standingsHeader title="Test contest" problemCount="2" totalAttempts="7" totalSolved="2"`,
+		`<problem id="1" title="A" attempts="5" numberSolved="1" bestSolutionTime="21"></problem>`,
+		`<teamStanding index="1" rank="1" teamName="Team, first" teamId="1" solved="1" points="41" totalAttempts="3">`,
+		`<problemSummaryInfo index="2" attempts="1" isSolved="false" isPending="true" solutionTime="0"></problemSummaryInfo>`,
+	} {
+		if !strings.Contains(string(data), part) {
+			t.Fatalf("Expected %q in %s", part, string(data))
+		}
+	}
+}
+
+func TestExportContestStandings(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	user := NewTestUser(e)
+	user.AddRoles("observe_contest", "create_contest", "update_contest")
+	user.LoginClient()
+	defer user.LogoutClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+		Duration:  getPtr(7200),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ExportContestStandings(
+		ctx, contest.ID, "unknown",
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusBadRequest, resp.StatusCode())
+	}
+	reader, err := e.Client.ExportContestStandings(
+		ctx, contest.ID, CSVStandingsExport,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if expected := "rank,participant,score,penalty\n"; string(data) != expected {
+		t.Fatalf("Expected %q, got %q", expected, string(data))
+	}
+}
+
+func TestExportContestStandingsParticipants(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	owner := NewTestUser(e)
+	owner.AddRoles("observe_contest", "create_contest", "update_contest")
+	users := []*TestUser{NewTestUser(e), NewTestUser(e), NewTestUser(e)}
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:     getPtr("Test contest"),
+		BeginTime: getPtr(NInt64(e.Now.Add(-3 * time.Hour).Unix())),
+		Duration:  getPtr(3600),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(ctx, &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: NInt64(fakeFile.ID),
+	}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := e.Core.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "g++", ImageID: fakeFile.ID}
+	if err := e.Core.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Manager and upsolving participants solve problem faster than
+	// regular participant, but they should not be exported.
+	for i, kind := range []models.ParticipantKind{
+		models.ManagerParticipant,
+		models.RegularParticipant,
+		models.UpsolvingParticipant,
+	} {
+		user, err := e.Core.Users.Get(users[i].User.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		participant := models.ContestParticipant{
+			ContestID: contest.ID,
+			AccountID: user.AccountID,
+			Kind:      kind,
+		}
+		if err := e.Core.ContestParticipants.Create(ctx, &participant); err != nil {
+			t.Fatal("Error:", err)
+		}
+		solution := models.Solution{
+			ProblemID:  problem.ID,
+			CompilerID: compiler.ID,
+			AuthorID:   user.AccountID,
+			CreateTime: e.Now.Add(-3*time.Hour + time.Duration(i+1)*time.Minute).Unix(),
+		}
+		if err := solution.SetReport(&models.SolutionReport{
+			Verdict: models.Accepted,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+			t.Fatal("Error:", err)
+		}
+		contestSolution := models.ContestSolution{
+			ContestID:     contest.ID,
+			SolutionID:    solution.ID,
+			ParticipantID: participant.ID,
+			ProblemID:     contestProblem.ID,
+		}
+		if err := e.Core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := e.Core.ContestProblems.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestParticipants.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.ContestSolutions.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	reader, err := e.Client.ExportContestStandings(
+		ctx, contest.ID, CSVStandingsExport,
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = reader.Close() }()
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "1,") ||
+		!strings.HasSuffix(lines[1], ",1,2,+") {
+		t.Fatalf("Invalid standings: %q", string(data))
+	}
+}
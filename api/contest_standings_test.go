@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestUnfreezeContestStandings(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	owner := NewTestUser(e)
+	owner.AddRoles("observe_contest", "create_contest", "update_contest")
+	participant := NewTestUser(e)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title:               getPtr("Test contest"),
+		BeginTime:           getPtr(NInt64(e.Now.Add(-3 * time.Hour).Unix())),
+		Duration:            getPtr(7200),
+		FreezeBeginDuration: getPtr(3600),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem, err := e.Client.CreateContestProblem(
+		contest.ID, createContestProblemForm{
+			Code:      getPtr("A"),
+			ProblemID: getPtr(problem.ID),
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestParticipant, err := e.Client.CreateContestParticipant(
+		contest.ID, createContestParticipantForm{
+			UserID: getPtr(participant.User.ID),
+			Kind:   models.RegularParticipant,
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	form := UnfreezeContestStandingsForm{
+		Mode:          UnfreezeCell,
+		ParticipantID: contestParticipant.ID,
+		ProblemID:     contestProblem.ID,
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := e.Client.UnfreezeContestStandings(ctx, contest.ID, form)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if resp.Unfrozen || resp.Cell == nil ||
+			resp.Cell.ParticipantID != contestParticipant.ID {
+			t.Fatalf("Invalid response: %+v", resp)
+		}
+	}
+	if err := e.Core.Contests.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	updated, err := e.Core.Contests.Get(contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	config, err := updated.GetConfig()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(config.UnfrozenCells) != 1 || config.Unfrozen {
+		t.Fatalf("Invalid config: %+v", config)
+	}
+	resp, err := e.Client.UnfreezeContestStandings(
+		ctx, contest.ID, UnfreezeContestStandingsForm{Mode: UnfreezeAllCells},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !resp.Unfrozen || resp.Cell != nil {
+		t.Fatalf("Invalid response: %+v", resp)
+	}
+}
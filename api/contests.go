@@ -99,6 +99,18 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 		v.extractContest, v.extractContestSolution,
 		v.requirePermission(models.UpdateContestSolutionRole),
 	)
+	g.POST(
+		"/v0/contests/:contest/solutions/:solution/stress", v.stressContestSolution,
+		v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestSolution,
+		v.requirePermission(models.UpdateContestSolutionRole),
+	)
+	g.GET(
+		"/v0/contests/:contest/solutions/:solution/stress", v.observeContestSolutionStress,
+		v.extractAuth(v.sessionAuth),
+		v.extractContest, v.extractContestSolution,
+		v.requirePermission(models.UpdateContestSolutionRole),
+	)
 	g.GET(
 		"/v0/contests/:contest/participants", v.observeContestParticipants,
 		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractContest,
@@ -120,6 +132,16 @@ func (v *View) registerContestHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth), v.extractContest,
 		v.requirePermission(models.RegisterContestRole),
 	)
+	g.POST(
+		"/v0/contests/:contest/virtual", v.startVirtualContest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.StartVirtualContestRole),
+	)
+	g.POST(
+		"/v0/contests/:contest/start", v.startContest,
+		v.extractAuth(v.sessionAuth), v.extractContest,
+		v.requirePermission(models.StartContestRole),
+	)
 }
 
 type ContestState struct {
@@ -136,7 +158,24 @@ type Contest struct {
 	Permissions        []string      `json:"permissions,omitempty"`
 	EnableRegistration bool          `json:"enable_registration"`
 	EnableUpsolving    bool          `json:"enable_upsolving"`
+	EnablePretests     bool          `json:"enable_pretests,omitempty"`
 	State              *ContestState `json:"state,omitempty"`
+	// StandingsKind contains kind of contest standings.
+	StandingsKind models.ContestStandingsKind `json:"standings_kind,omitempty"`
+	// IOIScoring contains rule for IOI standings.
+	IOIScoring models.IOIScoringRule `json:"ioi_scoring,omitempty"`
+	// FreezeBeginDuration contains duration from begin of contest
+	// after which standings are frozen.
+	FreezeBeginDuration int `json:"freeze_begin_duration,omitempty"`
+	// EnableVirtual means that virtual participation is enabled.
+	EnableVirtual bool `json:"enable_virtual,omitempty"`
+	// PersonalDuration contains duration of personal timer in windowed
+	// contest.
+	PersonalDuration int `json:"personal_duration,omitempty"`
+	// CompilerLimits contains contest specific compiler limits.
+	CompilerLimits models.CompilerLimitsOverrides `json:"compiler_limits,omitempty"`
+	// Template means that contest is used only as template for cloning.
+	Template bool `json:"template,omitempty"`
 }
 
 type Contests struct {
@@ -159,6 +198,8 @@ var contestPermissions = []string{
 	models.DeleteContestRole,
 	models.RegisterContestRole,
 	models.DeregisterContestRole,
+	models.StartVirtualContestRole,
+	models.StartContestRole,
 	models.ObserveContestProblemsRole,
 	models.CreateContestProblemRole,
 	models.UpdateContestProblemRole,
@@ -172,6 +213,10 @@ var contestPermissions = []string{
 	models.UpdateContestSolutionRole,
 	models.DeleteContestSolutionRole,
 	models.ObserveContestStandingsRole,
+	models.ObserveContestClarificationsRole,
+	models.CreateContestClarificationRole,
+	models.UpdateContestClarificationRole,
+	models.CreateContestAnnouncementRole,
 }
 
 func makeContestStage(stage managers.ContestStage) string {
@@ -200,6 +245,16 @@ func makeContest(
 		resp.Duration = config.Duration
 		resp.EnableRegistration = config.EnableRegistration
 		resp.EnableUpsolving = config.EnableUpsolving
+		resp.EnablePretests = config.EnablePretests
+		resp.StandingsKind = config.StandingsKind
+		resp.IOIScoring = config.IOIScoring
+		resp.FreezeBeginDuration = config.FreezeBeginDuration
+		resp.EnableVirtual = config.EnableVirtual
+		resp.PersonalDuration = config.PersonalDuration
+		resp.Template = config.Template
+		if permissions.HasPermission(models.UpdateContestRole) {
+			resp.CompilerLimits = config.CompilerLimits
+		}
 	}
 	for _, permission := range contestPermissions {
 		if permissions.HasPermission(permission) {
@@ -208,7 +263,7 @@ func makeContest(
 	}
 	if contextCtx, ok := permissions.(*managers.ContestContext); ok {
 		state := ContestState{
-			Stage: makeContestStage(contextCtx.Stage),
+			Stage: makeContestStage(contextCtx.GetEffectiveStage()),
 		}
 		participant := contextCtx.GetEffectiveParticipant()
 		if participant != nil {
@@ -235,6 +290,13 @@ func (v *View) makeContestProblem(
 		resp.Problem = v.makeProblem(
 			c, problem, managers.PermissionSet{}, withStatement,
 		)
+		if resp.Problem.StatementHTML != nil {
+			resp.Problem.StatementHTML = v.makeProblemStatementHTML(
+				c, problem.ID, getContestProblemResourceURLPrefix(
+					contestProblem.ContestID, contestProblem.Code,
+				),
+			)
+		}
 	}
 	return resp
 }
@@ -310,6 +372,21 @@ type updateContestForm struct {
 	Duration           *int    `json:"duration" form:"duration"`
 	EnableRegistration *bool   `json:"enable_registration" form:"enable_registration"`
 	EnableUpsolving    *bool   `json:"enable_upsolving" form:"enable_upsolving"`
+	EnablePretests     *bool   `json:"enable_pretests" form:"enable_pretests"`
+	StandingsKind      *string `json:"standings_kind" form:"standings_kind"`
+	IOIScoring         *string `json:"ioi_scoring" form:"ioi_scoring"`
+	// FreezeBeginDuration contains duration from begin of contest
+	// after which standings are frozen.
+	FreezeBeginDuration *int `json:"freeze_begin_duration" form:"freeze_begin_duration"`
+	// EnableVirtual means that virtual participation is enabled.
+	EnableVirtual *bool `json:"enable_virtual" form:"enable_virtual"`
+	// PersonalDuration contains duration of personal timer in windowed
+	// contest.
+	PersonalDuration *int `json:"personal_duration" form:"personal_duration"`
+	// CompilerLimits contains contest specific compiler limits.
+	CompilerLimits JSON `json:"compiler_limits" form:"compiler_limits"`
+	// Template means that contest is used only as template for cloning.
+	Template *bool `json:"template" form:"template"`
 }
 
 func (f *updateContestForm) Update(
@@ -349,6 +426,58 @@ func (f *updateContestForm) Update(
 	if f.EnableUpsolving != nil {
 		config.EnableUpsolving = *f.EnableUpsolving
 	}
+	if f.EnablePretests != nil {
+		config.EnablePretests = *f.EnablePretests
+	}
+	if f.StandingsKind != nil {
+		kind := models.ContestStandingsKind(*f.StandingsKind)
+		switch kind {
+		case "", models.ICPCStandings, models.IOIStandings:
+			config.StandingsKind = kind
+		default:
+			errors["standings_kind"] = errorField{
+				Message: localize(c, "Invalid standings kind."),
+			}
+		}
+	}
+	if f.IOIScoring != nil {
+		rule := models.IOIScoringRule(*f.IOIScoring)
+		switch rule {
+		case "", models.BestIOIScoring, models.LastIOIScoring:
+			config.IOIScoring = rule
+		default:
+			errors["ioi_scoring"] = errorField{
+				Message: localize(c, "Invalid IOI scoring."),
+			}
+		}
+	}
+	if f.EnableVirtual != nil {
+		config.EnableVirtual = *f.EnableVirtual
+	}
+	if f.PersonalDuration != nil {
+		if *f.PersonalDuration < 0 {
+			errors["personal_duration"] = errorField{
+				Message: localize(c, "Duration cannot be negative."),
+			}
+		}
+		config.PersonalDuration = *f.PersonalDuration
+	}
+	if f.FreezeBeginDuration != nil {
+		if *f.FreezeBeginDuration < 0 {
+			errors["freeze_begin_duration"] = errorField{
+				Message: localize(c, "Duration cannot be negative."),
+			}
+		}
+		config.FreezeBeginDuration = *f.FreezeBeginDuration
+	}
+	if f.Template != nil {
+		config.Template = *f.Template
+	}
+	if f.CompilerLimits.JSON != nil {
+		config.CompilerLimits = parseCompilerLimits(
+			c, f.CompilerLimits, "compiler_limits", errors,
+		)
+	}
 	if err := contest.SetConfig(config); err != nil {
 		errors["config"] = errorField{
 			Message: localize(c, "Invalid config."),
@@ -500,15 +629,17 @@ func (v *View) observeContestProblem(c echo.Context) error {
 }
 
 type updateContestProblemForm struct {
-	Code      *string `json:"code"`
-	ProblemID *int64  `json:"problem_id"`
-	Points    *int    `json:"points"`
+	Code       *string `json:"code"`
+	ProblemID  *int64  `json:"problem_id"`
+	Points     *int    `json:"points"`
+	RevisionID *int64  `json:"revision_id"`
 }
 
 func (f updateContestProblemForm) Update(
 	c echo.Context,
 	problem *models.ContestProblem,
 	problems *models.ProblemStore,
+	revisions *models.ProblemRevisionStore,
 ) error {
 	errors := errorFields{}
 	if f.Code != nil {
@@ -553,6 +684,36 @@ func (f updateContestProblemForm) Update(
 			return err
 		}
 	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if f.RevisionID != nil {
+		config.RevisionID = *f.RevisionID
+	}
+	if config.RevisionID != 0 {
+		revision, err := revisions.Get(config.RevisionID)
+		if err != nil || revision.ProblemID != problem.ProblemID {
+			return &errorResponse{
+				Code: http.StatusNotFound,
+				Message: localize(
+					c, "Revision {id} does not exists.",
+					replaceField("id", config.RevisionID),
+				),
+			}
+		}
+		if revision.CompiledID == 0 {
+			return &errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Revision is not compiled."),
+			}
+		}
+	}
+	if f.RevisionID != nil {
+		if err := problem.SetConfig(config); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -562,6 +723,7 @@ func (f *createContestProblemForm) Update(
 	c echo.Context,
 	problem *models.ContestProblem,
 	problems *models.ProblemStore,
+	revisions *models.ProblemRevisionStore,
 ) error {
 	if f.Code == nil {
 		return &errorResponse{
@@ -583,7 +745,7 @@ func (f *createContestProblemForm) Update(
 			),
 		}
 	}
-	return (*updateContestProblemForm)(f).Update(c, problem, problems)
+	return (*updateContestProblemForm)(f).Update(c, problem, problems, revisions)
 }
 
 func (v *View) createContestProblem(c echo.Context) error {
@@ -601,7 +763,12 @@ func (v *View) createContestProblem(c echo.Context) error {
 		return err
 	}
 	var problem models.ContestProblem
-	if err := form.Update(c, &problem, v.core.Problems); err != nil {
+	if err := syncStore(c, v.core.ProblemRevisions); err != nil {
+		return err
+	}
+	if err := form.Update(
+		c, &problem, v.core.Problems, v.core.ProblemRevisions,
+	); err != nil {
 		return err
 	}
 	problem.ContestID = contest.ID
@@ -649,7 +816,12 @@ func (v *View) updateContestProblem(c echo.Context) error {
 		c.Logger().Warn(err)
 		return c.NoContent(http.StatusBadRequest)
 	}
-	if err := form.Update(c, &problem, v.core.Problems); err != nil {
+	if err := syncStore(c, v.core.ProblemRevisions); err != nil {
+		return err
+	}
+	if err := form.Update(
+		c, &problem, v.core.Problems, v.core.ProblemRevisions,
+	); err != nil {
 		return err
 	}
 	if err := v.core.ContestProblems.Update(
@@ -660,6 +832,31 @@ func (v *View) updateContestProblem(c echo.Context) error {
 	return c.JSON(http.StatusOK, v.makeContestProblem(c, problem, false))
 }
 
+// getContestProblemRevisionID returns ID of pinned problem revision.
+func getContestProblemRevisionID(problem models.ContestProblem) int64 {
+	config, err := problem.GetConfig()
+	if err != nil {
+		return 0
+	}
+	return config.RevisionID
+}
+
+// isContestPretests returns true if solutions should be judged only on
+// pretests.
+//
+// Pretests are used only while contest is running, after contest is
+// finished all solutions are judged on full set of tests.
+func isContestPretests(contestCtx *managers.ContestContext) bool {
+	if contestCtx.Stage != managers.ContestStarted {
+		return false
+	}
+	config, err := contestCtx.Contest.GetConfig()
+	if err != nil {
+		return false
+	}
+	return config.EnablePretests
+}
+
 func (v *View) deleteContestProblem(c echo.Context) error {
 	problem, ok := c.Get(contestProblemKey).(models.ContestProblem)
 	if !ok {
@@ -677,9 +874,12 @@ type ContestParticipant struct {
 	ID        int64      `json:"id,omitempty"`
 	User      *User      `json:"user,omitempty"`
 	ScopeUser *ScopeUser `json:"scope_user,omitempty"`
+	Team      *Team      `json:"team,omitempty"`
 	ContestID int64      `json:"contest_id,omitempty"`
 	// Kind contains kind.
 	Kind models.ParticipantKind `json:"kind"`
+	// BeginTime contains personal begin time of participant.
+	BeginTime NInt64 `json:"begin_time,omitempty"`
 }
 
 type ContestParticipants struct {
@@ -710,6 +910,7 @@ type createContestParticipantForm struct {
 	UserID      *int64                 `json:"user_id"`
 	UserLogin   *string                `json:"user_login"`
 	ScopeUserID *int64                 `json:"scope_user_id"`
+	TeamID      *int64                 `json:"team_id"`
 	Kind        models.ParticipantKind `json:"kind"`
 }
 
@@ -752,6 +953,18 @@ func (f createContestParticipantForm) Update(
 			}
 		}
 		participant.AccountID = user.AccountID
+	} else if f.TeamID != nil {
+		team, err := core.Teams.Get(*f.TeamID)
+		if err != nil {
+			return &errorResponse{
+				Code: http.StatusBadRequest,
+				Message: localize(
+					c, "Team {id} does not exists.",
+					replaceField("id", *f.TeamID),
+				),
+			}
+		}
+		participant.AccountID = team.AccountID
 	}
 	participant.Kind = f.Kind
 	if participant.Kind == 0 {
@@ -865,6 +1078,73 @@ func (v *View) registerContest(c echo.Context) error {
 	)
 }
 
+// startContest starts personal timer of regular participant in
+// windowed contest.
+func (v *View) startContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var participant *models.ContestParticipant
+	for i, p := range contestCtx.Participants {
+		if p.ID != 0 && p.Kind == models.RegularParticipant {
+			participant = &contestCtx.Participants[i]
+			break
+		}
+	}
+	if participant == nil {
+		return errorResponse{
+			Code:    http.StatusForbidden,
+			Message: localize(c, "Participant not found."),
+		}
+	}
+	if err := participant.SetConfig(models.RegularParticipantConfig{
+		BeginTime: NInt64(contestCtx.Now.Unix()),
+	}); err != nil {
+		return err
+	}
+	if err := v.core.ContestParticipants.Update(
+		getContext(c), *participant,
+	); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusOK,
+		makeContestParticipant(*participant, v.core),
+	)
+}
+
+func (v *View) startVirtualContest(c echo.Context) error {
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	contest := contestCtx.Contest
+	account := contestCtx.Account
+	if account == nil {
+		return fmt.Errorf("account not extracted")
+	}
+	participant := models.ContestParticipant{
+		Kind:      models.VirtualParticipant,
+		ContestID: contest.ID,
+		AccountID: account.ID,
+	}
+	if err := participant.SetConfig(models.RegularParticipantConfig{
+		BeginTime: NInt64(contestCtx.Now.Unix()),
+	}); err != nil {
+		return err
+	}
+	if err := v.core.ContestParticipants.Create(
+		getContext(c), &participant,
+	); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusCreated,
+		makeContestParticipant(participant, v.core),
+	)
+}
+
 // ContestSolutions represents contest solutions response.
 type ContestSolutions struct {
 	Solutions []ContestSolution `json:"solutions"`
@@ -914,9 +1194,19 @@ func (v *View) rejudgeContestSolution(c echo.Context) error {
 	if !ok {
 		return fmt.Errorf("solution not extracted")
 	}
+	contestCtx, ok := c.Get(contestCtxKey).(*managers.ContestContext)
+	if !ok {
+		return fmt.Errorf("contest not extracted")
+	}
+	var revisionID int64
+	if problem, err := v.core.ContestProblems.Get(solution.ProblemID); err == nil {
+		revisionID = getContestProblemRevisionID(problem)
+	}
 	task := models.Task{}
 	if err := task.SetConfig(models.JudgeSolutionTaskConfig{
 		SolutionID: solution.SolutionID,
+		RevisionID: revisionID,
+		Pretests:   isContestPretests(contestCtx),
 	}); err != nil {
 		return err
 	}
@@ -930,6 +1220,121 @@ func (v *View) rejudgeContestSolution(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+type StressSolutionForm struct {
+	Generator  string `json:"generator"`
+	Iterations int    `json:"iterations"`
+	TimeBudget int64  `json:"time_budget"`
+}
+
+func (f StressSolutionForm) Update(
+	c echo.Context, config *models.StressSolutionTaskConfig,
+) error {
+	errors := errorFields{}
+	if len(strings.Fields(f.Generator)) == 0 {
+		errors["generator"] = errorField{
+			Message: localize(c, "Generator is required."),
+		}
+	} else if len(f.Generator) > 256 {
+		errors["generator"] = errorField{
+			Message: localize(c, "Generator is too long."),
+		}
+	}
+	if f.Iterations < 0 || f.Iterations > 100000 {
+		errors["iterations"] = errorField{
+			Message: localize(c, "Invalid amount of iterations."),
+		}
+	}
+	if f.TimeBudget < 0 || f.TimeBudget > 600 {
+		errors["time_budget"] = errorField{
+			Message: localize(c, "Invalid time budget."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config.Generator = f.Generator
+	config.Iterations = f.Iterations
+	config.TimeBudget = f.TimeBudget
+	return nil
+}
+
+// StressSolution represents state of stress testing of solution.
+type StressSolution struct {
+	Status         string                       `json:"status"`
+	Generator      string                       `json:"generator"`
+	Iterations     int                          `json:"iterations"`
+	Counterexample *models.StressCounterexample `json:"counterexample,omitempty"`
+}
+
+func makeStressSolution(task models.Task) StressSolution {
+	resp := StressSolution{Status: task.Status.String()}
+	var config models.StressSolutionTaskConfig
+	if err := task.ScanConfig(&config); err == nil {
+		resp.Generator = config.Generator
+	}
+	var state models.StressSolutionTaskState
+	if err := task.ScanState(&state); err == nil {
+		resp.Iterations = state.Iterations
+		resp.Counterexample = state.Counterexample
+	}
+	return resp
+}
+
+func (v *View) stressContestSolution(c echo.Context) error {
+	solution, ok := c.Get(contestSolutionKey).(models.ContestSolution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	var form StressSolutionForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	config := models.StressSolutionTaskConfig{SolutionID: solution.SolutionID}
+	if err := form.Update(c, &config); err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := task.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.Tasks.Create(getContext(c), &task); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeStressSolution(task))
+}
+
+func (v *View) observeContestSolutionStress(c echo.Context) error {
+	solution, ok := c.Get(contestSolutionKey).(models.ContestSolution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	if err := syncStore(c, v.core.Tasks); err != nil {
+		return err
+	}
+	tasks, err := v.core.Tasks.FindBySolution(solution.SolutionID)
+	if err != nil {
+		return err
+	}
+	var lastTask models.Task
+	for _, task := range tasks {
+		if task.Kind == models.StressSolutionTask && task.ID > lastTask.ID {
+			lastTask = task
+		}
+	}
+	if lastTask.ID == 0 {
+		return errorResponse{
+			Code:    http.StatusNotFound,
+			Message: localize(c, "Stress testing not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, makeStressSolution(lastTask))
+}
+
 type ContestSolution struct {
 	ID          int64               `json:"id"`
 	ContestID   int64               `json:"contest_id"`
@@ -1008,7 +1413,9 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 			if err := participant.ScanConfig(&config); err != nil {
 				return err
 			}
-			if config.BeginTime != contestConfig.BeginTime {
+			// Participants of windowed contests have personal begin time.
+			if contestConfig.PersonalDuration == 0 &&
+				config.BeginTime != contestConfig.BeginTime {
 				config.BeginTime = contestConfig.BeginTime
 				if err := participant.SetConfig(config); err != nil {
 					return err
@@ -1041,7 +1448,7 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 			Message: localize(c, "File is too large."),
 		}
 	}
-	if _, err := v.core.Compilers.Get(form.CompilerID); err != nil {
+	if compiler, err := v.core.Compilers.Get(form.CompilerID); err != nil {
 		if err == sql.ErrNoRows {
 			return errorResponse{
 				Code:    http.StatusBadRequest,
@@ -1049,6 +1456,11 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 			}
 		}
 		return err
+	} else if compiler.IsBroken() {
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Compiler is unavailable."),
+		}
 	}
 	solution := models.Solution{
 		ProblemID:  problem.ProblemID,
@@ -1082,6 +1494,8 @@ func (v *View) submitContestProblemSolution(c echo.Context) error {
 		task := models.Task{}
 		if err := task.SetConfig(models.JudgeSolutionTaskConfig{
 			SolutionID: solution.ID,
+			RevisionID: getContestProblemRevisionID(problem),
+			Pretests:   isContestPretests(contestCtx),
 		}); err != nil {
 			return err
 		}
@@ -1141,6 +1555,13 @@ func makeContestParticipant(
 		ContestID: participant.ContestID,
 		Kind:      participant.Kind,
 	}
+	switch participant.Kind {
+	case models.RegularParticipant, models.VirtualParticipant:
+		var config models.RegularParticipantConfig
+		if err := participant.ScanConfig(&config); err == nil {
+			resp.BeginTime = config.BeginTime
+		}
+	}
 	if account, err := core.Accounts.Get(participant.AccountID); err == nil {
 		switch account.Kind {
 		case models.UserAccount:
@@ -1158,6 +1579,10 @@ func makeContestParticipant(
 					Title: string(user.Title),
 				}
 			}
+		case models.TeamAccount:
+			if team, err := core.Teams.GetByAccount(account.ID); err == nil {
+				resp.Team = getPtr(makeTeam(team))
+			}
 		}
 	}
 	return resp
@@ -1176,6 +1601,15 @@ func (v *View) extractContest(next echo.HandlerFunc) echo.HandlerFunc {
 		if err := syncStore(c, v.core.Contests); err != nil {
 			return err
 		}
+		if err := syncStore(c, v.core.ContestParticipants); err != nil {
+			return err
+		}
+		if err := syncStore(c, v.core.Teams); err != nil {
+			return err
+		}
+		if err := syncStore(c, v.core.TeamMembers); err != nil {
+			return err
+		}
 		contest, err := v.core.Contests.Get(id)
 		if err != nil {
 			if err == sql.ErrNoRows {
@@ -169,6 +169,207 @@ func TestContestParticipation(t *testing.T) {
 	}()
 }
 
+func TestContestVirtualParticipation(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	var contest Contest
+	func() {
+		user := NewTestUser(e)
+		user.AddRoles("observe_contest", "create_contest", "update_contest")
+		user.LoginClient()
+		defer user.LogoutClient()
+		contestForm := createContestForm{
+			Title:         getPtr("Test contest"),
+			BeginTime:     getPtr(NInt64(e.Now.Add(-3 * time.Hour).Unix())),
+			Duration:      getPtr(3600),
+			EnableVirtual: getPtr(true),
+		}
+		var err error
+		if contest, err = e.Client.CreateContest(contestForm); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	user := NewTestUser(e)
+	user.AddRoles("register_contests")
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	participant, err := e.Client.StartVirtualContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if participant.Kind != models.VirtualParticipant {
+		t.Fatalf("Expected virtual participant, got %v", participant.Kind)
+	}
+	if participant.BeginTime != NInt64(e.Now.Unix()) {
+		t.Fatalf("Invalid begin time: %d", participant.BeginTime)
+	}
+	if _, err := e.Client.StartVirtualContest(ctx, contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	resp, err := e.Client.ObserveContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if resp.State == nil || resp.State.Stage != "started" {
+		t.Fatalf("Expected started stage, got %+v", resp.State)
+	}
+	e.Now = e.Now.Add(time.Hour)
+	resp, err = e.Client.ObserveContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if resp.State == nil || resp.State.Stage != "finished" {
+		t.Fatalf("Expected finished stage, got %+v", resp.State)
+	}
+}
+
+func TestContestWindowParticipation(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	var contest Contest
+	func() {
+		user := NewTestUser(e)
+		user.AddRoles("observe_contest", "create_contest", "update_contest")
+		user.LoginClient()
+		defer user.LogoutClient()
+		contestForm := createContestForm{
+			Title:              getPtr("Test contest"),
+			BeginTime:          getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+			Duration:           getPtr(7 * 24 * 3600),
+			PersonalDuration:   getPtr(5 * 3600),
+			EnableRegistration: getPtr(true),
+		}
+		var err error
+		if contest, err = e.Client.CreateContest(contestForm); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}()
+	user := NewTestUser(e)
+	user.AddRoles("register_contests")
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	if _, err := e.Client.RegisterContest(ctx, contest.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	observeStage := func(expected string) {
+		resp, err := e.Client.ObserveContest(ctx, contest.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if resp.State == nil || resp.State.Stage != expected {
+			t.Fatalf("Expected %q stage, got %+v", expected, resp.State)
+		}
+	}
+	observeStage("not_started")
+	participant, err := e.Client.StartContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if participant.BeginTime != NInt64(e.Now.Unix()) {
+		t.Fatalf("Invalid begin time: %d", participant.BeginTime)
+	}
+	if _, err := e.Client.StartContest(ctx, contest.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	observeStage("started")
+	e.Now = e.Now.Add(5 * time.Hour)
+	observeStage("finished")
+}
+
+func TestContestSolutionStress(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_contest", "create_contest", "update_contest")
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	contest, err := e.Client.CreateContest(testSimpleContest)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var fakeFile models.File
+	if err := e.Core.Files.Create(ctx, &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem", PackageID: NInt64(fakeFile.ID)}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem, err := e.Client.CreateContestProblem(contest.ID, createContestProblemForm{
+		Code:      getPtr("A"),
+		ProblemID: getPtr(problem.ID),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "test", ImageID: fakeFile.ID}
+	if err := e.Core.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant := models.ContestParticipant{
+		ContestID: contest.ID,
+		AccountID: user.ID,
+		Kind:      models.ManagerParticipant,
+	}
+	if err := e.Core.ContestParticipants.Create(ctx, &participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   user.ID,
+		CreateTime: e.Now.Unix(),
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		SolutionID:    solution.ID,
+		ContestID:     contest.ID,
+		ParticipantID: participant.ID,
+		ProblemID:     contestProblem.ID,
+	}
+	if err := e.Core.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ObserveContestSolutionStress(
+		ctx, contest.ID, contestSolution.ID,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := e.Client.StressContestSolution(
+		ctx, contest.ID, contestSolution.ID, StressSolutionForm{},
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	form := StressSolutionForm{Generator: "gen -n 10", Iterations: 100}
+	if resp, err := e.Client.StressContestSolution(
+		ctx, contest.ID, contestSolution.ID, form,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if resp.Status != models.QueuedTask.String() {
+		t.Fatalf("Expected %q, got %q", models.QueuedTask.String(), resp.Status)
+	}
+	resp, err := e.Client.ObserveContestSolutionStress(ctx, contest.ID, contestSolution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if resp.Generator != form.Generator {
+		t.Fatalf("Expected %q, got %q", form.Generator, resp.Generator)
+	}
+}
+
 func BenchmarkContests(b *testing.B) {
 	e := NewTestEnv(b)
 	defer e.Close()
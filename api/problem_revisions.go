@@ -0,0 +1,349 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+// registerProblemRevisionHandlers registers handlers for problem revisions.
+func (v *View) registerProblemRevisionHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/problems/:problem/revisions", v.observeProblemRevisions,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+	)
+	g.GET(
+		"/v0/problems/:problem/revisions/:revision",
+		v.observeProblemRevision,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+		v.extractProblemRevision,
+	)
+	g.GET(
+		"/v0/problems/:problem/revisions/:revision/diff",
+		v.diffProblemRevision,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+		v.extractProblemRevision,
+	)
+	g.POST(
+		"/v0/problems/:problem/revisions/:revision/rollback",
+		v.rollbackProblemRevision,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+		v.extractProblemRevision,
+	)
+}
+
+type ProblemRevision struct {
+	ID          int64                     `json:"id"`
+	ProblemID   int64                     `json:"problem_id"`
+	AuthorID    int64                     `json:"author_id,omitempty"`
+	CreateTime  int64                     `json:"create_time"`
+	Changelog   string                    `json:"changelog,omitempty"`
+	PackageKind models.ProblemPackageKind `json:"package_kind,omitempty"`
+	TimeLimit   int64                     `json:"time_limit,omitempty"`
+	MemoryLimit int64                     `json:"memory_limit,omitempty"`
+	TestCount   int                       `json:"test_count,omitempty"`
+	Compiled    bool                      `json:"compiled,omitempty"`
+	Current     bool                      `json:"current,omitempty"`
+}
+
+type ProblemRevisions struct {
+	Revisions []ProblemRevision `json:"revisions"`
+}
+
+func makeProblemRevision(
+	problem models.Problem, revision models.ProblemRevision,
+) ProblemRevision {
+	resp := ProblemRevision{
+		ID:         revision.ID,
+		ProblemID:  revision.ProblemID,
+		AuthorID:   int64(revision.AuthorID),
+		CreateTime: revision.CreateTime,
+		Compiled:   revision.CompiledID != 0,
+		Current:    getProblemRevisionID(problem) == revision.ID,
+	}
+	if config, err := revision.GetConfig(); err == nil {
+		resp.Changelog = config.Changelog
+		resp.PackageKind = config.PackageKind
+		resp.TimeLimit = config.TimeLimit
+		resp.MemoryLimit = config.MemoryLimit
+		resp.TestCount = config.TestCount
+	}
+	return resp
+}
+
+func (v *View) observeProblemRevisions(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	if err := syncStore(c, v.core.ProblemRevisions); err != nil {
+		return err
+	}
+	revisions, err := v.core.ProblemRevisions.FindByProblem(problem.ID)
+	if err != nil {
+		return err
+	}
+	resp := ProblemRevisions{}
+	for _, revision := range revisions {
+		resp.Revisions = append(
+			resp.Revisions, makeProblemRevision(problem, revision),
+		)
+	}
+	sortFunc(resp.Revisions, problemRevisionGreater)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) observeProblemRevision(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	revision, ok := c.Get(problemRevisionKey).(models.ProblemRevision)
+	if !ok {
+		return fmt.Errorf("revision not extracted")
+	}
+	return c.JSON(http.StatusOK, makeProblemRevision(problem, revision))
+}
+
+// ProblemRevisionChange represents change of revision field.
+type ProblemRevisionChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+type ProblemRevisionDiff struct {
+	Base     *ProblemRevision        `json:"base,omitempty"`
+	Revision ProblemRevision         `json:"revision"`
+	Changes  []ProblemRevisionChange `json:"changes"`
+}
+
+type diffProblemRevisionForm struct {
+	BaseID int64 `query:"base_id"`
+}
+
+func (v *View) diffProblemRevision(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	revision, ok := c.Get(problemRevisionKey).(models.ProblemRevision)
+	if !ok {
+		return fmt.Errorf("revision not extracted")
+	}
+	var form diffProblemRevisionForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Form has invalid fields."),
+		}
+	}
+	revisions, err := v.core.ProblemRevisions.FindByProblem(problem.ID)
+	if err != nil {
+		return err
+	}
+	var base *models.ProblemRevision
+	for i, candidate := range revisions {
+		if form.BaseID != 0 {
+			if candidate.ID == form.BaseID {
+				base = &revisions[i]
+			}
+			continue
+		}
+		// By default revision is compared with previous one.
+		if candidate.ID < revision.ID && (base == nil || candidate.ID > base.ID) {
+			base = &revisions[i]
+		}
+	}
+	if form.BaseID != 0 && base == nil {
+		return errorResponse{
+			Code:    http.StatusNotFound,
+			Message: localize(c, "Revision not found."),
+		}
+	}
+	resp := ProblemRevisionDiff{
+		Revision: makeProblemRevision(problem, revision),
+		Changes:  []ProblemRevisionChange{},
+	}
+	var baseResp ProblemRevision
+	if base != nil {
+		baseResp = makeProblemRevision(problem, *base)
+		resp.Base = &baseResp
+	}
+	addChange := func(field string, oldValue, newValue any) {
+		if oldValue != newValue {
+			resp.Changes = append(resp.Changes, ProblemRevisionChange{
+				Field: field,
+				Old:   oldValue,
+				New:   newValue,
+			})
+		}
+	}
+	addChange("package_kind", baseResp.PackageKind, resp.Revision.PackageKind)
+	addChange("time_limit", baseResp.TimeLimit, resp.Revision.TimeLimit)
+	addChange("memory_limit", baseResp.MemoryLimit, resp.Revision.MemoryLimit)
+	addChange("test_count", baseResp.TestCount, resp.Revision.TestCount)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) rollbackProblemRevision(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	revision, ok := c.Get(problemRevisionKey).(models.ProblemRevision)
+	if !ok {
+		return fmt.Errorf("revision not extracted")
+	}
+	revisionConfig, err := revision.GetConfig()
+	if err != nil {
+		return err
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	config = revisionConfig.GetProblemConfig(config)
+	config.RevisionID = revision.ID
+	if err := problem.SetConfig(config); err != nil {
+		return err
+	}
+	problem.PackageID = models.NInt64(revision.PackageID)
+	if revision.CompiledID != 0 {
+		problem.CompiledID = revision.CompiledID
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.core.Problems.Update(ctx, problem); err != nil {
+			return err
+		}
+		// Statements and resources should be restored from package.
+		task := models.Task{}
+		if err := task.SetConfig(models.UpdateProblemPackageTaskConfig{
+			ProblemID:   problem.ID,
+			FileID:      revision.PackageID,
+			Compile:     revision.CompiledID == 0,
+			PackageKind: revisionConfig.PackageKind,
+			RevisionID:  revision.ID,
+		}); err != nil {
+			return err
+		}
+		return v.core.Tasks.Create(ctx, &task)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeProblemRevision(problem, revision))
+}
+
+// updateProblemRevisionSettings stores judging settings of problem in
+// its current revision, so contests pinned to current revision are
+// judged with them.
+func (v *View) updateProblemRevisionSettings(
+	ctx context.Context, problem models.Problem,
+) error {
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.RevisionID == 0 {
+		return nil
+	}
+	revision, err := v.core.ProblemRevisions.Get(config.RevisionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	revisionConfig, err := revision.GetConfig()
+	if err != nil {
+		return err
+	}
+	revisionConfig.SetJudgeSettings(config)
+	if err := revision.SetConfig(revisionConfig); err != nil {
+		return err
+	}
+	return v.core.ProblemRevisions.Update(ctx, revision)
+}
+
+// createProblemRevision creates revision for uploaded problem package.
+func (v *View) createProblemRevision(
+	ctx context.Context, c echo.Context, problem models.Problem,
+	file models.File, changelog *string,
+) (models.ProblemRevision, error) {
+	revision := models.ProblemRevision{
+		ProblemID:  problem.ID,
+		CreateTime: getNow(c).Unix(),
+		PackageID:  file.ID,
+	}
+	if accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext); ok {
+		if account := accountCtx.Account; account != nil {
+			revision.AuthorID = models.NInt64(account.ID)
+		}
+	}
+	config := models.ProblemRevisionConfig{
+		PackageKind: getProblemPackageKind(problem),
+	}
+	if changelog != nil {
+		config.Changelog = *changelog
+	}
+	if err := revision.SetConfig(config); err != nil {
+		return models.ProblemRevision{}, err
+	}
+	if err := v.core.ProblemRevisions.Create(ctx, &revision); err != nil {
+		return models.ProblemRevision{}, err
+	}
+	return revision, nil
+}
+
+func (v *View) extractProblemRevision(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		id, err := strconv.ParseInt(c.Param("revision"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Invalid revision ID."),
+			}
+		}
+		problem, ok := c.Get(problemKey).(models.Problem)
+		if !ok {
+			return fmt.Errorf("problem not extracted")
+		}
+		if err := syncStore(c, v.core.ProblemRevisions); err != nil {
+			return err
+		}
+		revision, err := v.core.ProblemRevisions.Get(id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:    http.StatusNotFound,
+					Message: localize(c, "Revision not found."),
+				}
+			}
+			return err
+		}
+		if revision.ProblemID != problem.ID {
+			return errorResponse{
+				Code:    http.StatusNotFound,
+				Message: localize(c, "Revision not found."),
+			}
+		}
+		c.Set(problemRevisionKey, revision)
+		return next(c)
+	}
+}
+
+func problemRevisionGreater(l, r ProblemRevision) bool {
+	return l.ID > r.ID
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"container/list"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/solve/models"
+	"github.com/udovin/solve/pkg/markup"
+)
+
+// ProblemStatementHTML represents problem statement rendered to HTML.
+type ProblemStatementHTML struct {
+	Locale string `json:"locale"`
+	Legend string `json:"legend,omitempty"`
+	Input  string `json:"input,omitempty"`
+	Output string `json:"output,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+}
+
+// htmlStatementFormat represents format of rendered statement.
+const htmlStatementFormat = "html"
+
+type statementCacheKey struct {
+	ResourceID int64
+	URLPrefix  string
+}
+
+type statementCacheValue struct {
+	Key       statementCacheKey
+	Hash      string
+	Statement ProblemStatementHTML
+}
+
+// statementCacheSize contains maximal amount of cached statements.
+const statementCacheSize = 1024
+
+// statementCache contains rendered statements.
+//
+// Statement is rendered again only when config of statement resource
+// has been changed. Least recently used statements are evicted when
+// cache contains more than limit entries.
+type statementCache struct {
+	statements map[statementCacheKey]*list.Element
+	order      *list.List
+	limit      int
+	mutex      sync.Mutex
+}
+
+func newStatementCache(limit int) *statementCache {
+	return &statementCache{
+		statements: map[statementCacheKey]*list.Element{},
+		order:      list.New(),
+		limit:      limit,
+	}
+}
+
+func (s *statementCache) get(
+	key statementCacheKey, hash string,
+) (ProblemStatementHTML, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	elem, ok := s.statements[key]
+	if !ok {
+		return ProblemStatementHTML{}, false
+	}
+	value := elem.Value.(statementCacheValue)
+	if value.Hash != hash {
+		return ProblemStatementHTML{}, false
+	}
+	s.order.MoveToFront(elem)
+	return value.Statement, true
+}
+
+func (s *statementCache) set(value statementCacheValue) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if elem, ok := s.statements[value.Key]; ok {
+		elem.Value = value
+		s.order.MoveToFront(elem)
+		return
+	}
+	s.statements[value.Key] = s.order.PushFront(value)
+	for s.order.Len() > s.limit {
+		elem := s.order.Back()
+		s.order.Remove(elem)
+		delete(s.statements, elem.Value.(statementCacheValue).Key)
+	}
+}
+
+func (s *statementCache) Render(
+	resource models.ProblemResource, urlPrefix string,
+) (ProblemStatementHTML, error) {
+	hashBytes := md5.Sum(resource.Config)
+	hash := hex.EncodeToString(hashBytes[:])
+	key := statementCacheKey{ResourceID: resource.ID, URLPrefix: urlPrefix}
+	if statement, ok := s.get(key, hash); ok {
+		return statement, nil
+	}
+	var config models.ProblemStatementConfig
+	if err := resource.ScanConfig(&config); err != nil {
+		return ProblemStatementHTML{}, err
+	}
+	options := markup.Options{
+		ResourceURL: func(name string) string {
+			return urlPrefix + url.PathEscape(name)
+		},
+	}
+	statement := ProblemStatementHTML{
+		Locale: config.Locale,
+		Legend: markup.RenderHTML(config.Legend, options),
+		Input:  markup.RenderHTML(config.Input, options),
+		Output: markup.RenderHTML(config.Output, options),
+		Notes:  markup.RenderHTML(config.Notes, options),
+	}
+	s.set(statementCacheValue{Key: key, Hash: hash, Statement: statement})
+	return statement, nil
+}
+
+// isStatementFormatRequested returns true if client requests statement
+// in specified format.
+func isStatementFormatRequested(c echo.Context, format string) bool {
+	return c.QueryParam("statement_format") == format
+}
+
+// makeProblemStatementHTML renders statement of problem for current locale.
+//
+// Statement resources are resolved relative to specified URL prefix.
+func (v *View) makeProblemStatementHTML(
+	c echo.Context, problemID int64, urlPrefix string,
+) *ProblemStatementHTML {
+	resources, err := v.core.ProblemResources.FindByProblem(problemID)
+	if err != nil {
+		return nil
+	}
+	locale := getLocale(c)
+	var found *models.ProblemResource
+	for i, resource := range resources {
+		if resource.Kind != models.ProblemStatement {
+			continue
+		}
+		var config models.ProblemStatementConfig
+		if err := resource.ScanConfig(&config); err != nil {
+			continue
+		}
+		if found == nil || config.Locale == locale.Name() {
+			found = &resources[i]
+		}
+		if config.Locale == locale.Name() {
+			break
+		}
+	}
+	if found == nil {
+		return nil
+	}
+	statement, err := v.statements.Render(*found, urlPrefix)
+	if err != nil {
+		c.Logger().Warn(err)
+		return nil
+	}
+	return &statement
+}
+
+func getProblemResourceURLPrefix(problemID int64) string {
+	return fmt.Sprintf("/v0/problems/%d/resources/", problemID)
+}
+
+func getContestProblemResourceURLPrefix(contestID int64, code string) string {
+	return fmt.Sprintf(
+		"/v0/contests/%d/problems/%s/resources/",
+		contestID, url.PathEscape(code),
+	)
+}
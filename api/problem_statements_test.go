@@ -0,0 +1,43 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestStatementCache(t *testing.T) {
+	cache := newStatementCache(2)
+	render := func(id int64, legend string) ProblemStatementHTML {
+		resource := models.ProblemResource{Kind: models.ProblemStatement}
+		resource.ID = id
+		if err := resource.SetConfig(models.ProblemStatementConfig{
+			Locale: "en",
+			Legend: legend,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		statement, err := cache.Render(resource, "/resources/")
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		return statement
+	}
+	first := render(1, "First")
+	if updated := render(1, "Updated"); updated.Legend == first.Legend {
+		t.Fatal("Expected updated statement:", updated)
+	}
+	render(2, "Second")
+	render(1, "Updated")
+	render(3, "Third")
+	if len(cache.statements) != 2 || cache.order.Len() != 2 {
+		t.Fatal("Invalid cache size:", len(cache.statements))
+	}
+	for _, id := range []int64{1, 3} {
+		if _, ok := cache.statements[statementCacheKey{
+			ResourceID: id, URLPrefix: "/resources/",
+		}]; !ok {
+			t.Fatalf("Expected statement %d in cache", id)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"archive/zip"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"path"
 	"strconv"
 	"strings"
 
@@ -40,11 +44,31 @@ func (v *View) registerProblemHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth), v.extractProblem,
 		v.requirePermission(models.UpdateProblemRole),
 	)
+	g.POST(
+		"/v0/problems/:problem/calibrate", v.calibrateProblem,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+	)
+	g.GET(
+		"/v0/problems/:problem/calibration", v.observeProblemCalibration,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+	)
+	g.POST(
+		"/v0/problems/:problem/calibration/apply", v.applyProblemCalibration,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+	)
 	g.GET(
 		"/v0/problems/:problem/resources/:resource", v.observeProblemResource,
 		v.extractAuth(v.sessionAuth), v.extractProblem,
 		v.requirePermission(models.ObserveProblemRole),
 	)
+	g.GET(
+		"/v0/problems/:problem/export", v.exportProblem,
+		v.extractAuth(v.sessionAuth), v.extractProblem,
+		v.requirePermission(models.UpdateProblemRole),
+	)
 	g.DELETE(
 		"/v0/problems/:problem", v.deleteProblem,
 		v.extractAuth(v.sessionAuth), v.extractProblem,
@@ -55,11 +79,15 @@ func (v *View) registerProblemHandlers(g *echo.Group) {
 type ProblemStatement = models.ProblemStatementConfig
 
 type Problem struct {
-	ID          int64                 `json:"id"`
-	Title       string                `json:"title"`
-	Statement   *ProblemStatement     `json:"statement,omitempty"`
-	Config      *models.ProblemConfig `json:"config,omitempty"`
-	Permissions []string              `json:"permissions,omitempty"`
+	ID        int64             `json:"id"`
+	Title     string            `json:"title"`
+	Statement *ProblemStatement `json:"statement,omitempty"`
+	// StatementHTML contains statement rendered to sanitized HTML.
+	//
+	// It is provided only when requested with "statement_format=html".
+	StatementHTML *ProblemStatementHTML `json:"statement_html,omitempty"`
+	Config        *models.ProblemConfig `json:"config,omitempty"`
+	Permissions   []string              `json:"permissions,omitempty"`
 }
 
 type Problems struct {
@@ -88,6 +116,16 @@ func (v *View) makeProblem(
 				TimeLimit:   config.TimeLimit,
 				MemoryLimit: config.MemoryLimit,
 			}
+			// Problem statement contains only one time limit, so we
+			// show the largest one.
+			if len(config.TimeLimitOverrides) > 0 {
+				resp.Config.TimeLimit = 0
+				for _, timeLimit := range config.TimeLimitOverrides {
+					if timeLimit > resp.Config.TimeLimit {
+						resp.Config.TimeLimit = timeLimit
+					}
+				}
+			}
 		}
 	}
 	locale := getLocale(c)
@@ -121,6 +159,11 @@ func (v *View) makeProblem(
 			}
 		}
 	}
+	if withStatement && isStatementFormatRequested(c, htmlStatementFormat) {
+		resp.StatementHTML = v.makeProblemStatementHTML(
+			c, problem.ID, getProblemResourceURLPrefix(problem.ID),
+		)
+	}
 	for _, permission := range problemPermissions {
 		if permissions.HasPermission(permission) {
 			resp.Permissions = append(resp.Permissions, permission)
@@ -245,8 +288,209 @@ func (v *View) observeProblemResource(c echo.Context) error {
 	return v.observeFileContent(c)
 }
 
+// ProblemExportKind represents kind of exported problem package.
+type ProblemExportKind string
+
+const (
+	// PackageProblemExport represents export of original problem package.
+	PackageProblemExport ProblemExportKind = "package"
+	// CompiledProblemExport represents export of compiled problem package.
+	CompiledProblemExport ProblemExportKind = "compiled"
+)
+
+// ProblemExportResource represents exported problem resource.
+type ProblemExportResource struct {
+	Locale string `json:"locale"`
+	Name   string `json:"name"`
+	MD5    string `json:"md5,omitempty"`
+	Path   string `json:"path"`
+}
+
+// ProblemExportManifest represents manifest of exported problem.
+//
+// Manifest is stored in "manifest.json" file of exported archive.
+type ProblemExportManifest struct {
+	ID          int64                     `json:"id"`
+	Title       string                    `json:"title"`
+	Kind        ProblemExportKind         `json:"kind"`
+	PackageKind models.ProblemPackageKind `json:"package_kind,omitempty"`
+	Package     string                    `json:"package"`
+	Config      models.ProblemConfig      `json:"config"`
+	Statements  []ProblemStatement        `json:"statements,omitempty"`
+	Resources   []ProblemExportResource   `json:"resources,omitempty"`
+}
+
+const problemExportManifestName = "manifest.json"
+
+type exportProblemForm struct {
+	Kind ProblemExportKind `query:"kind"`
+}
+
+func (v *View) exportProblem(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	var form exportProblemForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Form has invalid fields."),
+		}
+	}
+	var packageID int64
+	switch form.Kind {
+	case "", PackageProblemExport:
+		form.Kind = PackageProblemExport
+		packageID = int64(problem.PackageID)
+	case CompiledProblemExport:
+		packageID = int64(problem.CompiledID)
+	default:
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"kind": errorField{
+					Message: localize(c, "Invalid export kind."),
+				},
+			},
+		}
+	}
+	if packageID == 0 {
+		return errorResponse{
+			Code:    http.StatusNotFound,
+			Message: localize(c, "File not found."),
+		}
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	manifest := ProblemExportManifest{
+		ID:          problem.ID,
+		Title:       problem.Title,
+		Kind:        form.Kind,
+		PackageKind: config.PackageKind,
+		Package:     string(form.Kind) + ".zip",
+		Config:      config,
+	}
+	resources, err := v.core.ProblemResources.FindByProblem(problem.ID)
+	if err != nil {
+		return err
+	}
+	var resourceFiles []int64
+	for _, resource := range resources {
+		switch resource.Kind {
+		case models.ProblemStatement:
+			var config models.ProblemStatementConfig
+			if err := resource.ScanConfig(&config); err != nil {
+				return err
+			}
+			manifest.Statements = append(manifest.Statements, config)
+		case models.ProblemStatementResource:
+			if resource.FileID == 0 {
+				continue
+			}
+			var config models.ProblemStatementResourceConfig
+			if err := resource.ScanConfig(&config); err != nil {
+				return err
+			}
+			file, err := v.core.Files.Get(int64(resource.FileID))
+			if err != nil {
+				return err
+			}
+			meta, err := file.GetMeta()
+			if err != nil {
+				return err
+			}
+			manifest.Resources = append(manifest.Resources, ProblemExportResource{
+				Locale: config.Locale,
+				Name:   config.Name,
+				MD5:    meta.MD5,
+				Path:   path.Join("resources", config.Locale, path.Base(config.Name)),
+			})
+			resourceFiles = append(resourceFiles, file.ID)
+		}
+	}
+	// All files should be opened before response is started,
+	// otherwise client will receive truncated archive with 200 OK.
+	ctx := c.Request().Context()
+	files := []problemExportFile{{name: manifest.Package, id: packageID}}
+	for i, resource := range manifest.Resources {
+		files = append(files, problemExportFile{
+			name: resource.Path,
+			id:   resourceFiles[i],
+		})
+	}
+	defer func() {
+		for _, file := range files {
+			if file.content != nil {
+				_ = file.content.Close()
+			}
+		}
+	}()
+	for i := range files {
+		content, err := v.files.DownloadFile(ctx, files[i].id)
+		if err != nil {
+			return err
+		}
+		files[i].content = content
+	}
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=\"problem-%d.zip\"", problem.ID),
+	)
+	c.Response().Header().Set(echo.HeaderContentType, "application/zip")
+	c.Response().WriteHeader(http.StatusOK)
+	writer := zip.NewWriter(c.Response())
+	defer func() { _ = writer.Close() }()
+	if err := writeProblemExportJSON(writer, problemExportManifestName, manifest); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := writeProblemExportFile(writer, file.name, file.content); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+type problemExportFile struct {
+	name    string
+	id      int64
+	content io.ReadCloser
+}
+
+func writeProblemExportJSON(writer *zip.Writer, name string, value any) error {
+	file, err := writer.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func writeProblemExportFile(
+	writer *zip.Writer, name string, content io.Reader,
+) error {
+	file, err := writer.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, content)
+	return err
+}
+
 type UpdateProblemForm struct {
-	Title       *string     `json:"title" form:"title"`
+	Title       *string `json:"title" form:"title"`
+	PackageKind *string `json:"package_kind" form:"package_kind"`
+	Changelog   *string `json:"changelog" form:"changelog"`
+	// CompilerLimits contains problem specific compiler limits.
+	CompilerLimits JSON `json:"compiler_limits" form:"compiler_limits"`
+	// Pretests contains tests used for judging during contest.
+	Pretests    JSON        `json:"pretests" form:"pretests"`
 	PackageFile *FileReader `json:"-"`
 }
 
@@ -291,6 +535,69 @@ func (f *UpdateProblemForm) Update(c echo.Context, problem *models.Problem) erro
 		}
 		problem.Title = *f.Title
 	}
+	if f.PackageKind != nil {
+		kind := models.ProblemPackageKind(*f.PackageKind)
+		switch kind {
+		case models.PolygonProblemPackage, models.ICPCProblemPackage:
+			if f.PackageFile == nil {
+				errors["file"] = errorField{
+					Message: localize(c, "File is required."),
+				}
+			}
+			config, err := problem.GetConfig()
+			if err != nil {
+				return err
+			}
+			config.PackageKind = kind
+			if err := problem.SetConfig(config); err != nil {
+				return err
+			}
+		default:
+			errors["package_kind"] = errorField{
+				Message: localize(c, "Invalid package kind."),
+			}
+		}
+	}
+	if f.Changelog != nil {
+		if f.PackageFile == nil {
+			errors["file"] = errorField{
+				Message: localize(c, "File is required."),
+			}
+		}
+		if len(*f.Changelog) > 1024 {
+			errors["changelog"] = errorField{
+				Message: localize(c, "Changelog is too long."),
+			}
+		}
+	}
+	if f.CompilerLimits.JSON != nil {
+		limits := parseCompilerLimits(c, f.CompilerLimits, "compiler_limits", errors)
+		config, err := problem.GetConfig()
+		if err != nil {
+			return err
+		}
+		config.CompilerLimits = limits
+		if err := problem.SetConfig(config); err != nil {
+			return err
+		}
+	}
+	if f.Pretests.JSON != nil {
+		var pretests *models.ProblemPretestsConfig
+		if err := json.Unmarshal(f.Pretests.JSON, &pretests); err != nil ||
+			(pretests != nil && pretests.Count < 0) {
+			errors["pretests"] = errorField{
+				Message: localize(c, "Invalid pretests."),
+			}
+		}
+		config, err := problem.GetConfig()
+		if err != nil {
+			return err
+		}
+		config.Pretests = pretests
+		if err := problem.SetConfig(config); err != nil {
+			return err
+		}
+	}
 	if len(errors) > 0 {
 		return errorResponse{
 			Message:       localize(c, "Form has invalid fields."),
@@ -355,11 +662,19 @@ func (v *View) createProblem(c echo.Context) error {
 		if err := v.core.Problems.Create(ctx, &problem); err != nil {
 			return err
 		}
+		revision, err := v.createProblemRevision(
+			ctx, c, problem, file, form.Changelog,
+		)
+		if err != nil {
+			return err
+		}
 		task := models.Task{}
 		if err := task.SetConfig(models.UpdateProblemPackageTaskConfig{
-			ProblemID: problem.ID,
-			FileID:    file.ID,
-			Compile:   true,
+			ProblemID:   problem.ID,
+			FileID:      file.ID,
+			Compile:     true,
+			PackageKind: getProblemPackageKind(problem),
+			RevisionID:  revision.ID,
 		}); err != nil {
 			return err
 		}
@@ -395,16 +710,27 @@ func (v *View) updateProblem(c echo.Context) error {
 		}
 		formFile = &file
 	}
+	if err := syncStore(c, v.core.ProblemRevisions); err != nil {
+		return err
+	}
 	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
 		if formFile != nil {
 			if err := v.files.ConfirmUploadFile(ctx, formFile); err != nil {
 				return err
 			}
+			revision, err := v.createProblemRevision(
+				ctx, c, problem, *formFile, form.Changelog,
+			)
+			if err != nil {
+				return err
+			}
 			task := models.Task{}
 			if err := task.SetConfig(models.UpdateProblemPackageTaskConfig{
-				ProblemID: problem.ID,
-				FileID:    formFile.ID,
-				Compile:   true,
+				ProblemID:   problem.ID,
+				FileID:      formFile.ID,
+				Compile:     true,
+				PackageKind: getProblemPackageKind(problem),
+				RevisionID:  revision.ID,
 			}); err != nil {
 				return err
 			}
@@ -412,6 +738,9 @@ func (v *View) updateProblem(c echo.Context) error {
 				return err
 			}
 		}
+		if err := v.updateProblemRevisionSettings(ctx, problem); err != nil {
+			return err
+		}
 		return v.core.Problems.Update(ctx, problem)
 	}, sqlRepeatableRead); err != nil {
 		return err
@@ -455,9 +784,11 @@ func (v *View) rebuildProblem(c echo.Context) error {
 	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
 		task := models.Task{}
 		if err := task.SetConfig(models.UpdateProblemPackageTaskConfig{
-			ProblemID: problem.ID,
-			FileID:    int64(problem.PackageID),
-			Compile:   problem.CompiledID == 0 || form.Compile,
+			ProblemID:   problem.ID,
+			FileID:      int64(problem.PackageID),
+			Compile:     problem.CompiledID == 0 || form.Compile,
+			PackageKind: getProblemPackageKind(problem),
+			RevisionID:  getProblemRevisionID(problem),
 		}); err != nil {
 			return err
 		}
@@ -471,6 +802,181 @@ func (v *View) rebuildProblem(c echo.Context) error {
 	)
 }
 
+type CalibrateProblemForm struct {
+	Runs           int     `json:"runs"`
+	TimeMultiplier float64 `json:"time_multiplier"`
+	TimeRounding   int64   `json:"time_rounding"`
+}
+
+func (f CalibrateProblemForm) Update(
+	c echo.Context, config *models.CalibrateProblemTaskConfig,
+) error {
+	errors := errorFields{}
+	if f.Runs < 0 || f.Runs > 10 {
+		errors["runs"] = errorField{
+			Message: localize(c, "Invalid amount of runs."),
+		}
+	}
+	if f.TimeMultiplier < 0 || f.TimeMultiplier > 10 {
+		errors["time_multiplier"] = errorField{
+			Message: localize(c, "Invalid time multiplier."),
+		}
+	}
+	if f.TimeRounding < 0 {
+		errors["time_rounding"] = errorField{
+			Message: localize(c, "Invalid time rounding."),
+		}
+	}
+	if len(errors) > 0 {
+		return errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	config.Runs = f.Runs
+	config.TimeMultiplier = f.TimeMultiplier
+	config.TimeRounding = f.TimeRounding
+	return nil
+}
+
+func (v *View) calibrateProblem(c echo.Context) error {
+	var form CalibrateProblemForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	permissions := v.getProblemPermissions(accountCtx, problem)
+	if problem.CompiledID == 0 {
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Problem is not compiled."),
+		}
+	}
+	config := models.CalibrateProblemTaskConfig{ProblemID: problem.ID}
+	if err := form.Update(c, &config); err != nil {
+		return err
+	}
+	task := models.Task{}
+	if err := task.SetConfig(config); err != nil {
+		return err
+	}
+	if err := v.core.Tasks.Create(getContext(c), &task); err != nil {
+		return err
+	}
+	return c.JSON(
+		http.StatusOK,
+		v.makeProblem(c, problem, permissions, false),
+	)
+}
+
+type ProblemCalibration = models.ProblemCalibration
+
+func (v *View) observeProblemCalibration(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.Calibration == nil {
+		return errorResponse{
+			Code:    http.StatusNotFound,
+			Message: localize(c, "Calibration not found."),
+		}
+	}
+	return c.JSON(http.StatusOK, config.Calibration)
+}
+
+func (v *View) applyProblemCalibration(c echo.Context) error {
+	problem, ok := c.Get(problemKey).(models.Problem)
+	if !ok {
+		return fmt.Errorf("problem not extracted")
+	}
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	if config.Calibration == nil || len(config.Calibration.Groups) == 0 {
+		return errorResponse{
+			Code:    http.StatusNotFound,
+			Message: localize(c, "Calibration not found."),
+		}
+	}
+	if !hasSuccessfulCalibration(*config.Calibration) {
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Calibration has no successful solutions."),
+		}
+	}
+	config.TimeLimitOverrides = map[string]int64{}
+	for _, group := range config.Calibration.Groups {
+		if group.SuggestedTimeLimit > 0 {
+			config.TimeLimitOverrides[group.Name] = group.SuggestedTimeLimit
+		}
+	}
+	if err := problem.SetConfig(config); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.ProblemRevisions); err != nil {
+		return err
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		if err := v.updateProblemRevisionSettings(ctx, problem); err != nil {
+			return err
+		}
+		return v.core.Problems.Update(ctx, problem)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	permissions := v.getProblemPermissions(accountCtx, problem)
+	return c.JSON(
+		http.StatusOK,
+		v.makeProblem(c, problem, permissions, true),
+	)
+}
+
+// hasSuccessfulCalibration returns true if calibration contains at least
+// one solution that was run successfully on all groups.
+func hasSuccessfulCalibration(calibration models.ProblemCalibration) bool {
+	for _, solution := range calibration.Solutions {
+		if solution.Error == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func getProblemPackageKind(problem models.Problem) models.ProblemPackageKind {
+	config, err := problem.GetConfig()
+	if err != nil {
+		return ""
+	}
+	return config.PackageKind
+}
+
+func getProblemRevisionID(problem models.Problem) int64 {
+	config, err := problem.GetConfig()
+	if err != nil {
+		return 0
+	}
+	return config.RevisionID
+}
+
 func (v *View) deleteProblem(c echo.Context) error {
 	problem, ok := c.Get(problemKey).(models.Problem)
 	if !ok {
@@ -497,6 +1003,17 @@ func (v *View) deleteProblem(c echo.Context) error {
 				return err
 			}
 		}
+		revisions, err := v.core.ProblemRevisions.FindByProblem(problem.ID)
+		if err != nil {
+			return err
+		}
+		for _, revision := range revisions {
+			if err := v.core.ProblemRevisions.Delete(
+				ctx, revision.ID,
+			); err != nil {
+				return err
+			}
+		}
 		return v.core.Problems.Delete(ctx, problem.ID)
 	}, sqlRepeatableRead); err != nil {
 		return err
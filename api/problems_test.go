@@ -1,9 +1,15 @@
 package api
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/udovin/solve/managers"
@@ -28,6 +34,36 @@ func TestProblemsSimpleScenario(t *testing.T) {
 		t.Fatal("Error:", err)
 	}
 	e.Check(problem)
+	{
+		content, err := e.Client.ExportProblem(context.Background(), problem.ID, PackageProblemExport)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		defer func() { _ = content.Close() }()
+		data, err := io.ReadAll(content)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		manifestFile, err := reader.Open(problemExportManifestName)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		defer func() { _ = manifestFile.Close() }()
+		var manifest ProblemExportManifest
+		if err := json.NewDecoder(manifestFile).Decode(&manifest); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if manifest.Title != "a-plus-b" {
+			t.Fatalf("Expected %q, got %q", "a-plus-b", manifest.Title)
+		}
+		if _, err := reader.Open(manifest.Package); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
 	{
 		file, err := os.Open(filepath.Join("../testdata", "a-plus-b.zip"))
 		if err != nil {
@@ -42,6 +78,21 @@ func TestProblemsSimpleScenario(t *testing.T) {
 		}
 		e.Check(updated)
 	}
+	{
+		revisions, err := e.Client.ObserveProblemRevisions(context.Background(), problem.ID)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if len(revisions.Revisions) != 2 {
+			t.Fatalf("Expected %d revisions, got %d", 2, len(revisions.Revisions))
+		}
+		first := revisions.Revisions[len(revisions.Revisions)-1]
+		if _, err := e.Client.RollbackProblemRevision(
+			context.Background(), problem.ID, first.ID,
+		); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
 	{
 		deleted, err := e.Client.DeleteProblem(context.Background(), problem.ID)
 		if err != nil {
@@ -50,3 +101,128 @@ func TestProblemsSimpleScenario(t *testing.T) {
 		e.Check(deleted)
 	}
 }
+
+func TestExportProblemMissingFile(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles(models.UpdateProblemRole)
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	file := models.File{Status: models.AvailableFile, Path: "missing.zip"}
+	if err := e.Core.Files.Create(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{
+		Title:     "Test problem",
+		PackageID: models.NInt64(file.ID),
+	}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ExportProblem(
+		ctx, problem.ID, PackageProblemExport,
+	); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusInternalServerError, resp.StatusCode())
+	}
+}
+
+func TestProblemCalibration(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles(models.ObserveProblemRole, models.UpdateProblemRole)
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	problem := models.Problem{Title: "Test problem"}
+	if err := problem.SetConfig(models.ProblemConfig{TimeLimit: 1000}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ObserveProblemCalibration(ctx, problem.ID); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := e.Client.ApplyProblemCalibration(ctx, problem.ID); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := e.Client.CalibrateProblem(ctx, problem.ID, CalibrateProblemForm{}); err == nil {
+		t.Fatal("Expected error")
+	}
+	config, err := problem.GetConfig()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	config.Calibration = &models.ProblemCalibration{
+		Groups: []models.ProblemCalibrationGroup{
+			{Name: "samples", TimeLimit: 1000},
+			{Name: "tests", TimeLimit: 1000},
+		},
+		Solutions: []models.ProblemCalibrationSolution{
+			{Name: "main.cpp", Kind: "main", Error: "compilation failed"},
+		},
+	}
+	if err := problem.SetConfig(config); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Problems.Update(ctx, problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.ApplyProblemCalibration(ctx, problem.ID); err == nil {
+		t.Fatal("Expected error")
+	}
+	config.Calibration = &models.ProblemCalibration{
+		TimeLimit: 600,
+		Groups: []models.ProblemCalibrationGroup{
+			{Name: "samples", TimeLimit: 1000, MaxTime: 50, SuggestedTimeLimit: 100},
+			{Name: "tests", TimeLimit: 1000, MaxTime: 250, SuggestedTimeLimit: 600},
+		},
+		Solutions: []models.ProblemCalibrationSolution{
+			{Name: "main.cpp", Kind: "main", Error: "compilation failed"},
+			{Name: "ok.cpp", Kind: "accepted", MaxTime: 250},
+		},
+	}
+	if err := problem.SetConfig(config); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Problems.Update(ctx, problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	calibration, err := e.Client.ObserveProblemCalibration(ctx, problem.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if calibration.TimeLimit != 600 {
+		t.Fatalf("Expected %d, got %d", 600, calibration.TimeLimit)
+	}
+	updated, err := e.Client.ApplyProblemCalibration(ctx, problem.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if updated.Config == nil || updated.Config.TimeLimit != 600 {
+		t.Fatalf("Expected time limit %d, got %v", 600, updated.Config)
+	}
+	if err := e.Core.Problems.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem, err = e.Core.Problems.Get(problem.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	config, err = problem.GetConfig()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if overrides := map[string]int64{"samples": 100, "tests": 600}; !reflect.DeepEqual(
+		config.TimeLimitOverrides, overrides,
+	) {
+		t.Fatalf("Expected %v, got %v", overrides, config.TimeLimitOverrides)
+	}
+}
@@ -26,6 +26,14 @@ func (v *View) registerSolutionHandlers(g *echo.Group) {
 		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractSolution,
 		v.requirePermission(models.ObserveSolutionRole),
 	)
+	g.GET(
+		"/v0/solutions/:solution/tests/:test/:artifact", v.observeSolutionTestArtifact,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractSolution,
+		v.requirePermission(
+			models.ObserveSolutionRole,
+			models.ObserveSolutionReportCheckerLogs,
+		),
+	)
 }
 
 type Solution struct {
@@ -98,12 +106,67 @@ func (v *View) makeSolutionContent(c echo.Context, solution models.Solution) str
 }
 
 type TestReport struct {
-	Verdict    models.Verdict `json:"verdict"`
-	UsedTime   int64          `json:"used_time,omitempty"`
-	UsedMemory int64          `json:"used_memory,omitempty"`
-	CheckLog   string         `json:"check_log,omitempty"`
-	Input      string         `json:"input,omitempty"`
-	Output     string         `json:"output,omitempty"`
+	Verdict     models.Verdict `json:"verdict"`
+	UsedTime    int64          `json:"used_time,omitempty"`
+	UsedMemory  int64          `json:"used_memory,omitempty"`
+	WallTime    int64          `json:"wall_time,omitempty"`
+	PeakMemory  int64          `json:"peak_memory,omitempty"`
+	Signal      string         `json:"signal,omitempty"`
+	OOMKilled   bool           `json:"oom_killed,omitempty"`
+	CheckLog    string         `json:"check_log,omitempty"`
+	Input       string         `json:"input,omitempty"`
+	Output      string         `json:"output,omitempty"`
+	TimeLimit   int64          `json:"time_limit,omitempty"`
+	MemoryLimit int64          `json:"memory_limit,omitempty"`
+	// Artifacts contains kinds of available test artifacts.
+	Artifacts []TestArtifactKind `json:"artifacts,omitempty"`
+}
+
+// TestArtifactKind represents kind of test artifact.
+type TestArtifactKind string
+
+const (
+	OutputTestArtifact   TestArtifactKind = "output"
+	StderrTestArtifact   TestArtifactKind = "stderr"
+	CheckLogTestArtifact TestArtifactKind = "check_log"
+)
+
+var testArtifactKinds = []TestArtifactKind{
+	OutputTestArtifact,
+	StderrTestArtifact,
+	CheckLogTestArtifact,
+}
+
+// getTestArtifactID returns ID of file with specified artifact.
+func getTestArtifactID(
+	artifacts models.TestArtifacts, kind TestArtifactKind,
+) int64 {
+	switch kind {
+	case OutputTestArtifact:
+		return artifacts.OutputID
+	case StderrTestArtifact:
+		return artifacts.StderrID
+	case CheckLogTestArtifact:
+		return artifacts.CheckLogID
+	default:
+		return 0
+	}
+}
+
+func makeTestArtifacts(
+	c echo.Context, report *models.SolutionReport, test models.TestReport,
+) []TestArtifactKind {
+	if test.Artifacts == nil ||
+		report.ArtifactsExpireTime <= getNow(c).Unix() {
+		return nil
+	}
+	var kinds []TestArtifactKind
+	for _, kind := range testArtifactKinds {
+		if getTestArtifactID(*test.Artifacts, kind) != 0 {
+			kinds = append(kinds, kind)
+		}
+	}
+	return kinds
 }
 
 type SolutionReport struct {
@@ -161,10 +224,17 @@ func (v *View) makeSolutionReport(c echo.Context, solution models.Solution, with
 		resp.CompileLog = report.Compile.Log
 		for _, test := range report.Tests {
 			resp.Tests = append(resp.Tests, TestReport{
-				Verdict:    test.Verdict,
-				CheckLog:   test.Check.Log,
-				UsedTime:   test.Usage.Time,
-				UsedMemory: test.Usage.Memory,
+				Verdict:     test.Verdict,
+				CheckLog:    test.Check.Log,
+				UsedTime:    test.Usage.Time,
+				UsedMemory:  test.Usage.Memory,
+				WallTime:    test.Usage.WallTime,
+				PeakMemory:  test.Usage.PeakMemory,
+				Signal:      test.Usage.Signal,
+				OOMKilled:   test.Usage.OOMKilled,
+				TimeLimit:   test.TimeLimit,
+				MemoryLimit: test.MemoryLimit,
+				Artifacts:   makeTestArtifacts(c, report, test),
 			})
 		}
 	}
@@ -276,6 +346,55 @@ func (v *View) observeSolution(c echo.Context) error {
 	return c.JSON(http.StatusOK, v.makeSolution(c, accountCtx, solution, true))
 }
 
+func (v *View) observeSolutionTestArtifact(c echo.Context) error {
+	solution, ok := c.Get(solutionKey).(models.Solution)
+	if !ok {
+		return fmt.Errorf("solution not extracted")
+	}
+	notFound := errorResponse{
+		Code:    http.StatusNotFound,
+		Message: localize(c, "Artifact not found."),
+	}
+	number, err := strconv.Atoi(c.Param("test"))
+	if err != nil {
+		c.Logger().Warn(err)
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Invalid test number."),
+		}
+	}
+	report, err := solution.GetReport()
+	if err != nil {
+		return err
+	}
+	if report == nil || number < 1 || number > len(report.Tests) {
+		return notFound
+	}
+	test := report.Tests[number-1]
+	if test.Artifacts == nil {
+		return notFound
+	}
+	if report.ArtifactsExpireTime <= getNow(c).Unix() {
+		return errorResponse{
+			Code:    http.StatusNotFound,
+			Message: localize(c, "Artifact has expired."),
+		}
+	}
+	kind := TestArtifactKind(c.Param("artifact"))
+	fileID := getTestArtifactID(*test.Artifacts, kind)
+	if fileID == 0 {
+		return notFound
+	}
+	content, err := v.files.DownloadFile(getContext(c), fileID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFound
+		}
+		return err
+	}
+	return c.Stream(http.StatusOK, "text/plain; charset=utf-8", content)
+}
+
 func (v *View) extractSolution(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		id, err := strconv.ParseInt(c.Param("solution"), 10, 64)
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+func TestSolutionTestArtifacts(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	user := NewTestUser(e)
+	user.AddRoles("observe_solution_report_checker_logs")
+	user.LoginClient()
+	defer user.LogoutClient()
+	ctx := context.Background()
+	files := managers.NewFileManager(e.Core)
+	file, err := files.UploadFile(ctx, &managers.FileReader{
+		Name:   "output.txt",
+		Reader: strings.NewReader("full output"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := files.ConfirmUploadTemporaryFile(ctx, &file, e.Now.Add(time.Hour)); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := e.Core.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Expired file that is still used by compiler can not be deleted.
+	fakeFile := models.File{
+		Path:       "image.tar.gz",
+		ExpireTime: models.NInt64(e.Now.Unix()),
+	}
+	if err := e.Core.Files.Create(ctx, &fakeFile); err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "test", ImageID: fakeFile.ID}
+	if err := e.Core.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   user.ID,
+		CreateTime: e.Now.Unix(),
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.WrongAnswer,
+		Tests: []models.TestReport{
+			{
+				Verdict:   models.WrongAnswer,
+				Artifacts: &models.TestArtifacts{OutputID: file.ID},
+			},
+		},
+		ArtifactsExpireTime: e.Now.Add(time.Hour).Unix(),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	{
+		content, err := e.Client.ObserveSolutionTestArtifact(
+			ctx, solution.ID, 1, OutputTestArtifact,
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		data, err := io.ReadAll(content)
+		_ = content.Close()
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if string(data) != "full output" {
+			t.Fatalf("Expected %q, got %q", "full output", data)
+		}
+	}
+	if _, err := e.Client.ObserveSolutionTestArtifact(
+		ctx, solution.ID, 1, StderrTestArtifact,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := e.Client.ObserveSolutionTestArtifact(
+		ctx, solution.ID, 2, OutputTestArtifact,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	e.Now = e.Now.Add(2 * time.Hour)
+	if _, err := e.Client.ObserveSolutionTestArtifact(
+		ctx, solution.ID, 1, OutputTestArtifact,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+	if n, err := files.DeleteExpiredFiles(ctx, e.Now); err != nil {
+		t.Fatal("Error:", err)
+	} else if n != 1 {
+		t.Fatalf("Expected %d, got %d", 1, n)
+	}
+}
@@ -0,0 +1,539 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/core"
+	"github.com/udovin/solve/managers"
+	"github.com/udovin/solve/models"
+)
+
+// registerTeamHandlers registers handlers for team management.
+func (v *View) registerTeamHandlers(g *echo.Group) {
+	g.GET(
+		"/v0/teams", v.observeTeams,
+		v.extractAuth(v.sessionAuth, v.guestAuth),
+		v.requirePermission(models.ObserveTeamsRole),
+	)
+	g.GET(
+		"/v0/teams/:team", v.observeTeam,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractTeam,
+		v.requirePermission(models.ObserveTeamRole),
+	)
+	g.POST(
+		"/v0/teams", v.createTeam,
+		v.extractAuth(v.sessionAuth),
+		v.requirePermission(models.CreateTeamRole),
+	)
+	g.PATCH(
+		"/v0/teams/:team", v.updateTeam,
+		v.extractAuth(v.sessionAuth), v.extractTeam,
+		v.requirePermission(models.UpdateTeamRole),
+	)
+	g.DELETE(
+		"/v0/teams/:team", v.deleteTeam,
+		v.extractAuth(v.sessionAuth), v.extractTeam,
+		v.requirePermission(models.DeleteTeamRole),
+	)
+	g.GET(
+		"/v0/teams/:team/members", v.observeTeamMembers,
+		v.extractAuth(v.sessionAuth, v.guestAuth), v.extractTeam,
+		v.requirePermission(models.ObserveTeamRole),
+	)
+	g.POST(
+		"/v0/teams/:team/members", v.createTeamMember,
+		v.extractAuth(v.sessionAuth), v.extractTeam,
+		v.requirePermission(models.UpdateTeamRole),
+	)
+	g.DELETE(
+		"/v0/teams/:team/members/:member", v.deleteTeamMember,
+		v.extractAuth(v.sessionAuth), v.extractTeam, v.extractTeamMember,
+		v.requirePermission(models.UpdateTeamRole),
+	)
+}
+
+func (v *View) observeTeams(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	if err := syncStore(c, v.core.Teams); err != nil {
+		return err
+	}
+	var resp Teams
+	teams, err := v.core.Teams.All()
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		permissions, err := v.getTeamPermissions(accountCtx, team)
+		if err != nil {
+			return err
+		}
+		if permissions.HasPermission(models.ObserveTeamRole) {
+			resp.Teams = append(resp.Teams, makeTeam(team))
+		}
+	}
+	sortFunc(resp.Teams, teamGreater)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (v *View) observeTeam(c echo.Context) error {
+	team, ok := c.Get(teamKey).(models.Team)
+	if !ok {
+		return fmt.Errorf("team not extracted")
+	}
+	return c.JSON(http.StatusOK, makeTeam(team))
+}
+
+type UpdateTeamForm struct {
+	Title *string `json:"title"`
+}
+
+func (f *UpdateTeamForm) Update(c echo.Context, o *models.Team) error {
+	errors := errorFields{}
+	if f.Title != nil {
+		if len(*f.Title) < 4 {
+			errors["title"] = errorField{
+				Message: localize(c, "Title is too short."),
+			}
+		} else if len(*f.Title) > 64 {
+			errors["title"] = errorField{
+				Message: localize(c, "Title is too long."),
+			}
+		}
+		o.Title = *f.Title
+	}
+	if len(errors) > 0 {
+		return &errorResponse{
+			Code:          http.StatusBadRequest,
+			Message:       localize(c, "Form has invalid fields."),
+			InvalidFields: errors,
+		}
+	}
+	return nil
+}
+
+type CreateTeamForm UpdateTeamForm
+
+func (f *CreateTeamForm) Update(c echo.Context, o *models.Team) error {
+	if f.Title == nil {
+		return &errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Form has invalid fields."),
+			InvalidFields: errorFields{
+				"title": errorField{
+					Message: localize(c, "Title is required."),
+				},
+			},
+		}
+	}
+	return (*UpdateTeamForm)(f).Update(c, o)
+}
+
+func (v *View) createTeam(c echo.Context) error {
+	accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+	if !ok {
+		return fmt.Errorf("account not extracted")
+	}
+	var form CreateTeamForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	var team models.Team
+	if err := form.Update(c, &team); err != nil {
+		return err
+	}
+	if account := accountCtx.Account; account != nil {
+		team.OwnerID = NInt64(account.ID)
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		account := models.Account{Kind: team.AccountKind()}
+		if err := v.core.Accounts.Create(ctx, &account); err != nil {
+			return err
+		}
+		team.AccountID = account.ID
+		return v.core.Teams.Create(ctx, &team)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeTeam(team))
+}
+
+func (v *View) updateTeam(c echo.Context) error {
+	team, ok := c.Get(teamKey).(models.Team)
+	if !ok {
+		return fmt.Errorf("team not extracted")
+	}
+	var form UpdateTeamForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	if err := form.Update(c, &team); err != nil {
+		return err
+	}
+	if err := v.core.Teams.Update(getContext(c), team); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeTeam(team))
+}
+
+func (v *View) deleteTeam(c echo.Context) error {
+	team, ok := c.Get(teamKey).(models.Team)
+	if !ok {
+		return fmt.Errorf("team not extracted")
+	}
+	if err := v.core.WrapTx(getContext(c), func(ctx context.Context) error {
+		// Team that participates in contests can not be deleted,
+		// because its solutions and standings refer to team account.
+		hasParticipants, err := v.hasContestParticipants(ctx, team.AccountID)
+		if err != nil {
+			return err
+		}
+		if hasParticipants {
+			return errorResponse{
+				Code:    http.StatusForbidden,
+				Message: localize(c, "Team participates in contests."),
+			}
+		}
+		members, err := v.core.TeamMembers.FindByTeam(team.ID)
+		if err != nil {
+			return err
+		}
+		for _, member := range members {
+			if err := v.core.TeamMembers.Delete(ctx, member.ID); err != nil {
+				return err
+			}
+		}
+		accountRoles, err := v.core.AccountRoles.FindByAccount(team.AccountID)
+		if err != nil {
+			return err
+		}
+		for _, accountRole := range accountRoles {
+			if err := v.core.AccountRoles.Delete(ctx, accountRole.ID); err != nil {
+				return err
+			}
+		}
+		if err := v.core.Teams.Delete(ctx, team.ID); err != nil {
+			return err
+		}
+		return v.core.Accounts.Delete(ctx, team.AccountID)
+	}, sqlRepeatableRead); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeTeam(team))
+}
+
+// hasContestParticipants returns true if account participates in some
+// contest.
+//
+// Participants are read from database, so participants that are not
+// synced to store yet are not missed.
+func (v *View) hasContestParticipants(
+	ctx context.Context, accountID int64,
+) (bool, error) {
+	reader, err := v.core.ContestParticipants.Find(
+		ctx, gosql.Column("account_id").Equal(accountID),
+	)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = reader.Close() }()
+	if reader.Next() {
+		return true, nil
+	}
+	return false, reader.Err()
+}
+
+func (v *View) observeTeamMembers(c echo.Context) error {
+	team, ok := c.Get(teamKey).(models.Team)
+	if !ok {
+		return fmt.Errorf("team not extracted")
+	}
+	if err := syncStore(c, v.core.TeamMembers); err != nil {
+		return err
+	}
+	members, err := v.core.TeamMembers.FindByTeam(team.ID)
+	if err != nil {
+		return err
+	}
+	resp := TeamMembers{}
+	for _, member := range members {
+		resp.Members = append(resp.Members, makeTeamMember(member, v.core))
+	}
+	sortFunc(resp.Members, teamMemberLess)
+	return c.JSON(http.StatusOK, resp)
+}
+
+type CreateTeamMemberForm struct {
+	UserID      *int64  `json:"user_id"`
+	UserLogin   *string `json:"user_login"`
+	ScopeUserID *int64  `json:"scope_user_id"`
+}
+
+func (f CreateTeamMemberForm) Update(
+	c echo.Context, member *models.TeamMember, core *core.Core,
+) error {
+	if f.UserID != nil {
+		user, err := core.Users.Get(*f.UserID)
+		if err != nil {
+			return errorResponse{
+				Code: http.StatusBadRequest,
+				Message: localize(
+					c, "User {id} does not exists.",
+					replaceField("id", *f.UserID),
+				),
+			}
+		}
+		member.AccountID = user.AccountID
+	} else if f.UserLogin != nil {
+		user, err := core.Users.GetByLogin(*f.UserLogin)
+		if err != nil {
+			return errorResponse{
+				Code: http.StatusBadRequest,
+				Message: localize(
+					c, "User \"{login}\" does not exists.",
+					replaceField("login", *f.UserLogin),
+				),
+			}
+		}
+		member.AccountID = user.AccountID
+	} else if f.ScopeUserID != nil {
+		user, err := core.ScopeUsers.Get(*f.ScopeUserID)
+		if err != nil {
+			return errorResponse{
+				Code: http.StatusBadRequest,
+				Message: localize(
+					c, "User {id} does not exists.",
+					replaceField("id", *f.ScopeUserID),
+				),
+			}
+		}
+		member.AccountID = user.AccountID
+	}
+	if member.AccountID == 0 {
+		return errorResponse{
+			Code:    http.StatusBadRequest,
+			Message: localize(c, "Member account is not specified."),
+		}
+	}
+	return nil
+}
+
+func (v *View) createTeamMember(c echo.Context) error {
+	team, ok := c.Get(teamKey).(models.Team)
+	if !ok {
+		return fmt.Errorf("team not extracted")
+	}
+	var form CreateTeamMemberForm
+	if err := c.Bind(&form); err != nil {
+		c.Logger().Warn(err)
+		return c.NoContent(http.StatusBadRequest)
+	}
+	member := models.TeamMember{TeamID: team.ID}
+	if err := form.Update(c, &member, v.core); err != nil {
+		return err
+	}
+	if err := syncStore(c, v.core.TeamMembers); err != nil {
+		return err
+	}
+	members, err := v.core.TeamMembers.FindByTeam(team.ID)
+	if err != nil {
+		return err
+	}
+	for _, m := range members {
+		if m.AccountID == member.AccountID {
+			return errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Member already exists."),
+			}
+		}
+	}
+	if err := v.core.TeamMembers.Create(getContext(c), &member); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, makeTeamMember(member, v.core))
+}
+
+func (v *View) deleteTeamMember(c echo.Context) error {
+	member, ok := c.Get(teamMemberKey).(models.TeamMember)
+	if !ok {
+		return fmt.Errorf("member not extracted")
+	}
+	if err := v.core.TeamMembers.Delete(getContext(c), member.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, makeTeamMember(member, v.core))
+}
+
+func (v *View) extractTeam(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		accountCtx, ok := c.Get(accountCtxKey).(*managers.AccountContext)
+		if !ok {
+			return fmt.Errorf("auth not extracted")
+		}
+		id, err := strconv.ParseInt(c.Param("team"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Invalid team ID."),
+			}
+		}
+		if err := syncStore(c, v.core.Teams); err != nil {
+			return err
+		}
+		team, err := v.core.Teams.Get(id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:    http.StatusNotFound,
+					Message: localize(c, "Team not found."),
+				}
+			}
+			return err
+		}
+		permissions, err := v.getTeamPermissions(accountCtx, team)
+		if err != nil {
+			return err
+		}
+		c.Set(teamKey, team)
+		c.Set(permissionCtxKey, permissions)
+		return next(c)
+	}
+}
+
+func (v *View) extractTeamMember(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		team, ok := c.Get(teamKey).(models.Team)
+		if !ok {
+			return fmt.Errorf("team not extracted")
+		}
+		id, err := strconv.ParseInt(c.Param("member"), 10, 64)
+		if err != nil {
+			c.Logger().Warn(err)
+			return errorResponse{
+				Code:    http.StatusBadRequest,
+				Message: localize(c, "Invalid member ID."),
+			}
+		}
+		if err := syncStore(c, v.core.TeamMembers); err != nil {
+			return err
+		}
+		member, err := v.core.TeamMembers.Get(id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errorResponse{
+					Code:    http.StatusNotFound,
+					Message: localize(c, "Member not found."),
+				}
+			}
+			return err
+		}
+		if member.TeamID != team.ID {
+			return errorResponse{
+				Code:    http.StatusNotFound,
+				Message: localize(c, "Member not found."),
+			}
+		}
+		c.Set(teamMemberKey, member)
+		return next(c)
+	}
+}
+
+// getTeamPermissions returns permissions for team.
+//
+// Owner of team can manage team and members of team can observe it.
+func (v *View) getTeamPermissions(
+	ctx *managers.AccountContext, team models.Team,
+) (managers.PermissionSet, error) {
+	permissions := ctx.Permissions.Clone()
+	if ctx.Account == nil || ctx.Account.ID == 0 {
+		return permissions, nil
+	}
+	if ctx.Account.ID == int64(team.OwnerID) {
+		permissions.AddPermission(
+			models.ObserveTeamRole,
+			models.UpdateTeamRole,
+			models.DeleteTeamRole,
+		)
+		return permissions, nil
+	}
+	members, err := v.core.TeamMembers.FindByTeam(team.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range members {
+		if member.AccountID == ctx.Account.ID {
+			permissions.AddPermission(models.ObserveTeamRole)
+			break
+		}
+	}
+	return permissions, nil
+}
+
+type Team struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type Teams struct {
+	Teams []Team `json:"teams"`
+}
+
+func makeTeam(team models.Team) Team {
+	return Team{
+		ID:    team.ID,
+		Title: team.Title,
+	}
+}
+
+type TeamMember struct {
+	ID        int64      `json:"id"`
+	User      *User      `json:"user,omitempty"`
+	ScopeUser *ScopeUser `json:"scope_user,omitempty"`
+}
+
+type TeamMembers struct {
+	Members []TeamMember `json:"members"`
+}
+
+func makeTeamMember(member models.TeamMember, core *core.Core) TeamMember {
+	resp := TeamMember{ID: member.ID}
+	if account, err := core.Accounts.Get(member.AccountID); err == nil {
+		switch account.Kind {
+		case models.UserAccount:
+			if user, err := core.Users.GetByAccount(account.ID); err == nil {
+				resp.User = &User{
+					ID:    user.ID,
+					Login: user.Login,
+				}
+			}
+		case models.ScopeUserAccount:
+			if user, err := core.ScopeUsers.GetByAccount(account.ID); err == nil {
+				resp.ScopeUser = &ScopeUser{
+					ID:    user.ID,
+					Login: user.Login,
+					Title: string(user.Title),
+				}
+			}
+		}
+	}
+	return resp
+}
+
+func teamGreater(l, r Team) bool {
+	return l.ID > r.ID
+}
+
+func teamMemberLess(l, r TeamMember) bool {
+	return l.ID < r.ID
+}
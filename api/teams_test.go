@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestTeamParticipation(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	member := NewTestUser(e)
+	var contest Contest
+	var team Team
+	func() {
+		owner := NewTestUser(e)
+		owner.AddRoles("observe_contest", "create_contest", "update_contest", "create_team")
+		owner.LoginClient()
+		defer owner.LogoutClient()
+		var err error
+		if team, err = e.Client.CreateTeam(ctx, CreateTeamForm{
+			Title: getPtr("Test team"),
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if _, err := e.Client.CreateTeamMember(ctx, team.ID, CreateTeamMemberForm{
+			UserLogin: getPtr(member.User.Login),
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if _, err := e.Client.CreateTeamMember(ctx, team.ID, CreateTeamMemberForm{
+			UserLogin: getPtr(member.User.Login),
+		}); err == nil {
+			t.Fatal("Expected error")
+		}
+		contestForm := createContestForm{
+			Title:     getPtr("Test contest"),
+			BeginTime: getPtr(NInt64(e.Now.Add(-time.Hour).Unix())),
+			Duration:  getPtr(7200),
+		}
+		if contest, err = e.Client.CreateContest(contestForm); err != nil {
+			t.Fatal("Error:", err)
+		}
+		participant, err := e.Client.CreateContestParticipant(
+			contest.ID, createContestParticipantForm{
+				TeamID: getPtr(team.ID),
+				Kind:   models.RegularParticipant,
+			},
+		)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if participant.Team == nil || participant.Team.ID != team.ID {
+			t.Fatalf("Expected team participant, got %+v", participant)
+		}
+	}()
+	member.LoginClient()
+	defer member.LogoutClient()
+	members, err := e.Client.ObserveTeamMembers(ctx, team.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(members.Members) != 1 || members.Members[0].User == nil ||
+		members.Members[0].User.Login != member.User.Login {
+		t.Fatalf("Invalid members: %+v", members)
+	}
+	resp, err := e.Client.ObserveContest(ctx, contest.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if resp.State == nil || resp.State.Participant == nil ||
+		resp.State.Participant.Team == nil ||
+		resp.State.Participant.Team.Title != "Test team" {
+		t.Fatalf("Expected team participant, got %+v", resp.State)
+	}
+	canSubmit := false
+	for _, permission := range resp.Permissions {
+		if permission == models.SubmitContestSolutionRole {
+			canSubmit = true
+		}
+	}
+	if !canSubmit {
+		t.Fatalf("Expected %q permission", models.SubmitContestSolutionRole)
+	}
+}
+
+func TestDeleteTeam(t *testing.T) {
+	e := NewTestEnv(t)
+	defer e.Close()
+	ctx := context.Background()
+	member := NewTestUser(e)
+	owner := NewTestUser(e)
+	owner.AddRoles(
+		"observe_contest", "create_contest", "update_contest",
+		"create_team", "delete_team",
+	)
+	owner.LoginClient()
+	defer owner.LogoutClient()
+	team, err := e.Client.CreateTeam(ctx, CreateTeamForm{
+		Title: getPtr("Test team"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Teams.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	teamModel, err := e.Core.Teams.Get(team.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.CreateTeamMember(ctx, team.ID, CreateTeamMemberForm{
+		UserLogin: getPtr(member.User.Login),
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest, err := e.Client.CreateContest(createContestForm{
+		Title: getPtr("Test contest"),
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant, err := e.Client.CreateContestParticipant(
+		contest.ID, createContestParticipantForm{
+			TeamID: getPtr(team.ID),
+			Kind:   models.RegularParticipant,
+		},
+	)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.DeleteTeam(ctx, team.ID); err == nil {
+		t.Fatal("Expected error")
+	} else if resp, ok := err.(statusCodeResponse); !ok {
+		t.Fatal("Invalid error:", err)
+	} else {
+		expectStatus(t, http.StatusForbidden, resp.StatusCode())
+	}
+	if err := e.Core.ContestParticipants.Delete(ctx, participant.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Client.DeleteTeam(ctx, team.ID); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := e.Core.Teams.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Core.Teams.Get(team.ID); err != sql.ErrNoRows {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	if err := e.Core.Accounts.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := e.Core.Accounts.Get(teamModel.AccountID); err != sql.ErrNoRows {
+		t.Fatalf("Expected %v, got %v", sql.ErrNoRows, err)
+	}
+	if err := e.Core.TeamMembers.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	members, err := e.Core.TeamMembers.FindByTeam(team.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(members) != 0 {
+		t.Fatalf("Expected no members, got %v", members)
+	}
+}
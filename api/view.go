@@ -33,6 +33,10 @@ type View struct {
 	solutions *managers.SolutionManager
 	standings *managers.ContestStandingsManager
 	visits    chan visitContext
+	// statements contains cache of rendered statements.
+	statements *statementCache
+	// clicsFeeds contains cache of CLICS event feeds.
+	clicsFeeds *clicsFeedCache
 }
 
 func (v *View) StartDaemons() {
@@ -85,11 +89,17 @@ func (v *View) Register(g *echo.Group) {
 	g.GET("/health", v.health)
 	v.registerUserHandlers(g)
 	v.registerScopeHandlers(g)
+	v.registerTeamHandlers(g)
 	v.registerRoleHandlers(g)
 	v.registerSessionHandlers(g)
 	v.registerContestHandlers(g)
 	v.registerContestStandingsHandlers(g)
+	v.registerContestClarificationHandlers(g)
+	v.registerContestAnnouncementHandlers(g)
+	v.registerContestCloneHandlers(g)
+	v.registerClicsHandlers(g)
 	v.registerProblemHandlers(g)
+	v.registerProblemRevisionHandlers(g)
 	v.registerSolutionHandlers(g)
 	v.registerCompilerHandlers(g)
 	v.registerSettingHandlers(g)
@@ -122,10 +132,12 @@ func (v *View) health(c echo.Context) error {
 // NewView returns a new instance of view.
 func NewView(core *core.Core) *View {
 	v := View{
-		core:      core,
-		accounts:  managers.NewAccountManager(core),
-		contests:  managers.NewContestManager(core),
-		standings: managers.NewContestStandingsManager(core),
+		core:       core,
+		accounts:   managers.NewAccountManager(core),
+		contests:   managers.NewContestManager(core),
+		standings:  managers.NewContestStandingsManager(core),
+		statements: newStatementCache(statementCacheSize),
+		clicsFeeds: newClicsFeedCache(clicsFeedCacheSize),
 	}
 	if core.Config.Storage != nil {
 		v.files = managers.NewFileManager(core)
@@ -137,29 +149,33 @@ func NewView(core *core.Core) *View {
 }
 
 const (
-	nowKey                = "now"
-	authVisitKey          = "auth_visit"
-	authSessionKey        = "auth_session"
-	accountCtxKey         = "account_ctx"
-	permissionCtxKey      = "permission_ctx"
-	roleKey               = "role"
-	childRoleKey          = "child_role"
-	userKey               = "user"
-	sessionKey            = "session"
-	sessionCookie         = "session"
-	contestCtxKey         = "contest_ctx"
-	contestProblemKey     = "contest_problem"
-	contestParticipantKey = "contest_participant"
-	contestSolutionKey    = "contest_solution"
-	problemKey            = "problem"
-	solutionKey           = "solution"
-	compilerKey           = "compiler"
-	fileKey               = "file"
-	settingKey            = "setting"
-	scopeKey              = "scope"
-	scopeUserKey          = "scope_user"
-	localeKey             = "locale"
-	syncKey               = "sync"
+	nowKey                  = "now"
+	authVisitKey            = "auth_visit"
+	authSessionKey          = "auth_session"
+	accountCtxKey           = "account_ctx"
+	permissionCtxKey        = "permission_ctx"
+	roleKey                 = "role"
+	childRoleKey            = "child_role"
+	userKey                 = "user"
+	sessionKey              = "session"
+	sessionCookie           = "session"
+	contestCtxKey           = "contest_ctx"
+	contestProblemKey       = "contest_problem"
+	contestParticipantKey   = "contest_participant"
+	contestSolutionKey      = "contest_solution"
+	contestClarificationKey = "contest_clarification"
+	problemKey              = "problem"
+	problemRevisionKey      = "problem_revision"
+	solutionKey             = "solution"
+	compilerKey             = "compiler"
+	fileKey                 = "file"
+	settingKey              = "setting"
+	scopeKey                = "scope"
+	scopeUserKey            = "scope_user"
+	teamKey                 = "team"
+	teamMemberKey           = "team_member"
+	localeKey               = "locale"
+	syncKey                 = "sync"
 )
 
 type (
@@ -422,10 +438,26 @@ func (v *View) userAuth(c echo.Context) (bool, error) {
 	if form.Login == "" || form.Password == "" {
 		return false, nil
 	}
+	return v.authUser(c, form.Login, form.Password)
+}
+
+// basicAuth authorizes user with HTTP basic authentication.
+//
+// Basic authentication is used by external tools that can not
+// obtain session cookie.
+func (v *View) basicAuth(c echo.Context) (bool, error) {
+	login, password, ok := c.Request().BasicAuth()
+	if !ok || login == "" || password == "" {
+		return false, nil
+	}
+	return v.authUser(c, login, password)
+}
+
+func (v *View) authUser(c echo.Context, login, password string) (bool, error) {
 	if err := syncStore(c, v.core.Users); err != nil {
 		return false, err
 	}
-	user, err := v.core.Users.GetByLogin(form.Login)
+	user, err := v.core.Users.GetByLogin(login)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			resp := errorResponse{
@@ -436,7 +468,7 @@ func (v *View) userAuth(c echo.Context) (bool, error) {
 		}
 		return false, err
 	}
-	if !v.core.Users.CheckPassword(user, form.Password) {
+	if !v.core.Users.CheckPassword(user, password) {
 		resp := errorResponse{
 			Code:    http.StatusForbidden,
 			Message: localize(c, "Invalid password."),
@@ -384,6 +384,27 @@ func (c *testClient) CreateContestProblem(
 	return respData, err
 }
 
+func (c *testClient) CreateContestParticipant(
+	contestID int64,
+	form createContestParticipantForm,
+) (ContestParticipant, error) {
+	data, err := json.Marshal(form)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	req, err := http.NewRequest(
+		http.MethodPost,
+		c.getURL("/v0/contests/%d/participants", contestID),
+		bytes.NewReader(data),
+	)
+	if err != nil {
+		return ContestParticipant{}, err
+	}
+	var respData ContestParticipant
+	_, err = c.doRequest(req, http.StatusCreated, &respData)
+	return respData, err
+}
+
 func (c *testClient) CreateRoleRole(role string, child string) (Role, error) {
 	req, err := http.NewRequest(
 		http.MethodPost, c.getURL("/v0/roles/%s/roles/%s", role, child),
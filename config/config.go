@@ -107,10 +107,39 @@ type Security struct {
 type Invoker struct {
 	// Workers contains amount of parallel workers.
 	Workers int `json:"workers"`
+	// Sandbox contains kind of sandbox backend.
+	//
+	// By default safeexec sandbox is used.
+	Sandbox string `json:"sandbox,omitempty"`
 	// Safeexec contains config for safeexec binary.
 	Safeexec Safeexec `json:"safeexec"`
+	// Artifacts contains config for test artifacts.
+	Artifacts *Artifacts `json:"artifacts,omitempty"`
+}
+
+// Artifacts contains config for storing of full test artifacts.
+type Artifacts struct {
+	// Disabled disables storing of test artifacts.
+	Disabled bool `json:"disabled,omitempty"`
+	// AllTests enables storing of artifacts for all tests.
+	//
+	// By default artifacts are stored only for the first failed test.
+	AllTests bool `json:"all_tests,omitempty"`
+	// Retention contains retention period of artifacts in seconds.
+	//
+	// By default artifacts are stored for 7 days.
+	Retention int64 `json:"retention,omitempty"`
 }
 
+const (
+	// SafeexecSandbox runs processes using safeexec binary.
+	SafeexecSandbox = "safeexec"
+	// FakeSandbox runs processes without isolation.
+	//
+	// It should be used only for testing purposes.
+	FakeSandbox = "fake"
+)
+
 type Safeexec struct {
 	Path string `json:"path"`
 }
@@ -41,10 +41,16 @@ type Core struct {
 	Scopes *models.ScopeStore
 	// ScopeUsers contains scope user store.
 	ScopeUsers *models.ScopeUserStore
+	// Teams contains team store.
+	Teams *models.TeamStore
+	// TeamMembers contains team member store.
+	TeamMembers *models.TeamMemberStore
 	// Problems contains problems store.
 	Problems *models.ProblemStore
 	// ProblemResources contains problem resources store.
 	ProblemResources *models.ProblemResourceStore
+	// ProblemRevisions contains problem revisions store.
+	ProblemRevisions *models.ProblemRevisionStore
 	// Solutions contains solutions store.
 	Solutions *models.SolutionStore
 	// Contests contains contest store.
@@ -55,6 +61,14 @@ type Core struct {
 	ContestParticipants *models.ContestParticipantStore
 	// ContestSolutions contains contest solutions store.
 	ContestSolutions *models.ContestSolutionStore
+	// ContestClarifications contains contest clarifications store.
+	ContestClarifications *models.ContestClarificationStore
+	// ContestClarificationReads contains contest clarification reads store.
+	ContestClarificationReads *models.ContestClarificationReadStore
+	// ContestAnnouncements contains contest announcements store.
+	ContestAnnouncements *models.ContestAnnouncementStore
+	// ContestNotifications contains contest notifications store.
+	ContestNotifications *models.ContestNotificationStore
 	// Compilers contains compiler store.
 	Compilers *models.CompilerStore
 	// Visits contains visit store.
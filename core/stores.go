@@ -49,6 +49,12 @@ func (c *Core) SetupAllStores() {
 			c.Config.Security.PasswordKey,
 		)
 	}
+	c.Teams = models.NewTeamStore(
+		c.DB, "solve_team", "solve_team_event",
+	)
+	c.TeamMembers = models.NewTeamMemberStore(
+		c.DB, "solve_team_member", "solve_team_member_event",
+	)
 	c.Contests = models.NewContestStore(
 		c.DB, "solve_contest", "solve_contest_event",
 	)
@@ -58,6 +64,9 @@ func (c *Core) SetupAllStores() {
 	c.ProblemResources = models.NewProblemResourceStore(
 		c.DB, "solve_problem_resource", "solve_problem_resource_event",
 	)
+	c.ProblemRevisions = models.NewProblemRevisionStore(
+		c.DB, "solve_problem_revision", "solve_problem_revision_event",
+	)
 	c.Solutions = models.NewSolutionStore(
 		c.DB, "solve_solution", "solve_solution_event",
 	)
@@ -70,6 +79,18 @@ func (c *Core) SetupAllStores() {
 	c.ContestSolutions = models.NewContestSolutionStore(
 		c.DB, "solve_contest_solution", "solve_contest_solution_event",
 	)
+	c.ContestClarifications = models.NewContestClarificationStore(
+		c.DB, "solve_contest_clarification", "solve_contest_clarification_event",
+	)
+	c.ContestClarificationReads = models.NewContestClarificationReadStore(
+		c.DB, "solve_contest_clarification_read", "solve_contest_clarification_read_event",
+	)
+	c.ContestAnnouncements = models.NewContestAnnouncementStore(
+		c.DB, "solve_contest_announcement", "solve_contest_announcement_event",
+	)
+	c.ContestNotifications = models.NewContestNotificationStore(
+		c.DB, "solve_contest_notification", "solve_contest_notification_event",
+	)
 	c.Compilers = models.NewCompilerStore(
 		c.DB, "solve_compiler", "solve_compiler_event",
 	)
@@ -88,13 +109,20 @@ func (c *Core) startStores(start func(models.Store, string, time.Duration)) {
 	start(c.Users, "users", time.Second)
 	start(c.Scopes, "scopes", time.Second*5)
 	start(c.ScopeUsers, "scope_users", time.Second)
+	start(c.Teams, "teams", time.Second)
+	start(c.TeamMembers, "team_members", time.Second)
 	start(c.Contests, "contests", time.Second)
 	start(c.Problems, "problems", time.Second)
 	start(c.ProblemResources, "problem_resources", time.Second)
+	start(c.ProblemRevisions, "problem_revisions", time.Second)
 	start(c.Solutions, "solutions", time.Second)
 	start(c.ContestProblems, "contest_problems", time.Second)
 	start(c.ContestParticipants, "contest_participants", time.Second)
 	start(c.ContestSolutions, "contest_solutions", time.Second)
+	start(c.ContestClarifications, "contest_clarifications", time.Second)
+	start(c.ContestClarificationReads, "contest_clarification_reads", time.Second)
+	start(c.ContestAnnouncements, "contest_announcements", time.Second)
+	start(c.ContestNotifications, "contest_notifications", time.Second)
 	start(c.Compilers, "compilers", time.Second*5)
 }
 
@@ -199,3 +199,24 @@ func (q CreateIndex) BuildUnapply(d gosql.Dialect) (string, error) {
 	query.WriteString(fmt.Sprintf("%q", q.getName()))
 	return query.String(), nil
 }
+
+// AddColumn represents add column query.
+type AddColumn struct {
+	Table  string
+	Column Column
+}
+
+// BuildApply returns alter SQL query in specified dialect.
+func (q AddColumn) BuildApply(d gosql.Dialect) (string, error) {
+	column, err := q.Column.BuildSQL(d)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("ALTER TABLE %q ADD COLUMN %s", q.Table, column), nil
+}
+
+func (q AddColumn) BuildUnapply(d gosql.Dialect) (string, error) {
+	return fmt.Sprintf(
+		"ALTER TABLE %q DROP COLUMN %q", q.Table, q.Column.Name,
+	), nil
+}
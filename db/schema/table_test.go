@@ -143,3 +143,28 @@ func TestCreateTableInvalidColumn(t *testing.T) {
 		t.Fatal("Expected error")
 	}
 }
+
+func TestAddColumn(t *testing.T) {
+	q := AddColumn{
+		Table:  "test_table",
+		Column: Column{Name: "state", Type: JSON, Nullable: true},
+	}
+	qSQLite := `ALTER TABLE "test_table" ADD COLUMN "state" blob`
+	if sql, err := q.BuildApply(gosql.SQLiteDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != qSQLite {
+		t.Fatal("Wrong SQL:", sql)
+	}
+	qPostgres := `ALTER TABLE "test_table" ADD COLUMN "state" jsonb`
+	if sql, err := q.BuildApply(gosql.PostgresDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != qPostgres {
+		t.Fatal("Wrong SQL:", sql)
+	}
+	qDrop := `ALTER TABLE "test_table" DROP COLUMN "state"`
+	if sql, err := q.BuildUnapply(gosql.SQLiteDialect); err != nil {
+		t.Fatal("Error:", err)
+	} else if sql != qDrop {
+		t.Fatal("Wrong SQL:", sql)
+	}
+}
@@ -0,0 +1,295 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/udovin/solve/models"
+	"github.com/udovin/solve/pkg/logs"
+)
+
+func init() {
+	registerTaskImpl(models.CalibrateProblemTask, &calibrateProblemTask{})
+}
+
+const (
+	defaultCalibrationRuns       = 3
+	defaultCalibrationMultiplier = 2
+	defaultCalibrationRounding   = 100
+)
+
+type calibrateProblemTask struct {
+	invoker     *Invoker
+	config      models.CalibrateProblemTaskConfig
+	problem     models.Problem
+	tempDir     string
+	problemImpl Problem
+	solutions   []ProblemSolution
+}
+
+func (calibrateProblemTask) New(invoker *Invoker) taskImpl {
+	return &calibrateProblemTask{invoker: invoker}
+}
+
+func (t *calibrateProblemTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	if t.config.Runs <= 0 {
+		t.config.Runs = defaultCalibrationRuns
+	}
+	if t.config.TimeMultiplier <= 0 {
+		t.config.TimeMultiplier = defaultCalibrationMultiplier
+	}
+	if t.config.TimeRounding <= 0 {
+		t.config.TimeRounding = defaultCalibrationRounding
+	}
+	if err := t.invoker.core.Problems.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to sync problems: %w", err)
+	}
+	problem, err := t.invoker.core.Problems.Get(t.config.ProblemID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch problem: %w", err)
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.problem = problem
+	t.tempDir = tempDir
+	return t.executeImpl(ctx)
+}
+
+func (t *calibrateProblemTask) prepareProblem(ctx TaskContext) error {
+	if t.problem.PackageID == 0 || t.problem.CompiledID == 0 {
+		return fmt.Errorf("problem is not compiled")
+	}
+	packageImpl, err := t.invoker.problems.DownloadProblem(ctx, t.problem, PolygonProblem)
+	if err != nil {
+		return fmt.Errorf("cannot download package: %w", err)
+	}
+	p, ok := packageImpl.(solutionsProblem)
+	if !ok {
+		return fmt.Errorf("package does not contain solutions")
+	}
+	solutions, err := p.GetSolutions()
+	if err != nil {
+		return fmt.Errorf("cannot get solutions: %w", err)
+	}
+	for _, solution := range solutions {
+		if kind := solution.Kind(); kind == MainSolution || kind == AcceptedSolution {
+			t.solutions = append(t.solutions, solution)
+		}
+	}
+	if len(t.solutions) == 0 {
+		return fmt.Errorf("package does not contain accepted solutions")
+	}
+	problem, err := t.invoker.problems.DownloadProblem(ctx, t.problem, CompiledProblem)
+	if err != nil {
+		return fmt.Errorf("cannot download problem: %w", err)
+	}
+	t.problemImpl = problem
+	return nil
+}
+
+func (t *calibrateProblemTask) executeImpl(ctx TaskContext) error {
+	if err := t.prepareProblem(ctx); err != nil {
+		return fmt.Errorf("cannot prepare problem: %w", err)
+	}
+	groups, err := t.problemImpl.GetTestGroups()
+	if err != nil {
+		return fmt.Errorf("cannot get test groups: %w", err)
+	}
+	calibration := models.ProblemCalibration{}
+	for _, group := range groups {
+		calibration.Groups = append(calibration.Groups, models.ProblemCalibrationGroup{
+			Name:      group.Name(),
+			TimeLimit: group.TimeLimit(),
+		})
+	}
+	var maxTime int64
+	// Suggested limits are calculated using only successful solutions.
+	successful := false
+	groupMaxTimes := make([]int64, len(groups))
+	for i, solution := range t.solutions {
+		result := models.ProblemCalibrationSolution{
+			Name: solution.Name(),
+			Kind: string(solution.Kind()),
+		}
+		groupTimes, err := t.runSolution(
+			ctx, solution, groups, filepath.Join(t.tempDir, fmt.Sprint(i)),
+		)
+		if err != nil {
+			result.Error = err.Error()
+			ctx.Logger().Warn(
+				"Cannot calibrate solution", err,
+				logs.Any("name", solution.Name()),
+			)
+		}
+		for j, groupTime := range groupTimes {
+			group := &calibration.Groups[j]
+			group.MaxTime = max(group.MaxTime, groupTime)
+			result.MaxTime = max(result.MaxTime, groupTime)
+			if float64(groupTime)*t.config.TimeMultiplier > float64(group.TimeLimit) {
+				result.Slow = true
+			}
+		}
+		if err == nil {
+			successful = true
+			maxTime = max(maxTime, result.MaxTime)
+			for j, groupTime := range groupTimes {
+				groupMaxTimes[j] = max(groupMaxTimes[j], groupTime)
+			}
+		}
+		calibration.Solutions = append(calibration.Solutions, result)
+	}
+	// Without successful solutions there is nothing to suggest, so
+	// suggested limits are left unset.
+	if successful {
+		for i, groupMaxTime := range groupMaxTimes {
+			calibration.Groups[i].SuggestedTimeLimit = suggestTimeLimit(
+				groupMaxTime, t.config.TimeMultiplier, t.config.TimeRounding,
+			)
+		}
+		calibration.TimeLimit = suggestTimeLimit(
+			maxTime, t.config.TimeMultiplier, t.config.TimeRounding,
+		)
+	}
+	calibration.CreateTime = time.Now().Unix()
+	return t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		problem, err := t.invoker.core.Problems.Get(t.problem.ID)
+		if err != nil {
+			return err
+		}
+		config, err := problem.GetConfig()
+		if err != nil {
+			return err
+		}
+		config.Calibration = &calibration
+		if err := problem.SetConfig(config); err != nil {
+			return err
+		}
+		return t.invoker.core.Problems.Update(ctx, problem)
+	})
+}
+
+// runSolution runs solution several times on every test and returns
+// max used time for every group.
+func (t *calibrateProblemTask) runSolution(
+	ctx TaskContext, solution ProblemSolution,
+	groups []ProblemTestGroup, tempDir string,
+) ([]int64, error) {
+	if solution.Compiler() == "" {
+		return nil, fmt.Errorf("unsupported compiler")
+	}
+	compiler, err := t.invoker.compilers.GetCompiler(ctx, solution.Compiler())
+	if err != nil {
+		return nil, fmt.Errorf("cannot get compiler: %w", err)
+	}
+	if err := os.MkdirAll(tempDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	sourcePath := filepath.Join(tempDir, "source")
+	if err := copySolutionSource(solution, sourcePath); err != nil {
+		return nil, err
+	}
+	binaryPath := filepath.Join(tempDir, "binary")
+	compileReport, err := compiler.Compile(ctx, CompileOptions{
+		Source:      sourcePath,
+		Target:      binaryPath,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile: %w", err)
+	}
+	if !compileReport.Success() {
+		return nil, fmt.Errorf("compilation failed: %s", compileReport.Log)
+	}
+	inputPath := filepath.Join(tempDir, "test.in")
+	outputPath := filepath.Join(tempDir, "test.out")
+	groupTimes := make([]int64, len(groups))
+	for i, group := range groups {
+		tests, err := group.GetTests()
+		if err != nil {
+			return nil, err
+		}
+		// Solutions are executed with increased time limit, so we can
+		// measure time of solutions that are slower than current limit.
+		timeLimit := time.Duration(group.TimeLimit()) * time.Millisecond * 3
+		for j, test := range tests {
+			if err := copyTestInput(test, inputPath); err != nil {
+				return nil, err
+			}
+			for run := 0; run < t.config.Runs; run++ {
+				report, err := compiler.Execute(ctx, ExecuteOptions{
+					Binary:      binaryPath,
+					InputFiles:  []MountFile{{Source: inputPath, Target: stdinFile}},
+					OutputFiles: []MountFile{{Source: outputPath, Target: stdoutFile}},
+					TimeLimit:   timeLimit,
+					MemoryLimit: group.MemoryLimit(),
+				})
+				if err != nil {
+					return nil, fmt.Errorf("cannot execute: %w", err)
+				}
+				groupTimes[i] = max(groupTimes[i], report.UsedTime.Milliseconds())
+				if report.UsedTime > timeLimit {
+					return groupTimes, fmt.Errorf(
+						"time limit exceeded on test %d of group %q", j+1, group.Name(),
+					)
+				}
+				if !report.Success() {
+					return groupTimes, fmt.Errorf(
+						"execution failed with code %d on test %d of group %q",
+						report.ExitCode, j+1, group.Name(),
+					)
+				}
+			}
+		}
+	}
+	return groupTimes, nil
+}
+
+func copySolutionSource(solution ProblemSolution, target string) error {
+	source, err := solution.OpenSource()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+	return writeFile(target, source)
+}
+
+func copyTestInput(test ProblemTest, target string) error {
+	source, err := test.OpenInput()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = source.Close() }()
+	return writeFile(target, source)
+}
+
+func writeFile(target string, source io.Reader) error {
+	file, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := io.Copy(file, source); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// suggestTimeLimit returns time limit for specified max time of model
+// solutions rounded up to specified rounding.
+func suggestTimeLimit(maxTime int64, multiplier float64, rounding int64) int64 {
+	timeLimit := int64(math.Ceil(float64(maxTime) * multiplier))
+	timeLimit = (timeLimit + rounding - 1) / rounding * rounding
+	return max(timeLimit, rounding)
+}
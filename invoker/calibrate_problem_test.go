@@ -0,0 +1,25 @@
+package invoker
+
+import (
+	"testing"
+)
+
+func TestSuggestTimeLimit(t *testing.T) {
+	for _, test := range []struct {
+		MaxTime    int64
+		Multiplier float64
+		Rounding   int64
+		Expected   int64
+	}{
+		{0, 2, 100, 100},
+		{120, 2, 100, 300},
+		{150, 2, 100, 300},
+		{151, 2, 100, 400},
+		{333, 1.5, 250, 500},
+		{1000, 3, 1000, 3000},
+	} {
+		if v := suggestTimeLimit(test.MaxTime, test.Multiplier, test.Rounding); v != test.Expected {
+			t.Fatalf("Expected %d for %+v, got %d", test.Expected, test, v)
+		}
+	}
+}
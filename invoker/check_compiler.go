@@ -0,0 +1,180 @@
+package invoker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func init() {
+	registerTaskImpl(models.CheckCompilerTask, &checkCompilerTask{})
+}
+
+type checkCompilerTask struct {
+	invoker  *Invoker
+	config   models.CheckCompilerTaskConfig
+	compiler models.Compiler
+	tempDir  string
+}
+
+func (checkCompilerTask) New(invoker *Invoker) taskImpl {
+	return &checkCompilerTask{invoker: invoker}
+}
+
+func (t *checkCompilerTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	if err := t.invoker.core.Compilers.Sync(ctx); err != nil {
+		return fmt.Errorf("unable to sync compilers: %w", err)
+	}
+	compiler, err := t.invoker.core.Compilers.Get(t.config.CompilerID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch compiler: %w", err)
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.compiler = compiler
+	t.tempDir = tempDir
+	return t.executeImpl(ctx)
+}
+
+func (t *checkCompilerTask) executeImpl(ctx TaskContext) error {
+	state := models.CompilerState{Status: models.HealthyCompiler}
+	config, err := t.compiler.GetConfig()
+	if err != nil {
+		state.Status = models.BrokenCompiler
+		state.Error = fmt.Sprintf("invalid config: %v", err)
+	} else if compilerImpl, err := t.invoker.compilers.DownloadCompiler(ctx, t.compiler); err != nil {
+		state.Status = models.BrokenCompiler
+		state.Error = fmt.Sprintf("cannot download image: %v", err)
+	} else if err := runCompilerTests(ctx, compilerImpl, config.Tests, t.tempDir, &state); err != nil {
+		state.Status = models.BrokenCompiler
+		state.Error = err.Error()
+	}
+	state.TestTime = time.Now().Unix()
+	if state.Status == models.BrokenCompiler {
+		ctx.Logger().Warnf("Compiler %q is broken: %s", t.compiler.Name, state.Error)
+	}
+	return t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		compiler, err := t.invoker.core.Compilers.Get(t.compiler.ID)
+		if err != nil {
+			return err
+		}
+		// Compiler image or config could be changed during self-test,
+		// so result of this self-test is not relevant anymore.
+		if compiler.ImageID != t.compiler.ImageID ||
+			!bytes.Equal(compiler.Config, t.compiler.Config) {
+			return nil
+		}
+		if err := compiler.SetState(state); err != nil {
+			return err
+		}
+		return t.invoker.core.Compilers.Update(ctx, compiler)
+	})
+}
+
+const (
+	helloWorldOutput = "Hello, World!"
+	aPlusBInput      = "2 3\n"
+	aPlusBOutput     = "5"
+)
+
+// runCompilerTests runs self-test programs and fills compiler state.
+func runCompilerTests(
+	ctx context.Context, compiler Compiler,
+	config *models.CompilerTestsConfig, tempDir string,
+	state *models.CompilerState,
+) error {
+	version, err := compiler.Version(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot get version: %w", err)
+	}
+	state.Version = version
+	if config == nil {
+		return nil
+	}
+	if config.HelloWorld != "" {
+		if err := runCompilerTest(
+			ctx, compiler, filepath.Join(tempDir, "hello_world"),
+			config.HelloWorld, "", helloWorldOutput, state,
+		); err != nil {
+			return fmt.Errorf("hello world: %w", err)
+		}
+	}
+	if config.APlusB != "" {
+		if err := runCompilerTest(
+			ctx, compiler, filepath.Join(tempDir, "a_plus_b"),
+			config.APlusB, aPlusBInput, aPlusBOutput, state,
+		); err != nil {
+			return fmt.Errorf("a+b: %w", err)
+		}
+	}
+	return nil
+}
+
+func runCompilerTest(
+	ctx context.Context, compiler Compiler, dir string,
+	source, input, expected string, state *models.CompilerState,
+) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	sourcePath := filepath.Join(dir, "source")
+	if err := os.WriteFile(sourcePath, []byte(source), 0644); err != nil {
+		return err
+	}
+	binaryPath := filepath.Join(dir, "binary")
+	compileReport, err := compiler.Compile(ctx, CompileOptions{
+		Source:      sourcePath,
+		Target:      binaryPath,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot compile: %w", err)
+	}
+	if !compileReport.Success() {
+		return fmt.Errorf(
+			"compilation failed with code %d: %s",
+			compileReport.ExitCode, compileReport.Log,
+		)
+	}
+	state.CompileTime = max(state.CompileTime, compileReport.UsedTime.Milliseconds())
+	inputPath := filepath.Join(dir, "input")
+	if err := os.WriteFile(inputPath, []byte(input), 0644); err != nil {
+		return err
+	}
+	outputPath := filepath.Join(dir, "output")
+	executeReport, err := compiler.Execute(ctx, ExecuteOptions{
+		Binary:      binaryPath,
+		InputFiles:  []MountFile{{Source: inputPath, Target: stdinFile}},
+		OutputFiles: []MountFile{{Source: outputPath, Target: stdoutFile}},
+		TimeLimit:   5 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot execute: %w", err)
+	}
+	if !executeReport.Success() {
+		return fmt.Errorf("execution failed with code %d", executeReport.ExitCode)
+	}
+	state.ExecuteTime = max(state.ExecuteTime, executeReport.UsedTime.Milliseconds())
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		return err
+	}
+	if actual := strings.TrimSpace(string(output)); actual != expected {
+		return fmt.Errorf("expected output %q, got %q", expected, actual)
+	}
+	return nil
+}
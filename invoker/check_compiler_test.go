@@ -0,0 +1,51 @@
+package invoker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestRunCompilerTests(t *testing.T) {
+	sandbox, err := newFakeSandbox(t.TempDir())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	source, binary := "source.sh", "binary.sh"
+	impl := &compiler{
+		sandbox: sandbox,
+		name:    "sh",
+		config: models.CompilerConfig{
+			Compile: &models.CompilerCommandConfig{
+				Command: "cp source.sh binary.sh",
+				Source:  &source,
+				Binary:  &binary,
+			},
+			Execute: &models.CompilerCommandConfig{
+				Command: "sh binary.sh",
+				Binary:  &binary,
+			},
+			Tests: &models.CompilerTestsConfig{
+				Version:    "echo sh 1.0",
+				HelloWorld: "echo 'Hello, World!'",
+				APlusB:     "read a b; echo $((a + b))",
+			},
+		},
+	}
+	state := models.CompilerState{}
+	if err := runCompilerTests(
+		context.Background(), impl, impl.config.Tests, t.TempDir(), &state,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if state.Version != "sh 1.0" {
+		t.Fatalf("Expected %q, got %q", "sh 1.0", state.Version)
+	}
+	impl.config.Tests.APlusB = "read a b; echo $((a - b))"
+	if err := runCompilerTests(
+		context.Background(), impl, impl.config.Tests, t.TempDir(), &state,
+	); err == nil {
+		t.Fatal("Expected error")
+	}
+}
@@ -35,9 +35,10 @@ type problemExecutableConfig struct {
 }
 
 type problemConfig struct {
-	Version     string                    `json:"version"`
-	Executables []problemExecutableConfig `json:"executables,omitempty"`
-	TestGroups  []problemTestGroupConfig  `json:"test_groups,omitempty"`
+	Version        string                    `json:"version"`
+	Executables    []problemExecutableConfig `json:"executables,omitempty"`
+	TestGroups     []problemTestGroupConfig  `json:"test_groups,omitempty"`
+	DefaultChecker *DefaultCheckerOptions    `json:"default_checker,omitempty"`
 }
 
 const problemConfigVersion = "0.1"
@@ -97,6 +98,9 @@ func buildCompiledProblem(problem Problem, target string) error {
 		}
 		config.Executables = append(config.Executables, executableConfig)
 	}
+	if p, ok := problem.(defaultCheckerProblem); ok {
+		config.DefaultChecker = p.GetDefaultChecker()
+	}
 	groups, err := problem.GetTestGroups()
 	if err != nil {
 		return err
@@ -228,6 +232,10 @@ func (p *compiledProblem) GetExecutables() ([]ProblemExecutable, error) {
 	return executables, nil
 }
 
+func (p *compiledProblem) GetDefaultChecker() *DefaultCheckerOptions {
+	return p.config.DefaultChecker
+}
+
 func (p *compiledProblem) GetTestGroups() ([]ProblemTestGroup, error) {
 	var groups []ProblemTestGroup
 	for _, group := range p.config.TestGroups {
@@ -271,4 +279,7 @@ func (g *compiledProblemTestGroup) GetTests() ([]ProblemTest, error) {
 	return tests, nil
 }
 
-var _ Problem = (*compiledProblem)(nil)
+var (
+	_ Problem               = (*compiledProblem)(nil)
+	_ defaultCheckerProblem = (*compiledProblem)(nil)
+)
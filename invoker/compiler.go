@@ -9,14 +9,18 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/udovin/algo/futures"
+	"golang.org/x/sys/unix"
+
 	"github.com/udovin/solve/core"
 	"github.com/udovin/solve/managers"
 	"github.com/udovin/solve/models"
 	"github.com/udovin/solve/pkg"
 	"github.com/udovin/solve/pkg/logs"
+	"github.com/udovin/solve/pkg/oci"
 )
 
 type MountFile struct {
@@ -44,35 +48,81 @@ type CompileOptions struct {
 }
 
 type ExecuteReport struct {
-	ExitCode   int
-	UsedTime   time.Duration
-	UsedMemory int64
+	ExitCode              int
+	UsedTime              time.Duration
+	UsedMemory            int64
+	UserTime              time.Duration
+	SystemTime            time.Duration
+	WallTime              time.Duration
+	PeakMemory            int64
+	Signal                syscall.Signal
+	OOMKilled             bool
+	PidsLimitExceeded     bool
+	FileSizeLimitExceeded bool
+	SecurityViolation     bool
 }
 
 func (r ExecuteReport) Success() bool {
 	return r.ExitCode == 0
 }
 
+// Violation returns true if process violated sandbox restrictions.
+func (r ExecuteReport) Violation() bool {
+	return r.SecurityViolation || r.PidsLimitExceeded || r.FileSizeLimitExceeded
+}
+
+// Usage returns usage report for specified execution.
+func (r ExecuteReport) Usage() models.UsageReport {
+	usage := models.UsageReport{
+		Time:       r.UsedTime.Milliseconds(),
+		Memory:     r.UsedMemory,
+		UserTime:   r.UserTime.Milliseconds(),
+		SystemTime: r.SystemTime.Milliseconds(),
+		WallTime:   r.WallTime.Milliseconds(),
+		PeakMemory: r.PeakMemory,
+		OOMKilled:  r.OOMKilled,
+	}
+	if r.Signal != 0 {
+		usage.Signal = unix.SignalName(r.Signal)
+	}
+	return usage
+}
+
+func applySandboxConfig(
+	config *sandboxProcessConfig, sandbox *models.CompilerSandboxConfig,
+) {
+	if sandbox == nil {
+		return
+	}
+	config.PidsLimit = sandbox.PidsLimit
+	config.FileSizeLimit = sandbox.FileSizeLimit
+	config.Syscalls = sandbox.Syscalls
+}
+
 type ExecuteOptions struct {
 	Binary      string
 	Args        []string
 	InputFiles  []MountFile
 	OutputFiles []MountFile
+	// OutputDir contains path to directory where all files created
+	// in working directory should be copied.
+	OutputDir   string
 	TimeLimit   time.Duration
 	MemoryLimit int64
 }
 
 type Compiler interface {
 	Name() string
+	Version(ctx context.Context) (string, error)
 	Compile(ctx context.Context, options CompileOptions) (CompileReport, error)
 	Execute(ctx context.Context, options ExecuteOptions) (ExecuteReport, error)
 }
 
 type compiler struct {
-	safeexec *safeexecProcessor
-	name     string
-	config   models.CompilerConfig
-	path     string
+	sandbox Sandbox
+	name    string
+	config  models.CompilerConfig
+	image   compilerImage
 }
 
 func (c *compiler) Name() string {
@@ -99,6 +149,50 @@ func (b *truncateBuffer) Write(p []byte) (int, error) {
 	return l, nil
 }
 
+// Version runs version command of compiler and returns its output.
+func (c *compiler) Version(ctx context.Context) (string, error) {
+	if c.config.Tests == nil || c.config.Tests.Version == "" {
+		return "", nil
+	}
+	// Version command is executed in compilation environment if it
+	// is available, because interpreted languages have no compilation.
+	command := c.config.Execute
+	if c.config.Compile != nil {
+		command = c.config.Compile
+	}
+	if command == nil {
+		return "", fmt.Errorf("compiler has no commands")
+	}
+	output := truncateBuffer{limit: 256}
+	config := sandboxProcessConfig{
+		Layers:      c.image.Layers,
+		Command:     strings.Fields(c.config.Tests.Version),
+		Environ:     c.image.environ(command.Environ),
+		Workdir:     command.Workdir,
+		Stdout:      &output,
+		Stderr:      &output,
+		TimeLimit:   5 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	}
+	applySandboxConfig(&config, command.Sandbox)
+	process, err := c.sandbox.Create(ctx, config)
+	if err != nil {
+		return "", fmt.Errorf("unable to create compiler: %w", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		return "", fmt.Errorf("cannot start compiler: %w", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		return "", err
+	}
+	if report.ExitCode != 0 {
+		return "", fmt.Errorf("version command exited with code %d", report.ExitCode)
+	}
+	return strings.TrimSpace(output.String()), nil
+}
+
 func (c *compiler) Compile(
 	ctx context.Context, options CompileOptions,
 ) (CompileReport, error) {
@@ -109,17 +203,18 @@ func (c *compiler) Compile(
 		return CompileReport{}, nil
 	}
 	log := truncateBuffer{limit: 2048}
-	config := safeexecProcessConfig{
-		Layers:      []string{c.path},
+	config := sandboxProcessConfig{
+		Layers:      c.image.Layers,
 		Command:     strings.Fields(c.config.Compile.Command),
-		Environ:     c.config.Compile.Environ,
+		Environ:     c.image.environ(c.config.Compile.Environ),
 		Workdir:     c.config.Compile.Workdir,
 		Stdout:      &log,
 		Stderr:      &log,
 		TimeLimit:   options.TimeLimit,
 		MemoryLimit: options.MemoryLimit,
 	}
-	process, err := c.safeexec.Create(ctx, config)
+	applySandboxConfig(&config, c.config.Compile.Sandbox)
+	process, err := c.sandbox.Create(ctx, config)
 	if err != nil {
 		return CompileReport{}, fmt.Errorf("unable to create compiler: %w", err)
 	}
@@ -210,13 +305,12 @@ func (c *compiler) Execute(ctx context.Context, options ExecuteOptions) (Execute
 		} else {
 			stderr = file
 		}
-		break
 	}
 	executeArgs := append(strings.Fields(c.config.Execute.Command), options.Args...)
-	config := safeexecProcessConfig{
-		Layers:      []string{c.path},
+	config := sandboxProcessConfig{
+		Layers:      c.image.Layers,
 		Command:     executeArgs,
-		Environ:     c.config.Execute.Environ,
+		Environ:     c.image.environ(c.config.Execute.Environ),
 		Workdir:     c.config.Execute.Workdir,
 		Stdin:       stdin,
 		Stdout:      stdout,
@@ -224,7 +318,8 @@ func (c *compiler) Execute(ctx context.Context, options ExecuteOptions) (Execute
 		TimeLimit:   options.TimeLimit,
 		MemoryLimit: options.MemoryLimit,
 	}
-	process, err := c.safeexec.Create(ctx, config)
+	applySandboxConfig(&config, c.config.Execute.Sandbox)
+	process, err := c.sandbox.Create(ctx, config)
 	if err != nil {
 		return ExecuteReport{}, fmt.Errorf("unable to create compiler: %w", err)
 	}
@@ -273,21 +368,57 @@ func (c *compiler) Execute(ctx context.Context, options ExecuteOptions) (Execute
 				return ExecuteReport{}, fmt.Errorf("unable to copy binary: %w", err)
 			}
 		}
+		if options.OutputDir != "" {
+			if err := copyDirFiles(
+				filepath.Join(process.GetUpperDir(), c.config.Execute.Workdir),
+				options.OutputDir,
+			); err != nil {
+				return ExecuteReport{}, fmt.Errorf("unable to copy output files: %w", err)
+			}
+		}
 	}
 	return ExecuteReport{
-		ExitCode:   report.ExitCode,
-		UsedTime:   report.Time,
-		UsedMemory: report.Memory,
+		ExitCode:              report.ExitCode,
+		UsedTime:              report.Time,
+		UsedMemory:            report.Memory,
+		UserTime:              report.UserTime,
+		SystemTime:            report.SystemTime,
+		WallTime:              report.WallTime,
+		PeakMemory:            report.PeakMemory,
+		Signal:                report.Signal,
+		OOMKilled:             report.OOMKilled,
+		PidsLimitExceeded:     report.PidsLimitExceeded,
+		FileSizeLimitExceeded: report.FileSizeLimitExceeded,
+		SecurityViolation:     report.SecurityViolation,
 	}, nil
 }
 
+// compilerImage represents extracted compiler image.
+type compilerImage struct {
+	// Layers contains paths to image layers ordered from top to base.
+	Layers []string
+	// Environ contains default environment of image.
+	Environ []string
+}
+
+// environ returns image environment overridden by specified variables.
+func (i compilerImage) environ(environ []string) []string {
+	if len(i.Environ) == 0 {
+		return environ
+	}
+	result := make([]string, 0, len(i.Environ)+len(environ))
+	result = append(result, i.Environ...)
+	return append(result, environ...)
+}
+
 type compilerManager struct {
 	files     *managers.FileManager
 	cacheDir  string
-	safeexec  *safeexecProcessor
+	sandbox   Sandbox
 	compilers *models.CompilerStore
 	settings  *models.SettingStore
-	images    map[int64]futures.Future[string]
+	images    map[int64]futures.Future[compilerImage]
+	layers    map[string]futures.Future[string]
 	logger    *logs.Logger
 	mutex     sync.Mutex
 }
@@ -295,7 +426,7 @@ type compilerManager struct {
 func newCompilerManager(
 	files *managers.FileManager,
 	cacheDir string,
-	safeexec *safeexecProcessor,
+	sandbox Sandbox,
 	core *core.Core,
 ) (*compilerManager, error) {
 	if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
@@ -304,10 +435,11 @@ func newCompilerManager(
 	return &compilerManager{
 		files:     files,
 		cacheDir:  cacheDir,
-		safeexec:  safeexec,
+		sandbox:   sandbox,
 		compilers: core.Compilers,
 		settings:  core.Settings,
-		images:    map[int64]futures.Future[string]{},
+		images:    map[int64]futures.Future[compilerImage]{},
+		layers:    map[string]futures.Future[string]{},
 		logger:    core.Logger(),
 	}, nil
 }
@@ -336,25 +468,25 @@ func (m *compilerManager) DownloadCompiler(ctx context.Context, c models.Compile
 	if err != nil {
 		return nil, err
 	}
-	imagePath, err := m.downloadImageAsync(ctx, c.ImageID).Get(ctx)
+	image, err := m.downloadImageAsync(ctx, c.ImageID).Get(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return &compiler{
-		safeexec: m.safeexec,
-		path:     imagePath,
-		name:     c.Name,
-		config:   config,
+		sandbox: m.sandbox,
+		image:   image,
+		name:    c.Name,
+		config:  config,
 	}, nil
 }
 
-func (m *compilerManager) downloadImageAsync(ctx context.Context, imageID int64) futures.Future[string] {
+func (m *compilerManager) downloadImageAsync(ctx context.Context, imageID int64) futures.Future[compilerImage] {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	if image, ok := m.images[imageID]; ok {
 		return image
 	}
-	future, setResult := futures.New[string]()
+	future, setResult := futures.New[compilerImage]()
 	m.images[imageID] = future
 	go func() {
 		image, err := m.runDownloadImage(ctx, imageID)
@@ -366,10 +498,10 @@ func (m *compilerManager) downloadImageAsync(ctx context.Context, imageID int64)
 	return future
 }
 
-func (m *compilerManager) runDownloadImage(ctx context.Context, imageID int64) (string, error) {
+func (m *compilerManager) runDownloadImage(ctx context.Context, imageID int64) (compilerImage, error) {
 	imageFile, err := m.files.DownloadFile(ctx, imageID)
 	if err != nil {
-		return "", err
+		return compilerImage{}, err
 	}
 	defer func() { _ = imageFile.Close() }()
 	localImagePath := filepath.Join(m.cacheDir, fmt.Sprintf("image-%d.tar.gz", imageID))
@@ -381,23 +513,96 @@ func (m *compilerManager) runDownloadImage(ctx context.Context, imageID int64) (
 	} else {
 		localImageFile, err := os.Create(localImagePath)
 		if err != nil {
-			return "", err
+			return compilerImage{}, err
 		}
 		defer func() {
 			_ = localImageFile.Close()
 			_ = os.Remove(localImagePath)
 		}()
 		if _, err := io.Copy(localImageFile, imageFile); err != nil {
-			return "", err
+			return compilerImage{}, err
 		}
 		if err := localImageFile.Close(); err != nil {
-			return "", err
+			return compilerImage{}, err
 		}
 	}
+	isOCI, err := oci.IsImageArchive(localImagePath)
+	if err != nil {
+		return compilerImage{}, err
+	}
+	if isOCI {
+		return m.importImage(ctx, localImagePath, imagePath)
+	}
 	if err := pkg.ExtractTarGz(localImagePath, imagePath); err != nil {
-		return "", fmt.Errorf("cannot extract image: %w", err)
+		return compilerImage{}, fmt.Errorf("cannot extract image: %w", err)
+	}
+	return compilerImage{Layers: []string{imagePath}}, nil
+}
+
+// importImage imports image saved by "docker save" or in OCI layout.
+//
+// Layers are extracted into shared directory by their digests, so
+// compilers with common base image reuse the same layers on disk.
+func (m *compilerManager) importImage(
+	ctx context.Context, source, target string,
+) (compilerImage, error) {
+	image, err := oci.ExtractImage(source, target)
+	if err != nil {
+		return compilerImage{}, fmt.Errorf("cannot extract image: %w", err)
+	}
+	// Layer tarballs are not required after extraction.
+	defer func() { _ = os.RemoveAll(target) }()
+	result := compilerImage{Environ: image.Config.Env}
+	for _, layer := range image.Layers {
+		layerPath, err := m.extractLayerAsync(layer).Get(ctx)
+		if err != nil {
+			return compilerImage{}, err
+		}
+		// Overlay expects top layer to be the first one.
+		result.Layers = append([]string{layerPath}, result.Layers...)
+	}
+	return result, nil
+}
+
+func (m *compilerManager) extractLayerAsync(layer oci.Layer) futures.Future[string] {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if future, ok := m.layers[layer.Digest]; ok {
+		return future
+	}
+	future, setResult := futures.New[string]()
+	m.layers[layer.Digest] = future
+	go func() {
+		path, err := m.runExtractLayer(layer)
+		if err != nil {
+			m.mutex.Lock()
+			delete(m.layers, layer.Digest)
+			m.mutex.Unlock()
+		}
+		setResult(path, err)
+	}()
+	return future
+}
+
+func (m *compilerManager) runExtractLayer(layer oci.Layer) (string, error) {
+	_, hash, ok := strings.Cut(layer.Digest, ":")
+	if !ok || hash == "" || strings.ContainsAny(hash, "/.") {
+		return "", fmt.Errorf("invalid layer digest %q", layer.Digest)
+	}
+	layerPath := filepath.Join(m.cacheDir, "layers", hash)
+	if _, err := os.Stat(layerPath); err == nil {
+		return layerPath, nil
+	}
+	tempPath := layerPath + ".tmp"
+	_ = os.RemoveAll(tempPath)
+	if err := oci.ExtractLayer(layer, tempPath); err != nil {
+		return "", fmt.Errorf("cannot extract layer %q: %w", layer.Digest, err)
+	}
+	if err := os.Rename(tempPath, layerPath); err != nil {
+		_ = os.RemoveAll(tempPath)
+		return "", err
 	}
-	return imagePath, nil
+	return layerPath, nil
 }
 
 func (m *compilerManager) deleteImage(imageID int64) {
@@ -0,0 +1,173 @@
+package invoker
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/udovin/solve/models"
+)
+
+// DefaultCheckerOptions represents options of built-in token checker.
+//
+// Semantics of options matches default output validator from ICPC
+// problem package format.
+type DefaultCheckerOptions struct {
+	CaseSensitive          bool    `json:"case_sensitive,omitempty"`
+	SpaceChangeSensitive   bool    `json:"space_change_sensitive,omitempty"`
+	FloatAbsoluteTolerance float64 `json:"float_absolute_tolerance,omitempty"`
+	FloatRelativeTolerance float64 `json:"float_relative_tolerance,omitempty"`
+}
+
+// parseDefaultCheckerOptions parses options from validator flags.
+func parseDefaultCheckerOptions(flags string) (DefaultCheckerOptions, error) {
+	options := DefaultCheckerOptions{}
+	fields := strings.Fields(flags)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; field {
+		case "case_sensitive":
+			options.CaseSensitive = true
+		case "space_change_sensitive":
+			options.SpaceChangeSensitive = true
+		case "float_tolerance", "float_absolute_tolerance", "float_relative_tolerance":
+			i++
+			if i >= len(fields) {
+				return DefaultCheckerOptions{}, fmt.Errorf("flag %q requires argument", field)
+			}
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return DefaultCheckerOptions{}, fmt.Errorf("cannot parse %q: %w", field, err)
+			}
+			if field != "float_relative_tolerance" {
+				options.FloatAbsoluteTolerance = value
+			}
+			if field != "float_absolute_tolerance" {
+				options.FloatRelativeTolerance = value
+			}
+		default:
+			return DefaultCheckerOptions{}, fmt.Errorf("unsupported flag %q", field)
+		}
+	}
+	return options, nil
+}
+
+// defaultCheckerProblem represents problem that should be checked with
+// built-in token checker.
+type defaultCheckerProblem interface {
+	// GetDefaultChecker returns options for built-in checker or nil
+	// if problem uses custom checker.
+	GetDefaultChecker() *DefaultCheckerOptions
+}
+
+func (o DefaultCheckerOptions) isFloat() bool {
+	return o.FloatAbsoluteTolerance > 0 || o.FloatRelativeTolerance > 0
+}
+
+func (o DefaultCheckerOptions) compareToken(output, answer string) bool {
+	if o.isFloat() {
+		answerValue, err := strconv.ParseFloat(answer, 64)
+		if err == nil {
+			outputValue, err := strconv.ParseFloat(output, 64)
+			if err != nil || math.IsNaN(outputValue) {
+				return false
+			}
+			diff := math.Abs(outputValue - answerValue)
+			return diff <= o.FloatAbsoluteTolerance ||
+				diff <= o.FloatRelativeTolerance*math.Abs(answerValue)
+		}
+	}
+	if o.CaseSensitive {
+		return output == answer
+	}
+	return strings.EqualFold(output, answer)
+}
+
+func readCheckerTokens(path string, lines bool) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	if !lines {
+		scanner.Split(bufio.ScanWords)
+	}
+	var tokens []string
+	for scanner.Scan() {
+		tokens = append(tokens, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if lines {
+		// Ignore trailing empty lines.
+		for len(tokens) > 0 && len(tokens[len(tokens)-1]) == 0 {
+			tokens = tokens[:len(tokens)-1]
+		}
+	}
+	return tokens, nil
+}
+
+// runDefaultChecker compares output with answer and returns verdict with log.
+func runDefaultChecker(
+	options DefaultCheckerOptions, outputPath, answerPath string,
+) (models.Verdict, string, error) {
+	outputTokens, err := readCheckerTokens(outputPath, options.SpaceChangeSensitive)
+	if err != nil {
+		return 0, "", fmt.Errorf("cannot read output: %w", err)
+	}
+	answerTokens, err := readCheckerTokens(answerPath, options.SpaceChangeSensitive)
+	if err != nil {
+		return 0, "", fmt.Errorf("cannot read answer: %w", err)
+	}
+	if options.SpaceChangeSensitive {
+		if len(outputTokens) != len(answerTokens) {
+			return models.WrongAnswer, fmt.Sprintf(
+				"expected %d lines, found %d", len(answerTokens), len(outputTokens),
+			), nil
+		}
+		for i := range answerTokens {
+			outputLine := strings.Split(outputTokens[i], " ")
+			answerLine := strings.Split(answerTokens[i], " ")
+			if len(outputLine) != len(answerLine) {
+				return models.WrongAnswer, fmt.Sprintf("line %d differs", i+1), nil
+			}
+			for j := range answerLine {
+				if !options.compareToken(outputLine[j], answerLine[j]) {
+					return models.WrongAnswer, fmt.Sprintf("line %d differs", i+1), nil
+				}
+			}
+		}
+		return models.Accepted, fmt.Sprintf("%d lines", len(answerTokens)), nil
+	}
+	for i := range answerTokens {
+		if i >= len(outputTokens) {
+			return models.WrongAnswer, fmt.Sprintf(
+				"expected %d tokens, found %d", len(answerTokens), len(outputTokens),
+			), nil
+		}
+		if !options.compareToken(outputTokens[i], answerTokens[i]) {
+			return models.WrongAnswer, fmt.Sprintf(
+				"token %d differs: expected %q, found %q",
+				i+1, truncateToken(answerTokens[i]), truncateToken(outputTokens[i]),
+			), nil
+		}
+	}
+	if len(outputTokens) > len(answerTokens) {
+		return models.WrongAnswer, fmt.Sprintf(
+			"expected %d tokens, found %d", len(answerTokens), len(outputTokens),
+		), nil
+	}
+	return models.Accepted, fmt.Sprintf("%d tokens", len(answerTokens)), nil
+}
+
+func truncateToken(token string) string {
+	if len(token) > 32 {
+		return strings.ToValidUTF8(token[:32], "") + "..."
+	}
+	return token
+}
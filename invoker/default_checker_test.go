@@ -0,0 +1,67 @@
+package invoker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestDefaultChecker(t *testing.T) {
+	dir := t.TempDir()
+	answerPath := filepath.Join(dir, "answer")
+	outputPath := filepath.Join(dir, "output")
+	check := func(options DefaultCheckerOptions, output, answer string) models.Verdict {
+		if err := os.WriteFile(answerPath, []byte(answer), 0644); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := os.WriteFile(outputPath, []byte(output), 0644); err != nil {
+			t.Fatal("Error:", err)
+		}
+		verdict, _, err := runDefaultChecker(options, outputPath, answerPath)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		return verdict
+	}
+	tests := []struct {
+		Flags   string
+		Output  string
+		Answer  string
+		Verdict models.Verdict
+	}{
+		{"", "1 2\n3", "1  2 3\n", models.Accepted},
+		{"", "YES", "yes", models.Accepted},
+		{"", "1 2", "1 2 3", models.WrongAnswer},
+		{"", "1 2 3 4", "1 2 3", models.WrongAnswer},
+		{"case_sensitive", "YES", "yes", models.WrongAnswer},
+		{"space_change_sensitive", "1  2", "1 2", models.WrongAnswer},
+		{"space_change_sensitive", "1 2\n", "1 2\n\n", models.Accepted},
+		{"float_tolerance 1e-6", "1.0000001", "1", models.Accepted},
+		{"float_tolerance 1e-6", "1.001", "1", models.WrongAnswer},
+		{"float_relative_tolerance 1e-3", "1000.5", "1000", models.Accepted},
+		{"float_absolute_tolerance 1e-3", "1000.5", "1000", models.WrongAnswer},
+	}
+	for _, test := range tests {
+		options, err := parseDefaultCheckerOptions(test.Flags)
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if verdict := check(options, test.Output, test.Answer); verdict != test.Verdict {
+			t.Errorf(
+				"Flags %q, output %q, answer %q: expected %v, got %v",
+				test.Flags, test.Output, test.Answer, test.Verdict, verdict,
+			)
+		}
+	}
+}
+
+func TestParseDefaultCheckerOptions(t *testing.T) {
+	if _, err := parseDefaultCheckerOptions("float_tolerance"); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := parseDefaultCheckerOptions("unknown_flag"); err == nil {
+		t.Fatal("Expected error")
+	}
+}
@@ -0,0 +1,115 @@
+package invoker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// fakeSandbox runs processes directly on host without any isolation.
+//
+// Layers are ignored and command is executed in upper directory, so
+// all paths should be relative to workdir. It should be used only for
+// testing purposes.
+type fakeSandbox struct {
+	path string
+	// usage allows to override usage reported for process.
+	usage func(config sandboxProcessConfig, report *sandboxReport)
+}
+
+var _ Sandbox = (*fakeSandbox)(nil)
+
+func newFakeSandbox(path string) (*fakeSandbox, error) {
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &fakeSandbox{path: path}, nil
+}
+
+func (s *fakeSandbox) Create(ctx context.Context, config sandboxProcessConfig) (sandboxProcess, error) {
+	if len(config.Command) == 0 {
+		return nil, errors.New("empty command")
+	}
+	path, err := os.MkdirTemp(s.path, "process-")
+	if err != nil {
+		return nil, err
+	}
+	workdir := filepath.Join(path, "upper", config.Workdir)
+	if err := os.MkdirAll(workdir, os.ModePerm); err != nil {
+		_ = os.RemoveAll(path)
+		return nil, err
+	}
+	var cancel context.CancelFunc
+	if config.TimeLimit > 0 {
+		ctx, cancel = context.WithTimeout(ctx, config.TimeLimit)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	cmd := exec.CommandContext(ctx, config.Command[0], config.Command[1:]...)
+	cmd.Dir = workdir
+	cmd.Env = append(os.Environ(), config.Environ...)
+	cmd.Stdin = config.Stdin
+	cmd.Stdout = config.Stdout
+	cmd.Stderr = config.Stderr
+	return &fakeProcess{
+		sandbox: s,
+		config:  config,
+		path:    path,
+		cmd:     cmd,
+		ctx:     ctx,
+		cancel:  cancel,
+	}, nil
+}
+
+type fakeProcess struct {
+	sandbox *fakeSandbox
+	config  sandboxProcessConfig
+	path    string
+	cmd     *exec.Cmd
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func (p *fakeProcess) Start() error {
+	return p.cmd.Start()
+}
+
+func (p *fakeProcess) Wait() (sandboxReport, error) {
+	err := p.cmd.Wait()
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return sandboxReport{}, err
+		}
+	}
+	report := sandboxReport{
+		ExitCode: p.cmd.ProcessState.ExitCode(),
+	}
+	if status, ok := p.cmd.ProcessState.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		report.Signal = status.Signal()
+	}
+	if errors.Is(p.ctx.Err(), context.DeadlineExceeded) {
+		report.Time = p.config.TimeLimit + time.Millisecond
+		report.WallTime = report.Time
+	}
+	if p.sandbox.usage != nil {
+		p.sandbox.usage(p.config, &report)
+	}
+	return report, nil
+}
+
+func (p *fakeProcess) Release() error {
+	p.cancel()
+	if p.cmd.Process != nil && p.cmd.ProcessState == nil {
+		_ = p.cmd.Process.Kill()
+		_ = p.cmd.Wait()
+	}
+	return os.RemoveAll(p.path)
+}
+
+func (p *fakeProcess) GetUpperDir() string {
+	return filepath.Join(p.path, "upper")
+}
@@ -0,0 +1,144 @@
+package invoker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestFakeSandbox(t *testing.T) {
+	sandbox, err := newFakeSandbox(t.TempDir())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	sandbox.usage = func(config sandboxProcessConfig, report *sandboxReport) {
+		report.Time = 100 * time.Millisecond
+		report.Memory = 1024
+	}
+	stdout := strings.Builder{}
+	process, err := sandbox.Create(context.Background(), sandboxProcessConfig{
+		Command:     []string{"sh", "-c", "echo -n 'solve_test' && exit 3"},
+		TimeLimit:   time.Second,
+		MemoryLimit: 1024 * 1024,
+		Stdout:      &stdout,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if report.ExitCode != 3 {
+		t.Fatal("Exit code:", report.ExitCode)
+	}
+	if report.Time != 100*time.Millisecond || report.Memory != 1024 {
+		t.Fatal("Invalid usage:", report.Time, report.Memory)
+	}
+	if s := stdout.String(); s != "solve_test" {
+		t.Fatal("Expected:", "solve_test", "got:", s)
+	}
+}
+
+func TestFakeSandboxTimeLimit(t *testing.T) {
+	sandbox, err := newFakeSandbox(t.TempDir())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	process, err := sandbox.Create(context.Background(), sandboxProcessConfig{
+		Command:   []string{"sleep", "5"},
+		TimeLimit: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if report.ExitCode == 0 {
+		t.Fatal("Expected non-zero exit code")
+	}
+	if report.Time <= 100*time.Millisecond {
+		t.Fatal("Invalid time:", report.Time.Milliseconds())
+	}
+}
+
+func TestCompilerFakeSandbox(t *testing.T) {
+	sandbox, err := newFakeSandbox(t.TempDir())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	source, binary := "source.sh", "binary.sh"
+	impl := compiler{
+		sandbox: sandbox,
+		name:    "sh",
+		config: models.CompilerConfig{
+			Compile: &models.CompilerCommandConfig{
+				Command: "cp source.sh binary.sh",
+				Source:  &source,
+				Binary:  &binary,
+			},
+			Execute: &models.CompilerCommandConfig{
+				Command: "sh binary.sh",
+				Binary:  &binary,
+			},
+		},
+	}
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "source")
+	if err := os.WriteFile(sourcePath, []byte("read a b; echo $((a + b))"), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	binaryPath := filepath.Join(tempDir, "binary")
+	compileReport, err := impl.Compile(context.Background(), CompileOptions{
+		Source:      sourcePath,
+		Target:      binaryPath,
+		TimeLimit:   time.Second,
+		MemoryLimit: 1024 * 1024,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !compileReport.Success() {
+		t.Fatal("Compilation failed:", compileReport.Log)
+	}
+	inputPath := filepath.Join(tempDir, "input")
+	if err := os.WriteFile(inputPath, []byte("1 2\n"), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	outputPath := filepath.Join(tempDir, "output")
+	executeReport, err := impl.Execute(context.Background(), ExecuteOptions{
+		Binary:      binaryPath,
+		InputFiles:  []MountFile{{Source: inputPath, Target: "stdin"}},
+		OutputFiles: []MountFile{{Source: outputPath, Target: "stdout"}},
+		TimeLimit:   time.Second,
+		MemoryLimit: 1024 * 1024,
+	})
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !executeReport.Success() {
+		t.Fatal("Exit code:", executeReport.ExitCode)
+	}
+	output, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if s := string(output); s != "3\n" {
+		t.Fatalf("Expected %q, got %q", "3\n", s)
+	}
+}
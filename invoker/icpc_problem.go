@@ -0,0 +1,478 @@
+package invoker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/models"
+	"github.com/udovin/solve/pkg"
+	"github.com/udovin/solve/pkg/icpc"
+	"github.com/udovin/solve/pkg/logs"
+)
+
+func extractICPCProblem(
+	source, target string, compilers *compilerManager,
+) (Problem, error) {
+	if err := pkg.ExtractZip(source, target); err != nil {
+		return nil, fmt.Errorf("cannot extract problem: %w", err)
+	}
+	root, err := findICPCProblemRoot(target)
+	if err != nil {
+		_ = os.RemoveAll(target)
+		return nil, err
+	}
+	config, err := icpc.ReadProblemConfig(filepath.Join(root, "problem.yaml"))
+	if err != nil {
+		_ = os.RemoveAll(target)
+		return nil, fmt.Errorf("cannot read problem config: %w", err)
+	}
+	if config.IsInteractive() {
+		_ = os.RemoveAll(target)
+		return nil, fmt.Errorf("interactive problems are not supported")
+	}
+	timeLimit, err := getICPCTimeLimit(root, config)
+	if err != nil {
+		_ = os.RemoveAll(target)
+		return nil, err
+	}
+	problem := icpcProblem{
+		path:      root,
+		config:    config,
+		compilers: compilers,
+		timeLimit: timeLimit,
+	}
+	if !config.IsCustomValidation() {
+		options, err := parseDefaultCheckerOptions(config.ValidatorFlags)
+		if err != nil {
+			_ = os.RemoveAll(target)
+			return nil, fmt.Errorf("cannot parse validator flags: %w", err)
+		}
+		problem.defaultChecker = &options
+	}
+	return &problem, nil
+}
+
+// findICPCProblemRoot returns directory with problem.yaml.
+//
+// Packages are often archived with top-level directory named as
+// short name of problem, so we should look into it too.
+func findICPCProblemRoot(path string) (string, error) {
+	if _, err := os.Stat(filepath.Join(path, "problem.yaml")); err == nil {
+		return path, nil
+	}
+	files, err := os.ReadDir(path)
+	if err != nil {
+		return "", err
+	}
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		root := filepath.Join(path, file.Name())
+		if _, err := os.Stat(filepath.Join(root, "problem.yaml")); err == nil {
+			return root, nil
+		}
+	}
+	return "", fmt.Errorf("cannot find problem.yaml")
+}
+
+// icpcLanguages contains mapping from source extension to language.
+var icpcLanguages = map[string]string{
+	".c":   "c",
+	".cc":  "cpp",
+	".cpp": "cpp",
+	".cxx": "cpp",
+	".py":  "python3",
+}
+
+type icpcProblem struct {
+	path           string
+	config         icpc.Problem
+	compilers      *compilerManager
+	defaultChecker *DefaultCheckerOptions
+	timeLimit      int64
+}
+
+type icpcValidator struct {
+	dir      string
+	source   string
+	language string
+}
+
+func (v icpcValidator) binaryPath() string {
+	return filepath.Join(v.dir, "validator")
+}
+
+func (p *icpcProblem) findValidator() (icpcValidator, error) {
+	for _, name := range []string{"output_validators", "output_validator"} {
+		dir := filepath.Join(p.path, name)
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return icpcValidator{}, err
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				validator, err := findICPCValidatorSource(filepath.Join(dir, file.Name()))
+				if err != nil {
+					return icpcValidator{}, err
+				}
+				if validator.source != "" {
+					return validator, nil
+				}
+			}
+		}
+		validator, err := findICPCValidatorSource(dir)
+		if err != nil {
+			return icpcValidator{}, err
+		}
+		if validator.source != "" {
+			return validator, nil
+		}
+	}
+	return icpcValidator{}, fmt.Errorf("cannot find output validator")
+}
+
+func findICPCValidatorSource(dir string) (icpcValidator, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return icpcValidator{}, err
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		language, ok := icpcLanguages[filepath.Ext(file.Name())]
+		if !ok {
+			continue
+		}
+		return icpcValidator{
+			dir:      dir,
+			source:   file.Name(),
+			language: language,
+		}, nil
+	}
+	return icpcValidator{}, nil
+}
+
+func (p *icpcProblem) Compile(ctx context.Context) error {
+	if !p.config.IsCustomValidation() {
+		return nil
+	}
+	validator, err := p.findValidator()
+	if err != nil {
+		return err
+	}
+	compilerName, err := p.compilers.GetCompilerName("icpc." + validator.language)
+	if err != nil {
+		return err
+	}
+	compiler, err := p.compilers.GetCompiler(ctx, compilerName)
+	if err != nil {
+		return err
+	}
+	files, err := os.ReadDir(validator.dir)
+	if err != nil {
+		return err
+	}
+	resources := []MountFile{}
+	for _, file := range files {
+		if file.IsDir() || file.Name() == validator.source {
+			continue
+		}
+		resources = append(resources, MountFile{
+			Source: filepath.Join(validator.dir, file.Name()),
+			Target: file.Name(),
+		})
+	}
+	report, err := compiler.Compile(ctx, CompileOptions{
+		Source:      filepath.Join(validator.dir, validator.source),
+		Target:      validator.binaryPath(),
+		InputFiles:  resources,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return err
+	}
+	if !report.Success() {
+		return fmt.Errorf(
+			"cannot compile %q with compiler %q: %q",
+			validator.source, compilerName, report.Log,
+		)
+	}
+	p.compilers.logger.Debug(
+		"Compiled output validator",
+		logs.Any("path", validator.source),
+	)
+	return nil
+}
+
+func (p *icpcProblem) GetExecutables() ([]ProblemExecutable, error) {
+	if !p.config.IsCustomValidation() {
+		return nil, nil
+	}
+	validator, err := p.findValidator()
+	if err != nil {
+		return nil, err
+	}
+	compilerName, err := p.compilers.GetCompilerName("icpc." + validator.language)
+	if err != nil {
+		return nil, err
+	}
+	return []ProblemExecutable{
+		problemExecutable{
+			name:       "checker",
+			kind:       ICPCOutputValidator,
+			binaryPath: validator.binaryPath(),
+			compiler:   compilerName,
+		},
+	}, nil
+}
+
+func (p *icpcProblem) GetDefaultChecker() *DefaultCheckerOptions {
+	return p.defaultChecker
+}
+
+// getICPCTimeLimit returns time limit of problem in milliseconds.
+//
+// Time multiplier is applied to running time of slowest accepted
+// solution, so it can be used only when time limit is already
+// calculated and stored in package.
+func getICPCTimeLimit(root string, config icpc.Problem) (int64, error) {
+	seconds := config.Limits.TimeLimit
+	if seconds <= 0 {
+		value, err := icpc.ReadTimeLimit(filepath.Join(root, ".timelimit"))
+		if err == nil {
+			seconds = value
+		}
+	}
+	if seconds <= 0 {
+		if config.Limits.TimeMultiplier != 0 {
+			return 0, fmt.Errorf("time limit with time multiplier is not supported")
+		}
+		seconds = 1
+	}
+	return int64(math.Ceil(seconds * 1000)), nil
+}
+
+func (p *icpcProblem) memoryLimit() int64 {
+	memory := p.config.Limits.Memory
+	if memory <= 0 {
+		memory = icpc.DefaultMemoryLimit
+	}
+	return memory * 1024 * 1024
+}
+
+func (p *icpcProblem) GetTestGroups() ([]ProblemTestGroup, error) {
+	dataDir := filepath.Join(p.path, "data")
+	var groups []ProblemTestGroup
+	addGroup := func(name, dir string) error {
+		tests, err := icpc.ReadTests(dir)
+		if err != nil {
+			return err
+		}
+		if len(tests) == 0 {
+			return nil
+		}
+		groups = append(groups, &icpcProblemTestGroup{
+			problem: p,
+			name:    name,
+			tests:   tests,
+		})
+		return nil
+	}
+	if err := addGroup("sample", filepath.Join(dataDir, "sample")); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	secretDir := filepath.Join(dataDir, "secret")
+	if err := addGroup("secret", secretDir); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(secretDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		if err := addGroup(
+			"secret_"+file.Name(), filepath.Join(secretDir, file.Name()),
+		); err != nil {
+			return nil, err
+		}
+	}
+	return groups, nil
+}
+
+// icpcStatementDirs contains possible directories with statements.
+var icpcStatementDirs = []string{"problem_statement", "statement"}
+
+func (p *icpcProblem) GetStatements() ([]ProblemStatement, error) {
+	var statements []ProblemStatement
+	for _, name := range icpcStatementDirs {
+		dir := filepath.Join(p.path, name)
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		for _, file := range files {
+			if file.IsDir() {
+				continue
+			}
+			locale, ok := getICPCStatementLocale(file.Name())
+			if !ok {
+				continue
+			}
+			statements = append(statements, &icpcProblemStatement{
+				problem: p,
+				dir:     dir,
+				name:    file.Name(),
+				locale:  locale,
+			})
+		}
+	}
+	return statements, nil
+}
+
+func getICPCStatementLocale(name string) (string, bool) {
+	if name == "problem.tex" {
+		return "en", true
+	}
+	if !strings.HasPrefix(name, "problem.") || !strings.HasSuffix(name, ".tex") {
+		return "", false
+	}
+	locale := strings.TrimSuffix(strings.TrimPrefix(name, "problem."), ".tex")
+	if len(locale) == 0 || strings.Contains(locale, ".") {
+		return "", false
+	}
+	return locale, true
+}
+
+type icpcProblemTestGroup struct {
+	problem *icpcProblem
+	name    string
+	tests   []icpc.Test
+}
+
+func (g *icpcProblemTestGroup) Name() string {
+	return g.name
+}
+
+func (g *icpcProblemTestGroup) TimeLimit() int64 {
+	return g.problem.timeLimit
+}
+
+func (g *icpcProblemTestGroup) MemoryLimit() int64 {
+	return g.problem.memoryLimit()
+}
+
+func (g *icpcProblemTestGroup) GetTests() ([]ProblemTest, error) {
+	var tests []ProblemTest
+	for _, test := range g.tests {
+		tests = append(tests, problemTest{
+			inputPath:  test.Input,
+			answerPath: test.Answer,
+		})
+	}
+	return tests, nil
+}
+
+type icpcProblemStatement struct {
+	problem *icpcProblem
+	dir     string
+	name    string
+	locale  string
+}
+
+func (s *icpcProblemStatement) Locale() string {
+	return s.locale
+}
+
+func (s *icpcProblemStatement) GetConfig() (models.ProblemStatementConfig, error) {
+	statement, err := icpc.ReadStatement(filepath.Join(s.dir, s.name))
+	if err != nil {
+		return models.ProblemStatementConfig{}, err
+	}
+	config := models.ProblemStatementConfig{
+		Locale: s.locale,
+		Title:  statement.Name,
+		Legend: statement.Legend,
+		Input:  statement.Input,
+		Output: statement.Output,
+		Notes:  statement.Notes,
+	}
+	if name, ok := s.problem.config.GetNames()[s.locale]; ok {
+		config.Title = name
+	}
+	tests, err := icpc.ReadTests(filepath.Join(s.problem.path, "data", "sample"))
+	if err != nil && !os.IsNotExist(err) {
+		return models.ProblemStatementConfig{}, err
+	}
+	for _, test := range tests {
+		input, err := os.ReadFile(test.Input)
+		if err != nil {
+			return models.ProblemStatementConfig{}, err
+		}
+		output, err := os.ReadFile(test.Answer)
+		if err != nil {
+			return models.ProblemStatementConfig{}, err
+		}
+		config.Samples = append(config.Samples, models.ProblemStatementSample{
+			Input:  string(input),
+			Output: string(output),
+		})
+	}
+	return config, nil
+}
+
+func (s *icpcProblemStatement) GetResources() ([]ProblemResource, error) {
+	config, err := s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	resources := []ProblemResource{}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		name := file.Name()
+		if _, ok := getICPCStatementLocale(name); ok {
+			continue
+		}
+		inStatements := strings.Contains(config.Legend, name) ||
+			strings.Contains(config.Input, name) ||
+			strings.Contains(config.Output, name) ||
+			strings.Contains(config.Notes, name)
+		if !inStatements {
+			continue
+		}
+		resources = append(resources, polygonProblemResource{
+			path: filepath.Join(s.dir, name),
+			name: name,
+		})
+	}
+	return resources, nil
+}
+
+var (
+	_ Problem               = (*icpcProblem)(nil)
+	_ defaultCheckerProblem = (*icpcProblem)(nil)
+)
@@ -0,0 +1,147 @@
+package invoker
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestZip(tb testing.TB, path string, files map[string]string) {
+	file, err := os.Create(path)
+	if err != nil {
+		tb.Fatal("Error:", err)
+	}
+	defer func() { _ = file.Close() }()
+	writer := zip.NewWriter(file)
+	for name, content := range files {
+		header, err := writer.Create(name)
+		if err != nil {
+			tb.Fatal("Error:", err)
+		}
+		if _, err := header.Write([]byte(content)); err != nil {
+			tb.Fatal("Error:", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		tb.Fatal("Error:", err)
+	}
+}
+
+func TestICPCProblem(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "problem.zip")
+	writeTestZip(t, source, map[string]string{
+		"sum/problem.yaml": "name: Sum\nlimits:\n  time_limit: 2\n  memory: 64\n",
+		"sum/problem_statement/problem.tex": "\\problemname{Sum}\nLegend.\n" +
+			"\\section*{Input}\nInput.\n\\section*{Output}\nOutput.\n",
+		"sum/data/sample/1.in":           "1 2\n",
+		"sum/data/sample/1.ans":          "3\n",
+		"sum/data/secret/group1/01.in":   "2 2\n",
+		"sum/data/secret/group1/01.ans":  "4\n",
+		"sum/data/secret/group1/02.in":   "2 3\n",
+		"sum/data/secret/group1/02.ans":  "5\n",
+		"sum/data/secret/group2/01.in":   "0 0\n",
+		"sum/data/secret/group2/01.ans":  "0\n",
+		"sum/output_validators/.gitkeep": "",
+	})
+	problem, err := extractICPCProblem(source, filepath.Join(t.TempDir(), "problem"), nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	groups, err := problem.GetTestGroups()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(groups) != 3 {
+		t.Fatal("Invalid groups count:", len(groups))
+	}
+	expectedGroups := []struct {
+		Name  string
+		Tests int
+	}{{"sample", 1}, {"secret_group1", 2}, {"secret_group2", 1}}
+	for i, expected := range expectedGroups {
+		group := groups[i]
+		if group.Name() != expected.Name {
+			t.Fatalf("Expected %q, got %q", expected.Name, group.Name())
+		}
+		tests, err := group.GetTests()
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if len(tests) != expected.Tests {
+			t.Fatalf("Expected %d tests, got %d", expected.Tests, len(tests))
+		}
+		if group.TimeLimit() != 2000 {
+			t.Fatal("Invalid time limit:", group.TimeLimit())
+		}
+		if group.MemoryLimit() != 64*1024*1024 {
+			t.Fatal("Invalid memory limit:", group.MemoryLimit())
+		}
+	}
+	executables, err := problem.GetExecutables()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(executables) != 0 {
+		t.Fatal("Unexpected executables:", executables)
+	}
+	if checker := problem.(defaultCheckerProblem).GetDefaultChecker(); checker == nil {
+		t.Fatal("Expected default checker")
+	}
+	statements, err := problem.GetStatements()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(statements) != 1 {
+		t.Fatal("Invalid statements count:", len(statements))
+	}
+	config, err := statements[0].GetConfig()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if config.Locale != "en" || config.Title != "Sum" || config.Input != "Input." {
+		t.Fatal("Invalid statement:", config)
+	}
+	if len(config.Samples) != 1 || config.Samples[0].Output != "3\n" {
+		t.Fatal("Invalid samples:", config.Samples)
+	}
+}
+
+func TestICPCProblemUnsupported(t *testing.T) {
+	for _, config := range []string{
+		"name: Guess\nvalidation: custom interactive\n",
+		"name: Sum\nlimits:\n  time_multiplier: 3\n",
+	} {
+		source := filepath.Join(t.TempDir(), "problem.zip")
+		writeTestZip(t, source, map[string]string{
+			"problem.yaml":       config,
+			"data/secret/01.in":  "1 2\n",
+			"data/secret/01.ans": "3\n",
+		})
+		target := filepath.Join(t.TempDir(), "problem")
+		if _, err := extractICPCProblem(source, target, nil); err == nil {
+			t.Fatalf("Expected error for config %q", config)
+		}
+		if _, err := os.Stat(target); !os.IsNotExist(err) {
+			t.Fatal("Expected removed problem:", err)
+		}
+	}
+	source := filepath.Join(t.TempDir(), "problem.zip")
+	writeTestZip(t, source, map[string]string{
+		"problem.yaml":       "name: Sum\nlimits:\n  time_multiplier: 3\n",
+		".timelimit":         "3\n",
+		"data/secret/01.in":  "1 2\n",
+		"data/secret/01.ans": "3\n",
+	})
+	problem, err := extractICPCProblem(source, filepath.Join(t.TempDir(), "problem"), nil)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	groups, err := problem.GetTestGroups()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(groups) != 1 || groups[0].TimeLimit() != 3000 {
+		t.Fatal("Invalid groups:", groups)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/udovin/gosql"
+	"github.com/udovin/solve/config"
 	"github.com/udovin/solve/core"
 	"github.com/udovin/solve/managers"
 	"github.com/udovin/solve/models"
@@ -45,17 +46,12 @@ func New(core *core.Core) *Invoker {
 //
 // This function will spawn config.Invoker.Workers amount of goroutines.
 func (s *Invoker) Start() error {
-	safeexecConfig := s.core.Config.Invoker.Safeexec
-	safeexec, err := newSafeexecProcessor(
-		safeexecConfig.Path,
-		"/tmp/solve-safeexec",
-		"solve-safeexec",
-	)
+	sandbox, err := s.newSandbox()
 	if err != nil {
 		return err
 	}
 	compilers, err := newCompilerManager(
-		s.files, "/tmp/solve-compilers", safeexec, s.core,
+		s.files, "/tmp/solve-compilers", sandbox, s.core,
 	)
 	if err != nil {
 		return err
@@ -76,9 +72,29 @@ func (s *Invoker) Start() error {
 		name := fmt.Sprintf("invoker-%d", i+1)
 		s.core.StartTask(name, s.runDaemon)
 	}
+	if s.files != nil {
+		s.core.StartTask("invoker-files-gc", s.runFilesGC)
+	}
+	s.core.StartTask("invoker-system-tests", s.runSystemTests)
 	return nil
 }
 
+func (s *Invoker) newSandbox() (Sandbox, error) {
+	switch kind := s.core.Config.Invoker.Sandbox; kind {
+	case "", config.SafeexecSandbox:
+		safeexecConfig := s.core.Config.Invoker.Safeexec
+		return newSafeexecProcessor(
+			safeexecConfig.Path,
+			"/tmp/solve-safeexec",
+			"solve-safeexec",
+		)
+	case config.FakeSandbox:
+		return newFakeSandbox("/tmp/solve-fake-sandbox")
+	default:
+		return nil, fmt.Errorf("unsupported sandbox %q", kind)
+	}
+}
+
 func (s *Invoker) runDaemon(ctx context.Context) {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
@@ -98,6 +114,25 @@ func (s *Invoker) runDaemon(ctx context.Context) {
 	}
 }
 
+// runFilesGC periodically deletes expired files like test artifacts.
+func (s *Invoker) runFilesGC(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		deleted, err := s.files.DeleteExpiredFiles(ctx, time.Now())
+		if err != nil {
+			s.core.Logger().Error("Cannot delete expired files", err)
+		} else if deleted > 0 {
+			s.core.Logger().Info("Expired files deleted", logs.Any("count", deleted))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (s *Invoker) runDaemonTick(ctx context.Context) bool {
 	select {
 	case <-ctx.Done():
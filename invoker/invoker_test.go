@@ -18,7 +18,9 @@ func testSetup(tb testing.TB) {
 		DB: config.DB{
 			Options: config.SQLiteOptions{Path: ":memory:"},
 		},
-		Invoker: &config.Invoker{},
+		Invoker: &config.Invoker{
+			Sandbox: config.FakeSandbox,
+		},
 		Security: &config.Security{
 			PasswordSalt: "qwerty123",
 		},
@@ -50,7 +52,9 @@ func testTeardown(tb testing.TB) {
 func TestInvoker_Start(t *testing.T) {
 	testSetup(t)
 	defer testTeardown(t)
-	testInvoker.Start()
+	if err := testInvoker.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
 	// Wait for cache sync.
 	<-time.After(1100 * time.Millisecond)
 }
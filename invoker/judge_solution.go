@@ -1,6 +1,7 @@
 package invoker
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/fs"
@@ -8,6 +9,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/udovin/solve/managers"
 	"github.com/udovin/solve/models"
 	"github.com/udovin/solve/pkg/logs"
 )
@@ -22,11 +24,15 @@ type judgeSolutionTask struct {
 	solution     models.Solution
 	problem      models.Problem
 	compiler     models.Compiler
+	limits       models.CompilerLimitsConfig
+	pretests     *models.ProblemPretestsConfig
+	timeLimits   map[string]int64
 	tempDir      string
 	problemImpl  Problem
 	compilerImpl Compiler
 	solutionPath string
 	compiledPath string
+	artifacts    []models.File
 }
 
 func (judgeSolutionTask) New(invoker *Invoker) taskImpl {
@@ -46,22 +52,106 @@ func (t *judgeSolutionTask) Execute(ctx TaskContext) error {
 	if err != nil {
 		return fmt.Errorf("unable to fetch problem: %w", err)
 	}
+	if t.config.RevisionID != 0 {
+		if err := t.invoker.core.ProblemRevisions.Sync(ctx); err != nil {
+			return fmt.Errorf("unable to sync revisions: %w", err)
+		}
+		revision, err := t.invoker.core.ProblemRevisions.Get(t.config.RevisionID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch revision: %w", err)
+		}
+		if revision.ProblemID != problem.ID {
+			return fmt.Errorf("revision %d does not belong to problem %d", revision.ID, problem.ID)
+		}
+		if revision.CompiledID == 0 {
+			return fmt.Errorf("revision %d is not compiled", revision.ID)
+		}
+		revisionConfig, err := revision.GetConfig()
+		if err != nil {
+			return fmt.Errorf("unable to get revision config: %w", err)
+		}
+		problemConfig, err := problem.GetConfig()
+		if err != nil {
+			return fmt.Errorf("unable to get problem config: %w", err)
+		}
+		// Pinned revision is judged with its own limits and pretests.
+		if err := problem.SetConfig(
+			revisionConfig.GetProblemConfig(problemConfig),
+		); err != nil {
+			return err
+		}
+		problem.PackageID = models.NInt64(revision.PackageID)
+		problem.CompiledID = revision.CompiledID
+	}
 	compiler, err := t.invoker.core.Compilers.Get(solution.CompilerID)
 	if err != nil {
 		return fmt.Errorf("unable to fetch compiler: %w", err)
 	}
+	limits, err := t.getCompilerLimits(ctx, solution, problem, compiler)
+	if err != nil {
+		return fmt.Errorf("unable to get compiler limits: %w", err)
+	}
 	tempDir, err := makeTempDir()
 	if err != nil {
 		return err
 	}
 	defer func() { _ = os.RemoveAll(tempDir) }()
 	t.tempDir = tempDir
+	t.limits = limits
+	problemConfig, err := problem.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get problem config: %w", err)
+	}
+	if t.config.Pretests {
+		// Problems without pretests are judged on all tests.
+		t.pretests = problemConfig.Pretests
+	}
+	t.timeLimits = problemConfig.TimeLimitOverrides
 	t.solution = solution
 	t.problem = problem
 	t.compiler = compiler
 	return t.executeImpl(ctx)
 }
 
+// getCompilerLimits returns limits config for solution compiler with
+// respect to problem and contest overrides.
+func (t *judgeSolutionTask) getCompilerLimits(
+	ctx TaskContext, solution models.Solution,
+	problem models.Problem, compiler models.Compiler,
+) (models.CompilerLimitsConfig, error) {
+	compilerConfig, err := compiler.GetConfig()
+	if err != nil {
+		return models.CompilerLimitsConfig{}, err
+	}
+	problemConfig, err := problem.GetConfig()
+	if err != nil {
+		return models.CompilerLimitsConfig{}, err
+	}
+	overrides := []models.CompilerLimitsOverrides{problemConfig.CompilerLimits}
+	if err := t.invoker.core.ContestSolutions.Sync(ctx); err != nil {
+		return models.CompilerLimitsConfig{}, err
+	}
+	contestSolutions, err := t.invoker.core.ContestSolutions.FindBySolution(solution.ID)
+	if err != nil {
+		return models.CompilerLimitsConfig{}, err
+	}
+	if len(contestSolutions) > 0 {
+		if err := t.invoker.core.Contests.Sync(ctx); err != nil {
+			return models.CompilerLimitsConfig{}, err
+		}
+		contest, err := t.invoker.core.Contests.Get(contestSolutions[0].ContestID)
+		if err != nil {
+			return models.CompilerLimitsConfig{}, err
+		}
+		contestConfig, err := contest.GetConfig()
+		if err != nil {
+			return models.CompilerLimitsConfig{}, err
+		}
+		overrides = append(overrides, contestConfig.CompilerLimits)
+	}
+	return models.GetCompilerLimits(compiler.Name, compilerConfig, overrides...), nil
+}
+
 func (t *judgeSolutionTask) prepareProblem(ctx TaskContext) error {
 	if t.problem.PackageID == 0 {
 		return fmt.Errorf("problem does not have package")
@@ -151,40 +241,10 @@ func (t *judgeSolutionTask) testSolution(
 	if err := ctx.SetState(ctx, state); err != nil {
 		return err
 	}
-	executables, err := t.problemImpl.GetExecutables()
-	if err != nil {
-		return fmt.Errorf("cannot get executables: %w", err)
-	}
-	var checker ProblemExecutable
-	for _, executable := range executables {
-		if executable.Kind() == TestlibChecker {
-			checker = executable
-			break
-		}
-	}
-	if checker == nil {
-		return fmt.Errorf("cannot find checker executable")
-	}
-	checkerCompiler, err := t.invoker.compilers.GetCompiler(ctx, checker.Compiler())
+	checker, err := t.prepareChecker(ctx)
 	if err != nil {
 		return err
 	}
-	checkerPath := filepath.Join(t.tempDir, "checker")
-	if err := func() error {
-		testFile, err := checker.OpenBinary()
-		if err != nil {
-			return err
-		}
-		file, err := os.OpenFile(checkerPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
-		if err != nil {
-			return err
-		}
-		defer func() { _ = file.Close() }()
-		_, err = io.Copy(file, testFile)
-		return err
-	}(); err != nil {
-		return err
-	}
 	groups, err := t.problemImpl.GetTestGroups()
 	if err != nil {
 		return err
@@ -195,10 +255,17 @@ func (t *judgeSolutionTask) testSolution(
 		if err != nil {
 			return err
 		}
+		timeLimit := t.limits.TimeLimit(t.getGroupTimeLimit(group))
+		memoryLimit := t.limits.MemoryLimit(group.MemoryLimit())
 		for _, test := range tests {
+			if t.pretests != nil && !t.pretests.IsPretest(group.Name(), testNumber) {
+				testNumber++
+				continue
+			}
 			testNumber++
 			inputPath := filepath.Join(t.tempDir, "test.in")
 			outputPath := filepath.Join(t.tempDir, "test.out")
+			stderrPath := filepath.Join(t.tempDir, "test.err")
 			answerPath := filepath.Join(t.tempDir, "test.ans")
 			if err := func() error {
 				testFile, err := test.OpenInput()
@@ -237,9 +304,10 @@ func (t *judgeSolutionTask) testSolution(
 				},
 				OutputFiles: []MountFile{
 					{Source: outputPath, Target: "stdout"},
+					{Source: stderrPath, Target: "stderr"},
 				},
-				TimeLimit:   time.Duration(group.TimeLimit()) * time.Millisecond,
-				MemoryLimit: group.MemoryLimit(),
+				TimeLimit:   time.Duration(timeLimit) * time.Millisecond,
+				MemoryLimit: memoryLimit,
 			})
 			if err != nil {
 				return fmt.Errorf("cannot execute solution: %w", err)
@@ -253,67 +321,32 @@ func (t *judgeSolutionTask) testSolution(
 				return err
 			}
 			testReport := models.TestReport{
-				Verdict: models.Rejected,
-				Input:   input,
-				Output:  output,
-				Usage: models.UsageReport{
-					Time:   executeReport.UsedTime.Milliseconds(),
-					Memory: executeReport.UsedMemory,
-				},
+				Verdict:     models.Rejected,
+				Input:       input,
+				Output:      output,
+				Usage:       executeReport.Usage(),
+				TimeLimit:   timeLimit,
+				MemoryLimit: memoryLimit,
 			}
-			if executeReport.UsedTime.Milliseconds() > group.TimeLimit() {
-				testReport.Verdict = models.TimeLimitExceeded
-			} else if executeReport.UsedMemory > group.MemoryLimit() {
-				testReport.Verdict = models.MemoryLimitExceeded
-			} else if !executeReport.Success() {
-				testReport.Verdict = models.RuntimeError
+			// Log of previous test should not be uploaded as artifact
+			// when checker is not executed.
+			_ = os.Remove(checker.LogPath())
+			if verdict := getExecuteVerdict(
+				executeReport, timeLimit, memoryLimit,
+			); verdict != models.Accepted {
+				testReport.Verdict = verdict
 			} else {
-				checkerLogPath := filepath.Join(t.tempDir, "checker.log")
-				checkerReport, err := checkerCompiler.Execute(ctx, ExecuteOptions{
-					Binary: checkerPath,
-					Args:   []string{"input.in", "output.out", "answer.ans"},
-					InputFiles: []MountFile{
-						{Source: inputPath, Target: "input.in"},
-						{Source: outputPath, Target: "output.out"},
-						{Source: answerPath, Target: "answer.ans"},
-					},
-					OutputFiles: []MountFile{
-						{Source: checkerLogPath, Target: "stderr"},
-					},
-					TimeLimit:   20 * time.Second,
-					MemoryLimit: 256 * 1024 * 1024,
-				})
+				verdict, checkReport, err := checker.Check(ctx, inputPath, outputPath, answerPath)
 				if err != nil {
 					return fmt.Errorf("cannot check solution: %w", err)
 				}
-				switch checkerReport.ExitCode {
-				case 0:
-					testReport.Verdict = models.Accepted
-				case 1:
-					testReport.Verdict = models.WrongAnswer
-				case 3:
-					testReport.Verdict = models.Failed
-				case 2, 4, 8:
-					testReport.Verdict = models.PresentationError
-				case 5:
-					testReport.Verdict = models.PartiallyAccepted
-				default:
-					if checkerReport.ExitCode < 16 {
-						return fmt.Errorf("checker exited with code: %d", checkerReport.ExitCode)
-					}
-					testReport.Verdict = models.PartiallyAccepted
-				}
-				checkerLog, err := readFile(checkerLogPath, 256)
-				if err != nil {
-					return err
-				}
-				testReport.Check = models.CheckReport{
-					Log: checkerLog,
-					Usage: models.UsageReport{
-						Time:   checkerReport.UsedTime.Milliseconds(),
-						Memory: checkerReport.UsedMemory,
-					},
-				}
+				testReport.Verdict = verdict
+				testReport.Check = checkReport
+			}
+			if t.shouldStoreArtifacts(testReport.Verdict) {
+				testReport.Artifacts = t.uploadArtifacts(
+					ctx, outputPath, stderrPath, checker.LogPath(),
+				)
 			}
 			report.Tests = append(report.Tests, testReport)
 			if report.Usage.Time < testReport.Usage.Time {
@@ -337,6 +370,241 @@ func (t *judgeSolutionTask) testSolution(
 	return nil
 }
 
+const defaultArtifactsRetention = 7 * 24 * time.Hour
+
+// shouldStoreArtifacts returns true if full artifacts of test with
+// specified verdict should be stored.
+func (t *judgeSolutionTask) shouldStoreArtifacts(verdict models.Verdict) bool {
+	if t.invoker.files == nil {
+		return false
+	}
+	config := t.invoker.core.Config.Invoker.Artifacts
+	if config == nil {
+		return verdict != models.Accepted
+	}
+	return !config.Disabled && (config.AllTests || verdict != models.Accepted)
+}
+
+// getGroupTimeLimit returns time limit of test group with respect
+// to problem overrides.
+func (t *judgeSolutionTask) getGroupTimeLimit(group ProblemTestGroup) int64 {
+	if timeLimit, ok := t.timeLimits[group.Name()]; ok && timeLimit > 0 {
+		return timeLimit
+	}
+	return group.TimeLimit()
+}
+
+// getArtifactsRetention returns retention period of test artifacts.
+func (t *judgeSolutionTask) getArtifactsRetention() time.Duration {
+	config := t.invoker.core.Config.Invoker.Artifacts
+	if config == nil || config.Retention <= 0 {
+		return defaultArtifactsRetention
+	}
+	return time.Duration(config.Retention) * time.Second
+}
+
+// uploadArtifacts uploads full output, stderr and checker log of test.
+//
+// Artifacts are not required for judging, so failed uploads are only
+// logged.
+func (t *judgeSolutionTask) uploadArtifacts(
+	ctx TaskContext, outputPath, stderrPath, checkLogPath string,
+) *models.TestArtifacts {
+	upload := func(path, name string) int64 {
+		file, err := os.Open(path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				ctx.Logger().Warn("Cannot open artifact", err)
+			}
+			return 0
+		}
+		uploaded, err := t.invoker.files.UploadFile(ctx, &managers.FileReader{
+			Name:   name,
+			Reader: file,
+		})
+		if err != nil {
+			ctx.Logger().Warn("Cannot upload artifact", err)
+			return 0
+		}
+		t.artifacts = append(t.artifacts, uploaded)
+		return uploaded.ID
+	}
+	artifacts := models.TestArtifacts{
+		OutputID:   upload(outputPath, "output.txt"),
+		StderrID:   upload(stderrPath, "stderr.txt"),
+		CheckLogID: upload(checkLogPath, "check.log"),
+	}
+	if len(artifacts.FileIDs()) == 0 {
+		return nil
+	}
+	return &artifacts
+}
+
+// getExecuteVerdict returns verdict of solution execution.
+//
+// Accepted verdict means that solution output should be checked.
+func getExecuteVerdict(
+	report ExecuteReport, timeLimit, memoryLimit int64,
+) models.Verdict {
+	switch {
+	case report.UsedTime.Milliseconds() > timeLimit:
+		return models.TimeLimitExceeded
+	case report.OOMKilled || report.UsedMemory > memoryLimit:
+		return models.MemoryLimitExceeded
+	case report.Violation():
+		return models.SecurityViolation
+	case !report.Success():
+		return models.RuntimeError
+	default:
+		return models.Accepted
+	}
+}
+
+// solutionChecker represents checker of solution output.
+type solutionChecker struct {
+	tempDir        string
+	executable     ProblemExecutable
+	compiler       Compiler
+	binaryPath     string
+	defaultChecker *DefaultCheckerOptions
+}
+
+func (t *judgeSolutionTask) prepareChecker(ctx TaskContext) (*solutionChecker, error) {
+	checker := solutionChecker{tempDir: t.tempDir}
+	if p, ok := t.problemImpl.(defaultCheckerProblem); ok {
+		checker.defaultChecker = p.GetDefaultChecker()
+	}
+	executables, err := t.problemImpl.GetExecutables()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get executables: %w", err)
+	}
+	for _, executable := range executables {
+		kind := executable.Kind()
+		if kind == TestlibChecker || kind == ICPCOutputValidator {
+			checker.executable = executable
+			break
+		}
+	}
+	if checker.executable == nil {
+		if checker.defaultChecker == nil {
+			return nil, fmt.Errorf("cannot find checker executable")
+		}
+		return &checker, nil
+	}
+	compiler, err := t.invoker.compilers.GetCompiler(ctx, checker.executable.Compiler())
+	if err != nil {
+		return nil, err
+	}
+	checker.compiler = compiler
+	checker.binaryPath = filepath.Join(t.tempDir, "checker")
+	if err := func() error {
+		testFile, err := checker.executable.OpenBinary()
+		if err != nil {
+			return err
+		}
+		defer func() { _ = testFile.Close() }()
+		file, err := os.OpenFile(checker.binaryPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, os.ModePerm)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		_, err = io.Copy(file, testFile)
+		return err
+	}(); err != nil {
+		return nil, err
+	}
+	return &checker, nil
+}
+
+// LogPath returns path to full log of last check.
+func (c *solutionChecker) LogPath() string {
+	return filepath.Join(c.tempDir, "checker.log")
+}
+
+// Check checks solution output and returns verdict.
+func (c *solutionChecker) Check(
+	ctx context.Context, inputPath, outputPath, answerPath string,
+) (models.Verdict, models.CheckReport, error) {
+	checkerLogPath := c.LogPath()
+	if c.executable == nil {
+		verdict, log, err := runDefaultChecker(*c.defaultChecker, outputPath, answerPath)
+		if err == nil {
+			err = os.WriteFile(checkerLogPath, []byte(log), 0644)
+		}
+		return verdict, models.CheckReport{Log: log}, err
+	}
+	options := ExecuteOptions{
+		Binary: c.binaryPath,
+		OutputFiles: []MountFile{
+			{Source: checkerLogPath, Target: "stderr"},
+		},
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	}
+	switch c.executable.Kind() {
+	case ICPCOutputValidator:
+		// Output validator reads solution output from stdin and
+		// writes feedback files into specified directory.
+		options.Args = []string{"input.in", "answer.ans", "."}
+		options.InputFiles = []MountFile{
+			{Source: inputPath, Target: "input.in"},
+			{Source: answerPath, Target: "answer.ans"},
+			{Source: outputPath, Target: "stdin"},
+		}
+	default:
+		options.Args = []string{"input.in", "output.out", "answer.ans"}
+		options.InputFiles = []MountFile{
+			{Source: inputPath, Target: "input.in"},
+			{Source: outputPath, Target: "output.out"},
+			{Source: answerPath, Target: "answer.ans"},
+		}
+	}
+	checkerReport, err := c.compiler.Execute(ctx, options)
+	if err != nil {
+		return 0, models.CheckReport{}, err
+	}
+	var verdict models.Verdict
+	switch c.executable.Kind() {
+	case ICPCOutputValidator:
+		switch checkerReport.ExitCode {
+		case 42:
+			verdict = models.Accepted
+		case 43:
+			verdict = models.WrongAnswer
+		default:
+			verdict = models.Failed
+		}
+	default:
+		switch checkerReport.ExitCode {
+		case 0:
+			verdict = models.Accepted
+		case 1:
+			verdict = models.WrongAnswer
+		case 3:
+			verdict = models.Failed
+		case 2, 4, 8:
+			verdict = models.PresentationError
+		case 5:
+			verdict = models.PartiallyAccepted
+		default:
+			if checkerReport.ExitCode < 16 {
+				return 0, models.CheckReport{}, fmt.Errorf(
+					"checker exited with code: %d", checkerReport.ExitCode,
+				)
+			}
+			verdict = models.PartiallyAccepted
+		}
+	}
+	checkerLog, err := readFile(checkerLogPath, 256)
+	if err != nil {
+		return 0, models.CheckReport{}, err
+	}
+	return verdict, models.CheckReport{
+		Log:   checkerLog,
+		Usage: checkerReport.Usage(),
+	}, nil
+}
+
 func (t *judgeSolutionTask) executeImpl(ctx TaskContext) error {
 	if err := t.prepareProblem(ctx); err != nil {
 		return fmt.Errorf("cannot prepare problem: %w", err)
@@ -348,7 +616,8 @@ func (t *judgeSolutionTask) executeImpl(ctx TaskContext) error {
 		return fmt.Errorf("cannot prepare solution: %w", err)
 	}
 	report := models.SolutionReport{
-		Verdict: models.Rejected,
+		Verdict:  models.Rejected,
+		Pretests: t.pretests != nil,
 	}
 	if ok, err := t.compileSolution(ctx, &report); err != nil {
 		return fmt.Errorf("cannot compile solution: %w", err)
@@ -359,8 +628,50 @@ func (t *judgeSolutionTask) executeImpl(ctx TaskContext) error {
 			return fmt.Errorf("cannot judge solution: %w", err)
 		}
 	}
+	expireTime := time.Now().Add(t.getArtifactsRetention())
+	if len(t.artifacts) > 0 {
+		report.ArtifactsExpireTime = expireTime.Unix()
+	}
 	if err := t.solution.SetReport(&report); err != nil {
 		return err
 	}
-	return t.invoker.core.Solutions.Update(ctx, t.solution)
+	return t.invoker.core.WrapTx(ctx, func(ctx context.Context) error {
+		for i := range t.artifacts {
+			if err := t.invoker.files.ConfirmUploadTemporaryFile(
+				ctx, &t.artifacts[i], expireTime,
+			); err != nil {
+				return err
+			}
+		}
+		if err := t.invoker.core.Solutions.Update(ctx, t.solution); err != nil {
+			return err
+		}
+		return t.notifyContestParticipants(ctx)
+	})
+}
+
+// notifyContestParticipants creates notifications about finished verdict
+// for participants of contests that contain solution.
+func (t *judgeSolutionTask) notifyContestParticipants(ctx context.Context) error {
+	contestSolutions, err := t.invoker.core.ContestSolutions.FindBySolution(
+		t.solution.ID,
+	)
+	if err != nil {
+		return err
+	}
+	for _, contestSolution := range contestSolutions {
+		notification := models.ContestNotification{
+			ContestID:     contestSolution.ContestID,
+			ParticipantID: models.NInt64(contestSolution.ParticipantID),
+			Kind:          models.SolutionNotification,
+			TargetID:      contestSolution.ID,
+			CreateTime:    time.Now().Unix(),
+		}
+		if err := t.invoker.core.ContestNotifications.Create(
+			ctx, &notification,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
 }
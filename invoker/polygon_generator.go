@@ -0,0 +1,385 @@
+package invoker
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/pkg/logs"
+	"github.com/udovin/solve/pkg/polygon"
+)
+
+// polygonGeneration represents single run of Polygon generator.
+type polygonGeneration struct {
+	cmd string
+	// files contains names of files created by generator for tests.
+	//
+	// Empty file name means that generator prints test to stdout.
+	files map[int]string
+}
+
+func (g polygonGeneration) tests() []int {
+	var tests []int
+	for test := range g.files {
+		tests = append(tests, test)
+	}
+	sort.Ints(tests)
+	return tests
+}
+
+// cacheKey returns key that identifies generated test.
+func (g polygonGeneration) cacheKey(test int) string {
+	if file := g.files[test]; file != "" {
+		return g.cmd + " > {" + file + "}"
+	}
+	return g.cmd
+}
+
+// getPolygonGenerations returns generator runs required for testset.
+//
+// Tests with the same command and "from-file" attribute are generated
+// by single generator run. Tests that are not manual and do not have
+// command are generated according to script.
+func getPolygonGenerations(testSet polygon.TestSet) ([]polygonGeneration, error) {
+	var generations []polygonGeneration
+	multiTests := map[string]int{}
+	defined := map[int]struct{}{}
+	for i, test := range testSet.Tests {
+		if test.Method == "manual" {
+			defined[i+1] = struct{}{}
+		}
+		if test.Cmd == "" {
+			continue
+		}
+		defined[i+1] = struct{}{}
+		if test.FromFile == "" {
+			generations = append(generations, polygonGeneration{
+				cmd:   test.Cmd,
+				files: map[int]string{i + 1: ""},
+			})
+			continue
+		}
+		if pos, ok := multiTests[test.Cmd]; ok {
+			generations[pos].files[i+1] = test.FromFile
+			continue
+		}
+		multiTests[test.Cmd] = len(generations)
+		generations = append(generations, polygonGeneration{
+			cmd:   test.Cmd,
+			files: map[int]string{i + 1: test.FromFile},
+		})
+	}
+	if strings.TrimSpace(testSet.Script) == "" {
+		return generations, nil
+	}
+	commands, err := polygon.ParseScript(
+		testSet.Script, getPolygonManualTests(testSet)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse script of testset %q: %w", testSet.Name, err)
+	}
+	for _, command := range commands {
+		generation := polygonGeneration{
+			cmd:   command.Cmd(),
+			files: map[int]string{},
+		}
+		for i, test := range command.Tests {
+			if _, ok := defined[test]; ok {
+				continue
+			}
+			if command.MultiTest() {
+				generation.files[test] = strconv.Itoa(i + 1)
+			} else {
+				generation.files[test] = ""
+			}
+		}
+		if len(generation.files) > 0 {
+			generations = append(generations, generation)
+		}
+	}
+	return generations, nil
+}
+
+// getPolygonManualTests returns indices of manual tests in testset.
+func getPolygonManualTests(testSet polygon.TestSet) []int {
+	var tests []int
+	for i, test := range testSet.Tests {
+		if test.Method == "manual" {
+			tests = append(tests, i+1)
+		}
+	}
+	return tests
+}
+
+// getPolygonTestCount returns amount of tests in testset.
+func getPolygonTestCount(testSet polygon.TestSet) int {
+	count := max(len(testSet.Tests), testSet.TestCount)
+	if strings.TrimSpace(testSet.Script) == "" {
+		return count
+	}
+	commands, err := polygon.ParseScript(
+		testSet.Script, getPolygonManualTests(testSet)...,
+	)
+	if err != nil {
+		return count
+	}
+	for _, command := range commands {
+		for _, test := range command.Tests {
+			count = max(count, test)
+		}
+	}
+	return count
+}
+
+const polygonGeneratedCacheName = "generated-tests.json"
+
+type polygonGeneratedTest struct {
+	// Cmd contains generator command of test.
+	Cmd string `json:"cmd,omitempty"`
+	// InputMD5 contains hash of input for which answer was generated.
+	InputMD5 string `json:"input_md5,omitempty"`
+}
+
+// polygonGeneratedCache represents cache of generated tests of package.
+type polygonGeneratedCache struct {
+	Tests map[string]polygonGeneratedTest `json:"tests"`
+}
+
+func readPolygonGeneratedCache(path string) (*polygonGeneratedCache, error) {
+	cache := polygonGeneratedCache{
+		Tests: map[string]polygonGeneratedTest{},
+	}
+	data, err := os.ReadFile(filepath.Join(path, polygonGeneratedCacheName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &cache, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	if cache.Tests == nil {
+		cache.Tests = map[string]polygonGeneratedTest{}
+	}
+	return &cache, nil
+}
+
+func (c *polygonGeneratedCache) Write(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(
+		filepath.Join(path, polygonGeneratedCacheName), data, os.ModePerm,
+	)
+}
+
+func (p *polygonProblem) generateTests(
+	ctx context.Context, testSet polygon.TestSet, solution compiled,
+	cache *polygonGeneratedCache,
+) error {
+	generations, err := getPolygonGenerations(testSet)
+	if err != nil {
+		return err
+	}
+	for _, generation := range generations {
+		if p.isGenerationCached(testSet, generation, cache) {
+			continue
+		}
+		if err := p.runGenerator(ctx, testSet, generation); err != nil {
+			return err
+		}
+		for test := range generation.files {
+			input := fmt.Sprintf(testSet.InputPathPattern, test)
+			cache.Tests[input] = polygonGeneratedTest{
+				Cmd: generation.cacheKey(test),
+			}
+		}
+	}
+	for i := 1; i <= getPolygonTestCount(testSet); i++ {
+		input := fmt.Sprintf(testSet.InputPathPattern, i)
+		answer := fmt.Sprintf(testSet.AnswerPathPattern, i)
+		inputPath := filepath.Join(p.path, input)
+		answerPath := filepath.Join(p.path, answer)
+		inputMD5, err := getFileMD5(inputPath)
+		if err != nil {
+			return fmt.Errorf(
+				"cannot read input of test %d of testset %q: %w",
+				i, testSet.Name, err,
+			)
+		}
+		cached := cache.Tests[input]
+		if cached.InputMD5 == inputMD5 {
+			if _, err := os.Stat(answerPath); err == nil {
+				continue
+			}
+		}
+		report, err := solution.compiler.Execute(ctx, ExecuteOptions{
+			Binary: solution.path,
+			InputFiles: []MountFile{
+				{Source: inputPath, Target: stdinFile},
+			},
+			OutputFiles: []MountFile{
+				{Source: answerPath, Target: stdoutFile},
+			},
+			TimeLimit:   time.Duration(testSet.TimeLimit) * time.Millisecond,
+			MemoryLimit: testSet.MemoryLimit,
+		})
+		if err != nil {
+			return fmt.Errorf("cannot execute solution: %w", err)
+		}
+		if !report.Success() {
+			return fmt.Errorf(
+				"solution exited with code %v on test %d of testset %q",
+				report.ExitCode, i, testSet.Name,
+			)
+		}
+		cached.InputMD5 = inputMD5
+		cache.Tests[input] = cached
+		p.compilers.logger.Debug(
+			"Generated test",
+			logs.Any("input", input),
+			logs.Any("answer", answer),
+		)
+	}
+	return nil
+}
+
+func (p *polygonProblem) isGenerationCached(
+	testSet polygon.TestSet, generation polygonGeneration,
+	cache *polygonGeneratedCache,
+) bool {
+	for test := range generation.files {
+		input := fmt.Sprintf(testSet.InputPathPattern, test)
+		if cache.Tests[input].Cmd != generation.cacheKey(test) {
+			return false
+		}
+		if _, err := os.Stat(filepath.Join(p.path, input)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *polygonProblem) runGenerator(
+	ctx context.Context, testSet polygon.TestSet, generation polygonGeneration,
+) error {
+	tests := generation.tests()
+	args := strings.Fields(generation.cmd)
+	if len(args) == 0 {
+		return fmt.Errorf(
+			"empty generator command for tests %v of testset %q",
+			tests, testSet.Name,
+		)
+	}
+	executable, ok := p.executables[fmt.Sprintf("files/%s", args[0])]
+	if !ok {
+		return fmt.Errorf(
+			"cannot find generator %q for tests %v of testset %q",
+			args[0], tests, testSet.Name,
+		)
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	stderrPath := filepath.Join(tempDir, "stderr")
+	outputDir := filepath.Join(tempDir, "output")
+	options := ExecuteOptions{
+		Binary: executable.path,
+		Args:   args[1:],
+		OutputFiles: []MountFile{
+			{Source: stderrPath, Target: stderrFile},
+		},
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	}
+	multiTest := true
+	if len(tests) == 1 && generation.files[tests[0]] == "" {
+		input := fmt.Sprintf(testSet.InputPathPattern, tests[0])
+		options.OutputFiles = append(options.OutputFiles, MountFile{
+			Source: filepath.Join(p.path, input), Target: stdoutFile,
+		})
+		multiTest = false
+	} else {
+		options.OutputDir = outputDir
+	}
+	report, err := executable.compiler.Execute(ctx, options)
+	if err != nil {
+		return fmt.Errorf(
+			"cannot execute generator %q for tests %v of testset %q: %w",
+			generation.cmd, tests, testSet.Name, err,
+		)
+	}
+	if !report.Success() {
+		return fmt.Errorf(
+			"generator %q failed for tests %v of testset %q with exit code %d: %q",
+			generation.cmd, tests, testSet.Name, report.ExitCode,
+			readFileTail(stderrPath, 1024),
+		)
+	}
+	if !multiTest {
+		return nil
+	}
+	for _, test := range tests {
+		name := generation.files[test]
+		input := fmt.Sprintf(testSet.InputPathPattern, test)
+		copied := false
+		// Generators may create files with leading zeroes.
+		for _, prefix := range []string{"", "0", "00"} {
+			source := filepath.Join(outputDir, prefix+name)
+			if _, err := os.Stat(source); err != nil {
+				continue
+			}
+			if err := copyFileRec(source, filepath.Join(p.path, input)); err != nil {
+				return err
+			}
+			copied = true
+			break
+		}
+		if !copied {
+			return fmt.Errorf(
+				"generator %q did not create file %q for test %d of testset %q",
+				generation.cmd, name, test, testSet.Name,
+			)
+		}
+	}
+	return nil
+}
+
+func getFileMD5(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// readFileTail returns at most limit last bytes of file.
+func readFileTail(path string, limit int) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if len(data) > limit {
+		data = data[len(data)-limit:]
+	}
+	return strings.TrimSpace(string(data))
+}
@@ -0,0 +1,153 @@
+package invoker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/udovin/solve/models"
+	"github.com/udovin/solve/pkg/logs"
+	"github.com/udovin/solve/pkg/polygon"
+)
+
+func TestPolygonGenerations(t *testing.T) {
+	testSet := polygon.TestSet{
+		Name: "tests",
+		Tests: []polygon.Test{
+			{Method: "manual"},
+			{Method: "generated", Cmd: "gen 1"},
+			{Method: "generated", Cmd: "multigen 2", FromFile: "1"},
+			{Method: "generated", Cmd: "multigen 2", FromFile: "2"},
+			{Method: "manual"},
+		},
+		// Test 5 is manual, so "$" should skip it.
+		Script: "gen 1 > 2\nmultigen 2 > {3-4}\ngen 5 > $\nmultigen 3 > {7-8}\n",
+	}
+	generations, err := getPolygonGenerations(testSet)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	expected := []polygonGeneration{
+		{cmd: "gen 1", files: map[int]string{2: ""}},
+		{cmd: "multigen 2", files: map[int]string{3: "1", 4: "2"}},
+		{cmd: "gen 5", files: map[int]string{6: ""}},
+		{cmd: "multigen 3", files: map[int]string{7: "1", 8: "2"}},
+	}
+	if !reflect.DeepEqual(generations, expected) {
+		t.Fatalf("Expected %v, got %v", expected, generations)
+	}
+	if count := getPolygonTestCount(testSet); count != 8 {
+		t.Fatalf("Expected %d tests, got %d", 8, count)
+	}
+	// Script can not override manual test.
+	testSet.Script = "gen 5 > 1\n"
+	if _, err := getPolygonGenerations(testSet); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestPolygonGenerateTests(t *testing.T) {
+	sandbox, err := newFakeSandbox(t.TempDir())
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	binary := "binary.sh"
+	impl := &compiler{
+		sandbox: sandbox,
+		name:    "sh",
+		config: models.CompilerConfig{
+			Execute: &models.CompilerCommandConfig{
+				Command: "sh binary.sh",
+				Binary:  &binary,
+			},
+		},
+	}
+	problemDir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(problemDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	writeFile("files/gen", `echo "$1"`)
+	writeFile("files/multigen", `i=1; while [ $i -le $1 ]; do echo "$2 $i" > 0$i; i=$((i+1)); done`)
+	writeFile("files/fail", `echo "bad args" >&2; exit 1`)
+	writeFile("solution", `read a b; echo "$a-$b"`)
+	writeFile("tests/01", "manual 1\n")
+	problem := polygonProblem{
+		path:      problemDir,
+		compilers: &compilerManager{logger: logs.NewLogger()},
+		executables: map[string]compiled{
+			"files/gen":      {path: filepath.Join(problemDir, "files/gen"), compiler: impl},
+			"files/multigen": {path: filepath.Join(problemDir, "files/multigen"), compiler: impl},
+			"files/fail":     {path: filepath.Join(problemDir, "files/fail"), compiler: impl},
+		},
+	}
+	solution := compiled{path: filepath.Join(problemDir, "solution"), compiler: impl}
+	testSet := polygon.TestSet{
+		Name:              "tests",
+		TimeLimit:         1000,
+		MemoryLimit:       1024 * 1024,
+		InputPathPattern:  "tests/%02d",
+		AnswerPathPattern: "tests/%02d.a",
+		Tests: []polygon.Test{
+			{Method: "manual"},
+			{Method: "generated", Cmd: "gen 2"},
+		},
+		Script: "multigen 2 multi > {3-4}\n",
+	}
+	cache, err := readPolygonGeneratedCache(problemDir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := problem.generateTests(
+		context.Background(), testSet, solution, cache,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	expected := map[string]string{
+		"tests/01.a": "manual-1\n",
+		"tests/02":   "2\n",
+		"tests/03":   "multi 1\n",
+		"tests/04.a": "multi-2\n",
+	}
+	for name, content := range expected {
+		data, err := os.ReadFile(filepath.Join(problemDir, name))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if string(data) != content {
+			t.Fatalf("Expected %q, got %q", content, string(data))
+		}
+	}
+	if err := cache.Write(problemDir); err != nil {
+		t.Fatal("Error:", err)
+	}
+	// Generators should not be executed for cached tests.
+	writeFile("files/multigen", "exit 1")
+	cache, err = readPolygonGeneratedCache(problemDir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := problem.generateTests(
+		context.Background(), testSet, solution, cache,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	testSet.Tests = append(testSet.Tests, polygon.Test{Cmd: "fail 5"})
+	testSet.Script = ""
+	err = problem.generateTests(context.Background(), testSet, solution, cache)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+	if !strings.Contains(err.Error(), `generator "fail 5" failed for tests [3]`) ||
+		!strings.Contains(err.Error(), "bad args") {
+		t.Fatal("Unexpected error:", err)
+	}
+}
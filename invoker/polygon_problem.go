@@ -2,10 +2,7 @@ package invoker
 
 import (
 	"context"
-	"crypto/md5"
-	"encoding/hex"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -47,6 +44,8 @@ type polygonProblem struct {
 	config      polygon.Problem
 	compilers   *compilerManager
 	executables map[string]compiled
+	// mainSolution contains main solution compiled by Compile.
+	mainSolution *compiled
 }
 
 func (p *polygonProblem) Compile(ctx context.Context) error {
@@ -191,63 +190,18 @@ func (p *polygonProblem) Compile(ctx context.Context) error {
 			path:     targetPath,
 			compiler: compiler,
 		}
+		p.mainSolution = &solution
+	}
+	cache, err := readPolygonGeneratedCache(p.path)
+	if err != nil {
+		return fmt.Errorf("cannot read generated tests cache: %w", err)
 	}
 	for _, testSet := range p.config.TestSets {
-		for i, test := range testSet.Tests {
-			input := fmt.Sprintf(testSet.InputPathPattern, i+1)
-			answer := fmt.Sprintf(testSet.AnswerPathPattern, i+1)
-			if test.Cmd != "" {
-				args := strings.Fields(test.Cmd)
-				if len(args) == 0 {
-					return fmt.Errorf("cannot find executable")
-				}
-				executable, ok := p.executables[fmt.Sprintf("files/%s", args[0])]
-				if !ok {
-					return fmt.Errorf("cannot find executable: %q", args[0])
-				}
-				report, err := executable.compiler.Execute(ctx, ExecuteOptions{
-					Binary: executable.path,
-					Args:   args[1:],
-					OutputFiles: []MountFile{
-						{Source: filepath.Join(p.path, input), Target: "stdout"},
-					},
-					TimeLimit:   20 * time.Second,
-					MemoryLimit: 256 * 1024 * 1024,
-				})
-				if err != nil {
-					return fmt.Errorf("cannot execute generator %q: %w", args[0], err)
-				}
-				if !report.Success() {
-					return fmt.Errorf("generator exited with code: %v", report.ExitCode)
-				}
-			}
-			{
-				report, err := solution.compiler.Execute(ctx, ExecuteOptions{
-					Binary: solution.path,
-					InputFiles: []MountFile{
-						{Source: filepath.Join(p.path, input), Target: "stdin"},
-					},
-					OutputFiles: []MountFile{
-						{Source: filepath.Join(p.path, answer), Target: "stdout"},
-					},
-					TimeLimit:   time.Duration(testSet.TimeLimit) * time.Millisecond,
-					MemoryLimit: testSet.MemoryLimit,
-				})
-				if err != nil {
-					return fmt.Errorf("cannot execute solution: %w", err)
-				}
-				if !report.Success() {
-					return fmt.Errorf("solution exited with code: %v", report.ExitCode)
-				}
-			}
-			p.compilers.logger.Debug(
-				"Generated test",
-				logs.Any("input", input),
-				logs.Any("answer", answer),
-			)
+		if err := p.generateTests(ctx, testSet, solution, cache); err != nil {
+			return err
 		}
 	}
-	return nil
+	return cache.Write(p.path)
 }
 
 func (p *polygonProblem) GetExecutables() ([]ProblemExecutable, error) {
@@ -323,6 +277,75 @@ func (p *polygonProblem) GetStatements() ([]ProblemStatement, error) {
 	return statements, nil
 }
 
+func (p *polygonProblem) GetGenerator(name string) (compiled, error) {
+	executable, ok := p.executables[fmt.Sprintf("files/%s", name)]
+	if !ok {
+		return compiled{}, fmt.Errorf("cannot find generator %q", name)
+	}
+	return executable, nil
+}
+
+func (p *polygonProblem) GetMainSolution() (compiled, error) {
+	if p.mainSolution == nil {
+		return compiled{}, fmt.Errorf("main solution is not compiled")
+	}
+	return *p.mainSolution, nil
+}
+
+func (p *polygonProblem) GetSolutions() ([]ProblemSolution, error) {
+	var solutions []ProblemSolution
+	if p.config.Assets == nil {
+		return nil, nil
+	}
+	for _, solution := range p.config.Assets.Solutions {
+		if solution.Source == nil {
+			continue
+		}
+		kind := RejectedSolution
+		switch solution.Tag {
+		case "main":
+			kind = MainSolution
+		case "accepted":
+			kind = AcceptedSolution
+		}
+		// Solutions with unknown compiler are still listed, so caller
+		// can report them.
+		compilerName, _ := p.compilers.GetCompilerName(
+			"polygon." + solution.Source.Type,
+		)
+		solutions = append(solutions, problemSolution{
+			name:       solution.Source.Path,
+			kind:       kind,
+			sourcePath: filepath.Join(p.path, solution.Source.Path),
+			compiler:   compilerName,
+		})
+	}
+	return solutions, nil
+}
+
+type problemSolution struct {
+	name       string
+	kind       ProblemSolutionKind
+	sourcePath string
+	compiler   string
+}
+
+func (s problemSolution) Name() string {
+	return s.name
+}
+
+func (s problemSolution) Kind() ProblemSolutionKind {
+	return s.kind
+}
+
+func (s problemSolution) Compiler() string {
+	return s.compiler
+}
+
+func (s problemSolution) OpenSource() (*os.File, error) {
+	return os.Open(s.sourcePath)
+}
+
 type polygonProblemTestGroup struct {
 	problem *polygonProblem
 	config  polygon.TestSet
@@ -342,7 +365,7 @@ func (g *polygonProblemTestGroup) MemoryLimit() int64 {
 
 func (g *polygonProblemTestGroup) GetTests() ([]ProblemTest, error) {
 	var tests []ProblemTest
-	for i := range g.config.Tests {
+	for i := 0; i < getPolygonTestCount(g.config); i++ {
 		input := fmt.Sprintf(g.config.InputPathPattern, i+1)
 		answer := fmt.Sprintf(g.config.AnswerPathPattern, i+1)
 		tests = append(tests, problemTest{
@@ -444,17 +467,7 @@ func (p polygonProblemResource) Name() string {
 }
 
 func (p polygonProblemResource) GetMD5() (string, error) {
-	file, err := os.Open(p.path)
-	if err != nil {
-		return "", err
-	}
-	defer func() { _ = file.Close() }()
-	hash := md5.New()
-	_, err = io.Copy(hash, file)
-	if err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return getFileMD5(p.path)
 }
 
 func (p polygonProblemResource) Open() (*os.File, error) {
@@ -21,7 +21,8 @@ type ProblemTest interface {
 type ProblemExecutableKind string
 
 const (
-	TestlibChecker ProblemExecutableKind = "testlib_checker"
+	TestlibChecker      ProblemExecutableKind = "testlib_checker"
+	ICPCOutputValidator ProblemExecutableKind = "icpc_output_validator"
 )
 
 type ProblemExecutable interface {
@@ -31,6 +32,36 @@ type ProblemExecutable interface {
 	OpenBinary() (*os.File, error)
 }
 
+type ProblemSolutionKind string
+
+const (
+	MainSolution     ProblemSolutionKind = "main"
+	AcceptedSolution ProblemSolutionKind = "accepted"
+	RejectedSolution ProblemSolutionKind = "rejected"
+)
+
+// ProblemSolution represents model solution from problem package.
+type ProblemSolution interface {
+	Name() string
+	Kind() ProblemSolutionKind
+	Compiler() string
+	OpenSource() (*os.File, error)
+}
+
+// solutionsProblem represents problem that contains model solutions.
+type solutionsProblem interface {
+	GetSolutions() ([]ProblemSolution, error)
+}
+
+// stressProblem represents compiled problem that contains generators
+// and reference solution required for stress testing.
+type stressProblem interface {
+	// GetGenerator returns compiled generator with specified name.
+	GetGenerator(name string) (compiled, error)
+	// GetMainSolution returns compiled main solution.
+	GetMainSolution() (compiled, error)
+}
+
 type ProblemTestGroup interface {
 	Name() string
 	TimeLimit() int64
@@ -61,6 +92,7 @@ type ProblemKind string
 
 const (
 	PolygonProblem  ProblemKind = "polygon"
+	ICPCProblem     ProblemKind = "icpc"
 	CompiledProblem ProblemKind = "compiled"
 )
 
@@ -92,7 +124,7 @@ func (m *problemManager) DownloadProblem(
 	ctx context.Context, p models.Problem, kind ProblemKind,
 ) (Problem, error) {
 	switch kind {
-	case PolygonProblem:
+	case PolygonProblem, ICPCProblem:
 		return m.downloadProblemAsync(ctx, int64(p.PackageID), kind).Get(ctx)
 	case CompiledProblem:
 		return m.downloadProblemAsync(ctx, int64(p.CompiledID), kind).Get(ctx)
@@ -156,6 +188,10 @@ func (m *problemManager) runDownloadProblem(
 		return extractPolygonProblem(
 			localProblemPath, problemPath, m.compilers,
 		)
+	case ICPCProblem:
+		return extractICPCProblem(
+			localProblemPath, problemPath, m.compilers,
+		)
 	case CompiledProblem:
 		return extractCompiledProblem(
 			localProblemPath, problemPath, m.compilers,
@@ -6,7 +6,6 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -16,18 +15,6 @@ import (
 	"time"
 )
 
-type safeexecProcessConfig struct {
-	TimeLimit   time.Duration
-	MemoryLimit int64
-	Stdin       io.Reader
-	Stdout      io.Writer
-	Stderr      io.Writer
-	Layers      []string
-	Environ     []string
-	Workdir     string
-	Command     []string
-}
-
 type safeexecProcess struct {
 	name       string
 	path       string
@@ -35,11 +22,7 @@ type safeexecProcess struct {
 	cmd        *exec.Cmd
 }
 
-type safeexecReport struct {
-	Memory   int64
-	Time     time.Duration
-	ExitCode int
-}
+var _ Sandbox = (*safeexecProcessor)(nil)
 
 func (p *safeexecProcess) Start() error {
 	return p.cmd.Start()
@@ -49,41 +32,79 @@ func (p *safeexecProcess) GetUpperDir() string {
 	return filepath.Join(p.path, "upper")
 }
 
-func (p *safeexecProcess) Wait() (safeexecReport, error) {
+func (p *safeexecProcess) Wait() (sandboxReport, error) {
 	if err := p.cmd.Wait(); err != nil {
-		return safeexecReport{}, err
+		return sandboxReport{}, err
 	}
 	file, err := os.Open(filepath.Join(p.path, "report.txt"))
 	if err != nil {
-		return safeexecReport{}, err
+		return sandboxReport{}, err
 	}
-	report := safeexecReport{}
+	report := sandboxReport{}
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
 		line := scanner.Text()
 		parts := strings.SplitN(line, " ", 2)
 		if len(parts) != 2 {
-			return safeexecReport{}, fmt.Errorf("cannot read report")
+			return sandboxReport{}, fmt.Errorf("cannot read report")
 		}
 		switch parts[0] {
 		case "memory":
 			value, err := strconv.ParseInt(parts[1], 10, 64)
 			if err != nil {
-				return safeexecReport{}, fmt.Errorf("cannot parse memory: %w", err)
+				return sandboxReport{}, fmt.Errorf("cannot parse memory: %w", err)
 			}
 			report.Memory = value
 		case "time":
 			value, err := strconv.ParseInt(parts[1], 10, 64)
 			if err != nil {
-				return safeexecReport{}, fmt.Errorf("cannot parse time: %w", err)
+				return sandboxReport{}, fmt.Errorf("cannot parse time: %w", err)
 			}
 			report.Time = time.Duration(value) * time.Millisecond
 		case "exit_code":
 			value, err := strconv.ParseInt(parts[1], 10, 32)
 			if err != nil {
-				return safeexecReport{}, fmt.Errorf("cannot parse exit_code: %w", err)
+				return sandboxReport{}, fmt.Errorf("cannot parse exit_code: %w", err)
 			}
 			report.ExitCode = int(value)
+		case "wall_time":
+			value, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return sandboxReport{}, fmt.Errorf("cannot parse wall_time: %w", err)
+			}
+			report.WallTime = time.Duration(value) * time.Millisecond
+		case "user_time":
+			value, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return sandboxReport{}, fmt.Errorf("cannot parse user_time: %w", err)
+			}
+			report.UserTime = time.Duration(value) * time.Millisecond
+		case "system_time":
+			value, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return sandboxReport{}, fmt.Errorf("cannot parse system_time: %w", err)
+			}
+			report.SystemTime = time.Duration(value) * time.Millisecond
+		case "memory_peak":
+			value, err := strconv.ParseInt(parts[1], 10, 64)
+			if err != nil {
+				return sandboxReport{}, fmt.Errorf("cannot parse memory_peak: %w", err)
+			}
+			report.PeakMemory = value
+		case "signal":
+			value, err := strconv.ParseInt(parts[1], 10, 32)
+			if err != nil {
+				return sandboxReport{}, fmt.Errorf("cannot parse signal: %w", err)
+			}
+			report.Signal = syscall.Signal(value)
+		case "oom_killed":
+			report.OOMKilled = parts[1] == "1"
+		case "pids_limit_exceeded":
+			report.PidsLimitExceeded = parts[1] == "1"
+		case "file_size_limit_exceeded":
+			report.FileSizeLimitExceeded = parts[1] == "1"
+		case "security_violation":
+			report.SecurityViolation = parts[1] == "1"
 		}
 	}
 	return report, nil
@@ -107,7 +128,7 @@ type safeexecProcessor struct {
 	cgroupPath    string
 }
 
-func (m *safeexecProcessor) Create(ctx context.Context, config safeexecProcessConfig) (*safeexecProcess, error) {
+func (m *safeexecProcessor) Create(ctx context.Context, config sandboxProcessConfig) (sandboxProcess, error) {
 	process, err := m.prepareProcess()
 	if err != nil {
 		return nil, err
@@ -115,6 +136,15 @@ func (m *safeexecProcessor) Create(ctx context.Context, config safeexecProcessCo
 	var args []string
 	args = append(args, "--time-limit", fmt.Sprint(config.TimeLimit.Milliseconds()))
 	args = append(args, "--memory-limit", fmt.Sprint(config.MemoryLimit))
+	if config.PidsLimit > 0 {
+		args = append(args, "--pids-limit", fmt.Sprint(config.PidsLimit))
+	}
+	if config.FileSizeLimit > 0 {
+		args = append(args, "--file-size-limit", fmt.Sprint(config.FileSizeLimit))
+	}
+	if len(config.Syscalls) > 0 {
+		args = append(args, "--seccomp-allow", strings.Join(config.Syscalls, ","))
+	}
 	args = append(args, "--overlay-lowerdir", strings.Join(config.Layers, ":"))
 	args = append(args, "--overlay-upperdir", filepath.Join(process.path, "upper"))
 	args = append(args, "--overlay-workdir", filepath.Join(process.path, "workdir"))
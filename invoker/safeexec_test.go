@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -24,7 +25,7 @@ func TestSafeexecSimple(t *testing.T) {
 		t.Fatal("Error:", err)
 	}
 	stdout := strings.Builder{}
-	processConfig := safeexecProcessConfig{
+	processConfig := sandboxProcessConfig{
 		Layers:      []string{alpinePath},
 		Command:     []string{"/bin/sh", "-c", "sleep 1 && echo -n 'solve_test'"},
 		TimeLimit:   2 * time.Second,
@@ -52,6 +53,15 @@ func TestSafeexecSimple(t *testing.T) {
 	if report.Time < time.Second || report.Time > 6*time.Second {
 		t.Fatal("Invalid time:", report.Time.Milliseconds())
 	}
+	if report.WallTime < time.Second {
+		t.Fatal("Invalid wall time:", report.WallTime.Milliseconds())
+	}
+	if report.PeakMemory < report.Memory {
+		t.Fatal("Invalid peak memory:", report.PeakMemory)
+	}
+	if report.Signal != 0 {
+		t.Fatal("Unexpected signal:", report.Signal)
+	}
 	if s := stdout.String(); s != "solve_test" {
 		t.Fatal("Expected:", "solve_test", "got:", s)
 	}
@@ -71,7 +81,7 @@ func TestSafeexecMemoryLimit(t *testing.T) {
 		t.Fatal("Error:", err)
 	}
 	stdout := strings.Builder{}
-	processConfig := safeexecProcessConfig{
+	processConfig := sandboxProcessConfig{
 		Layers:      []string{alpinePath},
 		Command:     []string{"/bin/sh", "-c", "echo -n 'solve_test'"},
 		TimeLimit:   time.Second,
@@ -112,7 +122,7 @@ func TestSafeexecTimeLimit(t *testing.T) {
 		t.Fatal("Error:", err)
 	}
 	stdout := strings.Builder{}
-	processConfig := safeexecProcessConfig{
+	processConfig := sandboxProcessConfig{
 		Layers:      []string{alpinePath},
 		Command:     []string{"/bin/sh", "-c", "sleep 2 && echo -n 'solve_test'"},
 		TimeLimit:   time.Second,
@@ -138,3 +148,161 @@ func TestSafeexecTimeLimit(t *testing.T) {
 		t.Fatal("Invalid time:", report.Time.Milliseconds())
 	}
 }
+
+func TestSafeexecPidsLimit(t *testing.T) {
+	safeexecPath := filepath.Join(t.TempDir(), "safeexec")
+	alpinePath := filepath.Join(t.TempDir(), "alpine")
+	if err := pkg.ExtractTarGz(
+		filepath.Join("../testdata", "alpine.tar.gz"),
+		alpinePath,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	safeexec, err := newSafeexecProcessor("../safeexec/safeexec", safeexecPath, "solve-safeexec")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	processConfig := sandboxProcessConfig{
+		Layers:      []string{alpinePath},
+		Command:     []string{"/bin/sh", "-c", "for i in 1 2 3 4 5 6 7 8; do sleep 1 & done; wait"},
+		TimeLimit:   5 * time.Second,
+		MemoryLimit: 16 * 1024 * 1024,
+		PidsLimit:   4,
+	}
+	process, err := safeexec.Create(context.Background(), processConfig)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !report.PidsLimitExceeded {
+		t.Fatal("Expected pids limit exceeded")
+	}
+}
+
+func TestSafeexecFileSizeLimit(t *testing.T) {
+	safeexecPath := filepath.Join(t.TempDir(), "safeexec")
+	alpinePath := filepath.Join(t.TempDir(), "alpine")
+	if err := pkg.ExtractTarGz(
+		filepath.Join("../testdata", "alpine.tar.gz"),
+		alpinePath,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	safeexec, err := newSafeexecProcessor("../safeexec/safeexec", safeexecPath, "solve-safeexec")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	processConfig := sandboxProcessConfig{
+		Layers:        []string{alpinePath},
+		Command:       []string{"/bin/sh", "-c", "exec yes > /tmp/output"},
+		TimeLimit:     5 * time.Second,
+		MemoryLimit:   16 * 1024 * 1024,
+		FileSizeLimit: 4096,
+	}
+	process, err := safeexec.Create(context.Background(), processConfig)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !report.FileSizeLimitExceeded {
+		t.Fatal("Expected file size limit exceeded")
+	}
+	if report.Signal != syscall.SIGXFSZ {
+		t.Fatal("Expected signal:", syscall.SIGXFSZ, "got:", report.Signal)
+	}
+}
+
+func TestSafeexecSecurityViolation(t *testing.T) {
+	safeexecPath := filepath.Join(t.TempDir(), "safeexec")
+	alpinePath := filepath.Join(t.TempDir(), "alpine")
+	if err := pkg.ExtractTarGz(
+		filepath.Join("../testdata", "alpine.tar.gz"),
+		alpinePath,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	safeexec, err := newSafeexecProcessor("../safeexec/safeexec", safeexecPath, "solve-safeexec")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	processConfig := sandboxProcessConfig{
+		Layers:      []string{alpinePath},
+		Command:     []string{"/bin/true"},
+		TimeLimit:   time.Second,
+		MemoryLimit: 16 * 1024 * 1024,
+		Syscalls:    []string{"exit_group"},
+	}
+	process, err := safeexec.Create(context.Background(), processConfig)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if report.ExitCode == 0 {
+		t.Fatal("Expected non-zero exit code")
+	}
+	if !report.SecurityViolation {
+		t.Fatal("Expected security violation")
+	}
+}
+
+func TestSafeexecSignal(t *testing.T) {
+	safeexecPath := filepath.Join(t.TempDir(), "safeexec")
+	alpinePath := filepath.Join(t.TempDir(), "alpine")
+	if err := pkg.ExtractTarGz(
+		filepath.Join("../testdata", "alpine.tar.gz"),
+		alpinePath,
+	); err != nil {
+		t.Fatal("Error:", err)
+	}
+	safeexec, err := newSafeexecProcessor("../safeexec/safeexec", safeexecPath, "solve-safeexec")
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	processConfig := sandboxProcessConfig{
+		Layers: []string{alpinePath},
+		// Init of PID namespace ignores signals without handlers, so
+		// command should not be run as init.
+		Command:     []string{"/bin/sh", "-c", "[ $$ -ne 1 ] && kill -SEGV $$"},
+		TimeLimit:   time.Second,
+		MemoryLimit: 16 * 1024 * 1024,
+	}
+	process, err := safeexec.Create(context.Background(), processConfig)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	defer func() { _ = process.Release() }()
+	if err := process.Start(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	report, err := process.Wait()
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if report.Signal != syscall.SIGSEGV {
+		t.Fatal("Expected SIGSEGV, got:", report.Signal)
+	}
+	if report.OOMKilled {
+		t.Fatal("Unexpected OOM kill")
+	}
+}
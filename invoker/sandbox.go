@@ -0,0 +1,59 @@
+package invoker
+
+import (
+	"context"
+	"io"
+	"syscall"
+	"time"
+)
+
+type sandboxProcessConfig struct {
+	TimeLimit   time.Duration
+	MemoryLimit int64
+	// PidsLimit limits count of processes and threads (0 means unlimited).
+	PidsLimit int64
+	// FileSizeLimit limits size of each written file (0 means unlimited).
+	FileSizeLimit int64
+	// Syscalls contains seccomp allowlist (empty means disabled seccomp).
+	Syscalls []string
+	Stdin    io.Reader
+	Stdout   io.Writer
+	Stderr   io.Writer
+	Layers   []string
+	Environ  []string
+	Workdir  string
+	Command  []string
+}
+
+type sandboxReport struct {
+	Memory                int64
+	Time                  time.Duration
+	ExitCode              int
+	UserTime              time.Duration
+	SystemTime            time.Duration
+	WallTime              time.Duration
+	PeakMemory            int64
+	Signal                syscall.Signal
+	OOMKilled             bool
+	PidsLimitExceeded     bool
+	FileSizeLimitExceeded bool
+	SecurityViolation     bool
+}
+
+// sandboxProcess represents process that is running inside sandbox.
+type sandboxProcess interface {
+	// Start starts process.
+	Start() error
+	// Wait waits for process completion and returns usage report.
+	Wait() (sandboxReport, error)
+	// Release releases all associated resources with process.
+	Release() error
+	// GetUpperDir returns path to directory with process filesystem changes.
+	GetUpperDir() string
+}
+
+// Sandbox represents backend for running processes in isolated environment.
+type Sandbox interface {
+	// Create creates a new process with specified config.
+	Create(ctx context.Context, config sandboxProcessConfig) (sandboxProcess, error)
+}
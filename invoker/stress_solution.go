@@ -0,0 +1,298 @@
+package invoker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func init() {
+	registerTaskImpl(models.StressSolutionTask, &stressSolutionTask{})
+}
+
+const (
+	defaultStressIterations = 1000
+	defaultStressTimeBudget = 60
+	maxStressTimeBudget     = 600
+	// stressShrinkIterations contains amount of iterations that are
+	// performed after first counterexample to find smaller one.
+	stressShrinkIterations = 100
+	// stressStateInterval contains amount of iterations between
+	// updates of task state.
+	stressStateInterval = 10
+)
+
+type stressSolutionTask struct {
+	invoker   *Invoker
+	config    models.StressSolutionTaskConfig
+	judge     judgeSolutionTask
+	generator compiled
+	reference compiled
+	checker   *solutionChecker
+	binary    string
+}
+
+func (stressSolutionTask) New(invoker *Invoker) taskImpl {
+	return &stressSolutionTask{invoker: invoker}
+}
+
+func (t *stressSolutionTask) Execute(ctx TaskContext) error {
+	if err := ctx.ScanConfig(&t.config); err != nil {
+		return fmt.Errorf("unable to scan task config: %w", err)
+	}
+	if t.config.Iterations <= 0 {
+		t.config.Iterations = defaultStressIterations
+	}
+	if t.config.TimeBudget <= 0 {
+		t.config.TimeBudget = defaultStressTimeBudget
+	}
+	t.config.TimeBudget = min(t.config.TimeBudget, maxStressTimeBudget)
+	solution, err := t.invoker.getSolution(ctx, t.config.SolutionID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch solution: %w", err)
+	}
+	problem, err := t.invoker.core.Problems.Get(solution.ProblemID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch problem: %w", err)
+	}
+	problemConfig, err := problem.GetConfig()
+	if err != nil {
+		return fmt.Errorf("unable to get problem config: %w", err)
+	}
+	compiler, err := t.invoker.core.Compilers.Get(solution.CompilerID)
+	if err != nil {
+		return fmt.Errorf("unable to fetch compiler: %w", err)
+	}
+	t.judge = judgeSolutionTask{
+		invoker:    t.invoker,
+		solution:   solution,
+		problem:    problem,
+		compiler:   compiler,
+		timeLimits: problemConfig.TimeLimitOverrides,
+	}
+	limits, err := t.judge.getCompilerLimits(ctx, solution, problem, compiler)
+	if err != nil {
+		return fmt.Errorf("unable to get compiler limits: %w", err)
+	}
+	tempDir, err := makeTempDir()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	t.judge.limits = limits
+	t.judge.tempDir = tempDir
+	return t.executeImpl(ctx)
+}
+
+func (t *stressSolutionTask) prepareStress(ctx TaskContext) error {
+	args := strings.Fields(t.config.Generator)
+	if len(args) == 0 {
+		return fmt.Errorf("empty generator command")
+	}
+	problem, err := t.invoker.problems.DownloadProblem(ctx, t.judge.problem, PolygonProblem)
+	if err != nil {
+		return fmt.Errorf("cannot download package: %w", err)
+	}
+	p, ok := problem.(stressProblem)
+	if !ok {
+		return fmt.Errorf("package does not support stress testing")
+	}
+	if err := problem.Compile(ctx); err != nil {
+		return fmt.Errorf("cannot compile package: %w", err)
+	}
+	generator, err := p.GetGenerator(args[0])
+	if err != nil {
+		return err
+	}
+	reference, err := p.GetMainSolution()
+	if err != nil {
+		return err
+	}
+	t.generator = generator
+	t.reference = reference
+	return nil
+}
+
+func (t *stressSolutionTask) compileSolution(ctx TaskContext) error {
+	t.binary = filepath.Join(t.judge.tempDir, "candidate")
+	report, err := t.judge.compilerImpl.Compile(ctx, CompileOptions{
+		Source:      t.judge.solutionPath,
+		Target:      t.binary,
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return err
+	}
+	if !report.Success() {
+		return fmt.Errorf("compilation failed: %s", report.Log)
+	}
+	return nil
+}
+
+func (t *stressSolutionTask) executeImpl(ctx TaskContext) error {
+	if err := t.judge.prepareProblem(ctx); err != nil {
+		return fmt.Errorf("cannot prepare problem: %w", err)
+	}
+	if err := t.judge.prepareCompiler(ctx); err != nil {
+		return fmt.Errorf("cannot prepare compiler: %w", err)
+	}
+	if err := t.judge.prepareSolution(ctx); err != nil {
+		return fmt.Errorf("cannot prepare solution: %w", err)
+	}
+	if err := t.compileSolution(ctx); err != nil {
+		return fmt.Errorf("cannot compile solution: %w", err)
+	}
+	if err := t.prepareStress(ctx); err != nil {
+		return fmt.Errorf("cannot prepare stress: %w", err)
+	}
+	checker, err := t.judge.prepareChecker(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot prepare checker: %w", err)
+	}
+	t.checker = checker
+	groups, err := t.judge.problemImpl.GetTestGroups()
+	if err != nil {
+		return fmt.Errorf("cannot get test groups: %w", err)
+	}
+	if len(groups) == 0 {
+		return fmt.Errorf("problem does not have test groups")
+	}
+	// Limits of first group are used for generated tests.
+	group := groups[0]
+	groupTimeLimit := t.judge.getGroupTimeLimit(group)
+	limits := stressLimits{
+		referenceTime:   groupTimeLimit,
+		referenceMemory: group.MemoryLimit(),
+		timeLimit:       t.judge.limits.TimeLimit(groupTimeLimit),
+		memoryLimit:     t.judge.limits.MemoryLimit(group.MemoryLimit()),
+	}
+	state := models.StressSolutionTaskState{}
+	deadline := time.Now().Add(time.Duration(t.config.TimeBudget) * time.Second)
+	iterations := t.config.Iterations
+	for state.Iterations < iterations && time.Now().Before(deadline) {
+		state.Iterations++
+		counterexample, err := t.runIteration(ctx, state.Iterations, limits)
+		if err != nil {
+			return fmt.Errorf("iteration %d failed: %w", state.Iterations, err)
+		}
+		if counterexample != nil {
+			if state.Counterexample == nil {
+				iterations = min(iterations, state.Iterations+stressShrinkIterations)
+			}
+			if state.Counterexample == nil ||
+				counterexample.InputSize < state.Counterexample.InputSize {
+				state.Counterexample = counterexample
+			}
+		}
+		if state.Iterations%stressStateInterval == 0 {
+			if err := ctx.SetState(ctx, state); err != nil {
+				return err
+			}
+		}
+	}
+	return ctx.SetState(ctx, state)
+}
+
+type stressLimits struct {
+	referenceTime   int64
+	referenceMemory int64
+	timeLimit       int64
+	memoryLimit     int64
+}
+
+// runIteration generates test with specified seed and returns
+// counterexample if candidate solution fails on it.
+func (t *stressSolutionTask) runIteration(
+	ctx TaskContext, seed int, limits stressLimits,
+) (*models.StressCounterexample, error) {
+	tempDir := t.judge.tempDir
+	inputPath := filepath.Join(tempDir, "stress.in")
+	outputPath := filepath.Join(tempDir, "stress.out")
+	answerPath := filepath.Join(tempDir, "stress.ans")
+	stderrPath := filepath.Join(tempDir, "stress.err")
+	args := append(strings.Fields(t.config.Generator)[1:], strconv.Itoa(seed))
+	command := t.config.Generator + " " + strconv.Itoa(seed)
+	generatorReport, err := t.generator.compiler.Execute(ctx, ExecuteOptions{
+		Binary: t.generator.path,
+		Args:   args,
+		OutputFiles: []MountFile{
+			{Source: inputPath, Target: stdoutFile},
+			{Source: stderrPath, Target: stderrFile},
+		},
+		TimeLimit:   20 * time.Second,
+		MemoryLimit: 256 * 1024 * 1024,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute generator: %w", err)
+	}
+	if !generatorReport.Success() {
+		return nil, fmt.Errorf(
+			"generator %q failed with exit code %d: %q",
+			command, generatorReport.ExitCode, readFileTail(stderrPath, 1024),
+		)
+	}
+	referenceReport, err := t.reference.compiler.Execute(ctx, ExecuteOptions{
+		Binary:      t.reference.path,
+		InputFiles:  []MountFile{{Source: inputPath, Target: stdinFile}},
+		OutputFiles: []MountFile{{Source: answerPath, Target: stdoutFile}},
+		TimeLimit:   time.Duration(limits.referenceTime) * time.Millisecond,
+		MemoryLimit: limits.referenceMemory,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute reference solution: %w", err)
+	}
+	if verdict := getExecuteVerdict(
+		referenceReport, limits.referenceTime, limits.referenceMemory,
+	); verdict != models.Accepted {
+		return nil, fmt.Errorf(
+			"reference solution failed on %q with verdict %s", command, verdict,
+		)
+	}
+	candidateReport, err := t.judge.compilerImpl.Execute(ctx, ExecuteOptions{
+		Binary:      t.binary,
+		InputFiles:  []MountFile{{Source: inputPath, Target: stdinFile}},
+		OutputFiles: []MountFile{{Source: outputPath, Target: stdoutFile}},
+		TimeLimit:   time.Duration(limits.timeLimit) * time.Millisecond,
+		MemoryLimit: limits.memoryLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot execute solution: %w", err)
+	}
+	counterexample := models.StressCounterexample{Generator: command}
+	counterexample.Verdict = getExecuteVerdict(
+		candidateReport, limits.timeLimit, limits.memoryLimit,
+	)
+	if counterexample.Verdict == models.Accepted {
+		verdict, checkReport, err := t.checker.Check(ctx, inputPath, outputPath, answerPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot check solution: %w", err)
+		}
+		counterexample.Verdict = verdict
+		counterexample.CheckLog = checkReport.Log
+	}
+	if counterexample.Verdict == models.Accepted {
+		return nil, nil
+	}
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	counterexample.InputSize = info.Size()
+	if counterexample.Input, err = readFile(inputPath, 4096); err != nil {
+		return nil, err
+	}
+	if counterexample.Output, err = readFile(outputPath, 4096); err != nil {
+		// Solution could fail before creating output.
+		counterexample.Output = ""
+	}
+	if counterexample.Answer, err = readFile(answerPath, 4096); err != nil {
+		return nil, err
+	}
+	return &counterexample, nil
+}
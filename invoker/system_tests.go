@@ -0,0 +1,206 @@
+package invoker
+
+import (
+	"context"
+	"time"
+
+	"github.com/udovin/solve/models"
+	"github.com/udovin/solve/pkg/logs"
+)
+
+// runSystemTests periodically starts system testing of finished contests.
+func (s *Invoker) runSystemTests(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		if err := s.startSystemTests(ctx, time.Now()); err != nil {
+			s.core.Logger().Error("Cannot start system tests", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Invoker) startSystemTests(ctx context.Context, now time.Time) error {
+	if err := s.core.Contests.Sync(ctx); err != nil {
+		return err
+	}
+	contests, err := s.core.Contests.All()
+	if err != nil {
+		return err
+	}
+	for _, contest := range contests {
+		if !needSystemTests(contest, now) {
+			continue
+		}
+		started, err := s.startContestSystemTests(ctx, contest.ID, now)
+		if err != nil {
+			s.core.Logger().Error(
+				"Cannot start contest system tests", err,
+				logs.Any("contest_id", contest.ID),
+			)
+			continue
+		}
+		if !started {
+			continue
+		}
+		s.core.Logger().Info(
+			"Contest system tests started",
+			logs.Any("contest_id", contest.ID),
+		)
+	}
+	return nil
+}
+
+// needSystemTests returns true if contest is finished and its pretested
+// solutions should be judged on full set of tests.
+func needSystemTests(contest models.Contest, now time.Time) bool {
+	config, err := contest.GetConfig()
+	if err != nil {
+		return false
+	}
+	if !config.EnablePretests || config.SystemTestTime != 0 {
+		return false
+	}
+	if config.BeginTime == 0 {
+		return false
+	}
+	endTime := int64(config.BeginTime) + int64(config.Duration)
+	return endTime <= now.Unix()
+}
+
+// isSolutionJudging returns true if solution has queued or running
+// judge task.
+func (s *Invoker) isSolutionJudging(id int64) (bool, error) {
+	tasks, err := s.core.Tasks.FindBySolution(id)
+	if err != nil {
+		return false, err
+	}
+	for _, task := range tasks {
+		if task.Kind != models.JudgeSolutionTask {
+			continue
+		}
+		if task.Status == models.QueuedTask || task.Status == models.RunningTask {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type systemTestKey struct {
+	ParticipantID int64
+	ProblemID     int64
+}
+
+// startContestSystemTests starts system tests of contest and returns
+// true if they are started.
+//
+// Solutions sent at the end of contest can be still judged, so system
+// tests are postponed until all solutions are judged.
+func (s *Invoker) startContestSystemTests(
+	ctx context.Context, contestID int64, now time.Time,
+) (bool, error) {
+	if err := s.core.Contests.Sync(ctx); err != nil {
+		return false, err
+	}
+	if err := s.core.ContestProblems.Sync(ctx); err != nil {
+		return false, err
+	}
+	if err := s.core.ContestSolutions.Sync(ctx); err != nil {
+		return false, err
+	}
+	if err := s.core.Solutions.Sync(ctx); err != nil {
+		return false, err
+	}
+	if err := s.core.Tasks.Sync(ctx); err != nil {
+		return false, err
+	}
+	started := false
+	if err := s.core.WrapTx(ctx, func(ctx context.Context) error {
+		contest, err := s.core.Contests.Get(contestID)
+		if err != nil {
+			return err
+		}
+		if !needSystemTests(contest, now) {
+			return nil
+		}
+		contestSolutions, err := s.core.ContestSolutions.FindByContest(contestID)
+		if err != nil {
+			return err
+		}
+		for _, contestSolution := range contestSolutions {
+			pending, err := s.isSolutionJudging(contestSolution.SolutionID)
+			if err != nil {
+				return err
+			}
+			if pending {
+				return nil
+			}
+		}
+		// Only last solution passed pretests is judged for every
+		// problem of every participant.
+		lastSolutions := map[systemTestKey]models.Solution{}
+		for _, contestSolution := range contestSolutions {
+			solution, err := s.core.Solutions.Get(contestSolution.SolutionID)
+			if err != nil {
+				continue
+			}
+			report, err := solution.GetReport()
+			if err != nil || report == nil {
+				continue
+			}
+			if !report.Pretests || report.Verdict != models.Accepted {
+				continue
+			}
+			key := systemTestKey{
+				ParticipantID: contestSolution.ParticipantID,
+				ProblemID:     contestSolution.ProblemID,
+			}
+			if last, ok := lastSolutions[key]; ok && last.ID > solution.ID {
+				continue
+			}
+			lastSolutions[key] = solution
+		}
+		config, err := contest.GetConfig()
+		if err != nil {
+			return err
+		}
+		config.SystemTestTime = models.NInt64(now.Unix())
+		if err := contest.SetConfig(config); err != nil {
+			return err
+		}
+		if err := s.core.Contests.Update(ctx, contest); err != nil {
+			return err
+		}
+		for key, solution := range lastSolutions {
+			var revisionID int64
+			if problem, err := s.core.ContestProblems.Get(key.ProblemID); err == nil {
+				if problemConfig, err := problem.GetConfig(); err == nil {
+					revisionID = problemConfig.RevisionID
+				}
+			}
+			solution.Report = nil
+			if err := s.core.Solutions.Update(ctx, solution); err != nil {
+				return err
+			}
+			task := models.Task{}
+			if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+				SolutionID: solution.ID,
+				RevisionID: revisionID,
+			}); err != nil {
+				return err
+			}
+			if err := s.core.Tasks.Create(ctx, &task); err != nil {
+				return err
+			}
+		}
+		started = true
+		return nil
+	}, sqlRepeatableRead); err != nil {
+		return false, err
+	}
+	return started, nil
+}
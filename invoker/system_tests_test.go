@@ -0,0 +1,147 @@
+package invoker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestNeedSystemTests(t *testing.T) {
+	now := time.Unix(10000, 0)
+	for _, test := range []struct {
+		Config   models.ContestConfig
+		Expected bool
+	}{
+		{models.ContestConfig{BeginTime: 1000, Duration: 3600}, false},
+		{models.ContestConfig{BeginTime: 1000, Duration: 3600, EnablePretests: true}, true},
+		{models.ContestConfig{BeginTime: 9000, Duration: 3600, EnablePretests: true}, false},
+		{models.ContestConfig{Duration: 3600, EnablePretests: true}, false},
+		{models.ContestConfig{BeginTime: 1000, Duration: 3600, EnablePretests: true, SystemTestTime: 5000}, false},
+	} {
+		contest := models.Contest{}
+		if err := contest.SetConfig(test.Config); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if v := needSystemTests(contest, now); v != test.Expected {
+			t.Fatalf("Expected %v for %+v, got %v", test.Expected, test.Config, v)
+		}
+	}
+}
+
+func TestStartContestSystemTests(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	ctx := context.Background()
+	c := testInvoker.core
+	account := models.Account{Kind: models.UserAccount}
+	if err := c.Accounts.Create(ctx, &account); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contest := models.Contest{Title: "Test contest"}
+	if err := contest.SetConfig(models.ContestConfig{
+		BeginTime:      1000,
+		Duration:       3600,
+		EnablePretests: true,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Contests.Create(ctx, &contest); err != nil {
+		t.Fatal("Error:", err)
+	}
+	var file models.File
+	if err := c.Files.Create(ctx, &file); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem := models.Problem{Title: "Test problem"}
+	if err := c.Problems.Create(ctx, &problem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestProblem := models.ContestProblem{
+		ContestID: contest.ID,
+		ProblemID: problem.ID,
+		Code:      "A",
+	}
+	if err := c.ContestProblems.Create(ctx, &contestProblem); err != nil {
+		t.Fatal("Error:", err)
+	}
+	compiler := models.Compiler{Name: "test", ImageID: file.ID}
+	if err := c.Compilers.Create(ctx, &compiler); err != nil {
+		t.Fatal("Error:", err)
+	}
+	participant := models.ContestParticipant{
+		ContestID: contest.ID,
+		AccountID: account.ID,
+		Kind:      models.RegularParticipant,
+	}
+	if err := c.ContestParticipants.Create(ctx, &participant); err != nil {
+		t.Fatal("Error:", err)
+	}
+	solution := models.Solution{
+		ProblemID:  problem.ID,
+		CompilerID: compiler.ID,
+		AuthorID:   account.ID,
+		CreateTime: 4500,
+	}
+	if err := c.Solutions.Create(ctx, &solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	contestSolution := models.ContestSolution{
+		ContestID:     contest.ID,
+		SolutionID:    solution.ID,
+		ParticipantID: participant.ID,
+		ProblemID:     contestProblem.ID,
+	}
+	if err := c.ContestSolutions.Create(ctx, &contestSolution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	task := models.Task{}
+	if err := task.SetConfig(models.JudgeSolutionTaskConfig{
+		SolutionID: solution.ID,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Tasks.Create(ctx, &task); err != nil {
+		t.Fatal("Error:", err)
+	}
+	now := time.Unix(10000, 0)
+	// Solution is still judged, so system tests should be postponed.
+	if started, err := testInvoker.startContestSystemTests(
+		ctx, contest.ID, now,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if started {
+		t.Fatal("Expected postponed system tests")
+	}
+	task.Status = models.SucceededTask
+	if err := c.Tasks.Update(ctx, task); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict:  models.Accepted,
+		Pretests: true,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if err := c.Solutions.Update(ctx, solution); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if started, err := testInvoker.startContestSystemTests(
+		ctx, contest.ID, now,
+	); err != nil {
+		t.Fatal("Error:", err)
+	} else if !started {
+		t.Fatal("Expected started system tests")
+	}
+	if err := c.Tasks.Sync(ctx); err != nil {
+		t.Fatal("Error:", err)
+	}
+	tasks, err := c.Tasks.FindBySolution(solution.ID)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected %d tasks, got %d", 2, len(tasks))
+	}
+}
@@ -21,6 +21,7 @@ type updateProblemPackageTask struct {
 	problem     models.Problem
 	file        models.File
 	resources   []models.ProblemResource
+	revision    *models.ProblemRevision
 	tempDir     string
 	problemImpl Problem
 }
@@ -56,6 +57,16 @@ func (t *updateProblemPackageTask) Execute(ctx TaskContext) error {
 	if err != nil {
 		return fmt.Errorf("unable to fetch resources: %w", err)
 	}
+	if t.config.RevisionID != 0 {
+		if err := t.invoker.core.ProblemRevisions.Sync(ctx); err != nil {
+			return fmt.Errorf("unable to sync revisions: %w", err)
+		}
+		revision, err := t.invoker.core.ProblemRevisions.Get(t.config.RevisionID)
+		if err != nil {
+			return fmt.Errorf("unable to fetch revision: %w", err)
+		}
+		t.revision = &revision
+	}
 	tempDir, err := makeTempDir()
 	if err != nil {
 		return err
@@ -73,9 +84,15 @@ func (t *updateProblemPackageTask) prepareProblem(ctx TaskContext) error {
 		return fmt.Errorf("problem does not have package")
 	}
 	t.problem.PackageID = models.NInt64(t.file.ID)
-	problem, err := t.invoker.problems.DownloadProblem(
-		ctx, t.problem, PolygonProblem,
-	)
+	kind := PolygonProblem
+	switch t.config.PackageKind {
+	case "", models.PolygonProblemPackage:
+	case models.ICPCProblemPackage:
+		kind = ICPCProblem
+	default:
+		return fmt.Errorf("unsupported package kind: %q", t.config.PackageKind)
+	}
+	problem, err := t.invoker.problems.DownloadProblem(ctx, t.problem, kind)
 	if err != nil {
 		return fmt.Errorf("cannot download problem: %w", err)
 	}
@@ -90,6 +107,38 @@ func max[T constraints.Ordered](a, b T) T {
 	return a
 }
 
+func min[T constraints.Ordered](a, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// makeProblemPackageConfig returns config of problem with updated package.
+//
+// Limits of problem are calculated from new package, so they are reset.
+// Calibration and time limit overrides refer to test groups of previous
+// package, so they are reset too. Overrides are kept on rollback to
+// current revision, because they are restored from this revision.
+// Other fields of config are not stored in package and are kept.
+func makeProblemPackageConfig(
+	prevConfig models.ProblemConfig, kind models.ProblemPackageKind,
+	revisionID int64,
+) models.ProblemConfig {
+	config := prevConfig
+	config.PackageKind = kind
+	config.TimeLimit = 0
+	config.MemoryLimit = 0
+	config.Calibration = nil
+	if revisionID == 0 || revisionID != prevConfig.RevisionID {
+		config.TimeLimitOverrides = nil
+	}
+	if revisionID != 0 {
+		config.RevisionID = revisionID
+	}
+	return config
+}
+
 func (t *updateProblemPackageTask) executeImpl(ctx TaskContext) error {
 	if err := t.prepareProblem(ctx); err != nil {
 		return fmt.Errorf("cannot prepare problem: %w", err)
@@ -107,14 +156,40 @@ func (t *updateProblemPackageTask) executeImpl(ctx TaskContext) error {
 	if err != nil {
 		return fmt.Errorf("cannot get test groups: %w", err)
 	}
-	config := models.ProblemConfig{}
+	prevConfig, err := t.problem.GetConfig()
+	if err != nil {
+		return err
+	}
+	config := makeProblemPackageConfig(
+		prevConfig, t.config.PackageKind, t.config.RevisionID,
+	)
+	testCount := 0
 	for _, group := range groups {
 		config.TimeLimit = max(config.TimeLimit, group.TimeLimit())
 		config.MemoryLimit = max(config.MemoryLimit, group.MemoryLimit())
+		tests, err := group.GetTests()
+		if err != nil {
+			return fmt.Errorf("cannot get tests: %w", err)
+		}
+		testCount += len(tests)
 	}
 	if err := t.problem.SetConfig(config); err != nil {
 		return err
 	}
+	if t.revision != nil {
+		revisionConfig, err := t.revision.GetConfig()
+		if err != nil {
+			return err
+		}
+		revisionConfig.PackageKind = config.PackageKind
+		revisionConfig.TimeLimit = config.TimeLimit
+		revisionConfig.MemoryLimit = config.MemoryLimit
+		revisionConfig.TestCount = testCount
+		revisionConfig.SetJudgeSettings(config)
+		if err := t.revision.SetConfig(revisionConfig); err != nil {
+			return err
+		}
+	}
 	type eventKey struct {
 		Locale string
 		Kind   models.ProblemResourceKind
@@ -260,6 +335,9 @@ func (t *updateProblemPackageTask) executeImpl(ctx TaskContext) error {
 			}
 			files = append(files, file)
 			t.problem.CompiledID = models.NInt64(file.ID)
+			if t.revision != nil {
+				t.revision.CompiledID = models.NInt64(file.ID)
+			}
 		}
 	}
 	for key, fileReader := range fileReaders {
@@ -314,6 +392,13 @@ func (t *updateProblemPackageTask) executeImpl(ctx TaskContext) error {
 				)
 			}
 		}
+		if t.revision != nil {
+			if err := t.invoker.core.ProblemRevisions.Update(
+				ctx, *t.revision,
+			); err != nil {
+				return err
+			}
+		}
 		return t.invoker.core.Problems.Update(ctx, t.problem)
 	}, sqlRepeatableRead)
 }
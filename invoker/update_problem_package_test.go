@@ -0,0 +1,50 @@
+package invoker
+
+import (
+	"testing"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestMakeProblemPackageConfig(t *testing.T) {
+	prevConfig := models.ProblemConfig{
+		TimeLimit:   1000,
+		MemoryLimit: 256 * 1024 * 1024,
+		RevisionID:  1,
+		CompilerLimits: models.CompilerLimitsOverrides{
+			"python": {TimeMultiplier: 3},
+		},
+		Pretests:           &models.ProblemPretestsConfig{Count: 2},
+		TimeLimitOverrides: map[string]int64{"tests": 2000},
+		Calibration:        &models.ProblemCalibration{TimeLimit: 2000},
+	}
+	config := makeProblemPackageConfig(prevConfig, models.ICPCProblemPackage, 0)
+	if config.TimeLimit != 0 || config.MemoryLimit != 0 {
+		t.Fatalf("Limits should be reset: %+v", config)
+	}
+	if config.PackageKind != models.ICPCProblemPackage || config.RevisionID != 1 {
+		t.Fatalf("Invalid config: %+v", config)
+	}
+	if limits, ok := config.CompilerLimits["python"]; !ok ||
+		limits.TimeMultiplier != 3 {
+		t.Fatalf("Compiler limits should be kept: %+v", config)
+	}
+	if config.Pretests == nil || config.Pretests.Count != 2 {
+		t.Fatalf("Pretests should be kept: %+v", config)
+	}
+	if config.TimeLimitOverrides != nil || config.Calibration != nil {
+		t.Fatalf("Overrides and calibration should be reset: %+v", config)
+	}
+	config = makeProblemPackageConfig(prevConfig, models.PolygonProblemPackage, 2)
+	if config.RevisionID != 2 {
+		t.Fatalf("Expected revision 2, got %d", config.RevisionID)
+	}
+	if config.TimeLimitOverrides != nil || config.Calibration != nil {
+		t.Fatalf("Overrides and calibration should be reset: %+v", config)
+	}
+	// Overrides of current revision are kept on rollback.
+	config = makeProblemPackageConfig(prevConfig, models.PolygonProblemPackage, 1)
+	if config.TimeLimitOverrides["tests"] != 2000 || config.Calibration != nil {
+		t.Fatalf("Overrides should be kept: %+v", config)
+	}
+}
@@ -34,6 +34,29 @@ func copyFileRec(source, target string) error {
 	return copyFile(source, target)
 }
 
+// copyDirFiles copies regular files from source directory to target.
+func copyDirFiles(source, target string) error {
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(target, os.ModePerm); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if err := copyFile(
+			filepath.Join(source, entry.Name()),
+			filepath.Join(target, entry.Name()),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func copyFile(source, target string) error {
 	r, err := os.Open(source)
 	if err != nil {
@@ -20,8 +20,10 @@ var (
 		DB: config.DB{
 			Options: config.SQLiteOptions{Path: ":memory:"},
 		},
-		Server:  &config.Server{},
-		Invoker: &config.Invoker{},
+		Server: &config.Server{},
+		Invoker: &config.Invoker{
+			Sandbox: config.FakeSandbox,
+		},
 		Security: &config.Security{
 			PasswordSalt: "qwerty123",
 		},
@@ -2,6 +2,7 @@ package managers
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -12,15 +13,48 @@ import (
 type ContestManager struct {
 	contests     *models.ContestStore
 	participants *models.ContestParticipantStore
+	teams        *models.TeamStore
+	teamMembers  *models.TeamMemberStore
 }
 
 func NewContestManager(core *core.Core) *ContestManager {
 	return &ContestManager{
 		contests:     core.Contests,
 		participants: core.ContestParticipants,
+		teams:        core.Teams,
+		teamMembers:  core.TeamMembers,
 	}
 }
 
+// getTeamParticipants returns participants of teams where specified
+// account is a member, so member can act for team participant.
+func (m *ContestManager) getTeamParticipants(
+	contestID int64, accountID int64,
+) ([]models.ContestParticipant, error) {
+	members, err := m.teamMembers.FindByAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	var participants []models.ContestParticipant
+	for _, member := range members {
+		team, err := m.teams.Get(member.TeamID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, err
+		}
+		teamParticipants, err := m.participants.FindByContestAccount(
+			contestID, team.AccountID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		participants = append(participants, teamParticipants...)
+	}
+	return participants, nil
+}
+
 func addContestManagerPermissions(permissions PermissionSet) {
 	permissions.AddPermission(
 		models.ObserveContestRole,
@@ -42,6 +76,10 @@ func addContestManagerPermissions(permissions PermissionSet) {
 		models.SubmitContestSolutionRole,
 		models.ObserveContestStandingsRole,
 		models.ObserveContestFullStandingsRole,
+		models.ObserveContestClarificationsRole,
+		models.CreateContestClarificationRole,
+		models.UpdateContestClarificationRole,
+		models.CreateContestAnnouncementRole,
 		models.ObserveSolutionReportTestNumber,
 		models.ObserveSolutionReportCheckerLogs,
 	)
@@ -61,6 +99,7 @@ func addContestRegularPermissions(
 			models.ObserveContestSolutionsRole,
 			models.SubmitContestSolutionRole,
 			models.ObserveContestStandingsRole,
+			models.ObserveContestClarificationsRole,
 			models.ObserveSolutionReportTestNumber,
 		)
 	case ContestFinished:
@@ -69,6 +108,7 @@ func addContestRegularPermissions(
 			models.ObserveContestProblemRole,
 			models.ObserveContestSolutionsRole,
 			models.ObserveContestStandingsRole,
+			models.ObserveContestClarificationsRole,
 			models.ObserveSolutionReportTestNumber,
 		)
 	}
@@ -85,11 +125,81 @@ func addContestUpsolvingPermissions(
 			models.ObserveContestSolutionsRole,
 			models.SubmitContestSolutionRole,
 			models.ObserveContestStandingsRole,
+			models.ObserveContestClarificationsRole,
 			models.ObserveSolutionReportTestNumber,
 		)
 	}
 }
 
+// getParticipantStage returns stage of contest for specified participant.
+//
+// Virtual participants and regular participants of windowed contests
+// have their own begin time, so contest is started for them only within
+// their own window.
+func getParticipantStage(
+	config models.ContestConfig, stage ContestStage,
+	participant models.ContestParticipant, now time.Time,
+) ContestStage {
+	switch participant.Kind {
+	case models.VirtualParticipant:
+		return getVirtualParticipantStage(config, participant, now)
+	case models.RegularParticipant:
+		if config.PersonalDuration == 0 || stage != ContestStarted {
+			return stage
+		}
+		return getWindowParticipantStage(config, participant, now)
+	default:
+		return stage
+	}
+}
+
+// getWindowParticipantStage returns stage of windowed contest for regular
+// participant.
+//
+// Personal timer of participant cannot exceed end of contest.
+func getWindowParticipantStage(
+	config models.ContestConfig, participant models.ContestParticipant,
+	now time.Time,
+) ContestStage {
+	var participantConfig models.RegularParticipantConfig
+	if err := participant.ScanConfig(&participantConfig); err != nil {
+		return ContestNotStarted
+	}
+	if participantConfig.BeginTime == 0 {
+		return ContestNotStarted
+	}
+	endTime := int64(participantConfig.BeginTime) + int64(config.PersonalDuration)
+	if contestEndTime := int64(config.BeginTime) + int64(config.Duration); endTime > contestEndTime {
+		endTime = contestEndTime
+	}
+	if now.Unix() < endTime {
+		return ContestStarted
+	}
+	return ContestFinished
+}
+
+func getVirtualParticipantStage(
+	config models.ContestConfig, participant models.ContestParticipant,
+	now time.Time,
+) ContestStage {
+	var participantConfig models.RegularParticipantConfig
+	if err := participant.ScanConfig(&participantConfig); err != nil {
+		return ContestNotStarted
+	}
+	if participantConfig.BeginTime == 0 {
+		return ContestNotStarted
+	}
+	beginTime := int64(participantConfig.BeginTime)
+	switch {
+	case now.Unix() < beginTime:
+		return ContestNotStarted
+	case now.Unix() < beginTime+int64(config.Duration):
+		return ContestStarted
+	default:
+		return ContestFinished
+	}
+}
+
 func getParticipantPermissions(
 	contest models.Contest, stage ContestStage,
 	participant models.ContestParticipant,
@@ -98,6 +208,12 @@ func getParticipantPermissions(
 	switch participant.Kind {
 	case models.RegularParticipant:
 		addContestRegularPermissions(permissions, stage)
+		// Only participants of running contest can ask jury.
+		if stage == ContestStarted {
+			permissions.AddPermission(models.CreateContestClarificationRole)
+		}
+	case models.VirtualParticipant:
+		addContestRegularPermissions(permissions, stage)
 	case models.UpsolvingParticipant:
 		addContestUpsolvingPermissions(permissions, stage)
 	case models.ManagerParticipant:
@@ -110,7 +226,7 @@ func checkEffectiveParticipant(
 	stage ContestStage, participant models.ContestParticipant,
 ) bool {
 	switch participant.Kind {
-	case models.RegularParticipant:
+	case models.RegularParticipant, models.VirtualParticipant:
 		return stage == ContestStarted
 	case models.UpsolvingParticipant:
 		return stage == ContestFinished
@@ -134,7 +250,8 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 		Now:            models.GetNow(ctx),
 	}
 	now := c.Now.Unix()
-	if config.BeginTime != 0 {
+	// Templates are never started, so nobody can participate in them.
+	if config.BeginTime != 0 && !config.Template {
 		c.Stage = ContestNotStarted
 		if now >= int64(config.BeginTime) {
 			c.Stage = ContestStarted
@@ -151,22 +268,34 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 		if err != nil {
 			return nil, fmt.Errorf("unable to build contest context: %w", err)
 		}
+		teamParticipants, err := m.getTeamParticipants(contest.ID, account.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to build contest context: %w", err)
+		}
+		participants = append(participants, teamParticipants...)
 		hasRegular := false
 		hasUpsolving := false
 		hasManager := false
+		hasVirtual := false
 		for _, participant := range participants {
+			participantStage := c.GetParticipantStage(participant)
 			for permission := range getParticipantPermissions(
-				contest, c.Stage, participant,
+				contest, participantStage, participant,
 			) {
 				c.Permissions.AddPermission(permission)
 			}
 			switch participant.Kind {
 			case models.RegularParticipant:
 				hasRegular = true
+				if c.Stage == ContestStarted && participantStage == ContestNotStarted {
+					c.Permissions.AddPermission(models.StartContestRole)
+				}
 			case models.UpsolvingParticipant:
 				hasUpsolving = true
 			case models.ManagerParticipant:
 				hasManager = true
+			case models.VirtualParticipant:
+				hasVirtual = true
 			}
 		}
 		c.Participants = participants
@@ -178,7 +307,9 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 			})
 			addContestManagerPermissions(c.Permissions)
 		}
-		if !hasRegular && c.Stage == ContestNotStarted && config.EnableRegistration {
+		canRegister := c.Stage == ContestNotStarted ||
+			(c.Stage == ContestStarted && config.PersonalDuration > 0)
+		if !hasRegular && canRegister && config.EnableRegistration {
 			c.Permissions.AddPermission(models.ObserveContestRole)
 			if c.HasPermission(models.RegisterContestsRole) {
 				c.Permissions.AddPermission(models.RegisterContestRole)
@@ -194,10 +325,18 @@ func (m *ContestManager) BuildContext(ctx *AccountContext, contest models.Contes
 			})
 			addContestUpsolvingPermissions(c.Permissions, c.Stage)
 		}
+		if !hasRegular && !hasVirtual && c.Stage == ContestFinished && config.EnableVirtual {
+			c.Permissions.AddPermission(models.ObserveContestRole)
+			if c.HasPermission(models.RegisterContestsRole) {
+				c.Permissions.AddPermission(models.StartVirtualContestRole)
+			}
+		}
 	}
 	c.effectivePos = len(c.Participants)
 	for i := 0; i < len(c.Participants); i++ {
-		if checkEffectiveParticipant(c.Stage, c.Participants[i]) {
+		if checkEffectiveParticipant(
+			c.GetParticipantStage(c.Participants[i]), c.Participants[i],
+		) {
 			c.effectivePos = i
 			break
 		}
@@ -228,6 +367,33 @@ func (c *ContestContext) HasPermission(name string) bool {
 	return c.Permissions.HasPermission(name)
 }
 
+// GetParticipantStage returns stage of contest for specified participant.
+func (c *ContestContext) GetParticipantStage(
+	participant models.ContestParticipant,
+) ContestStage {
+	config, err := c.Contest.GetConfig()
+	if err != nil {
+		return c.Stage
+	}
+	return getParticipantStage(config, c.Stage, participant, c.Now)
+}
+
+// GetEffectiveStage returns stage of contest for effective participant.
+//
+// If there is no effective participant, stage of regular participant
+// is used, so personal timers of windowed contests are respected.
+func (c *ContestContext) GetEffectiveStage() ContestStage {
+	if participant := c.GetEffectiveParticipant(); participant != nil {
+		return c.GetParticipantStage(*participant)
+	}
+	for _, participant := range c.Participants {
+		if participant.Kind == models.RegularParticipant {
+			return c.GetParticipantStage(participant)
+		}
+	}
+	return c.Stage
+}
+
 func (c *ContestContext) GetEffectiveParticipant() *models.ContestParticipant {
 	if c.effectivePos >= len(c.Participants) {
 		return nil
@@ -238,7 +404,9 @@ func (c *ContestContext) GetEffectiveParticipant() *models.ContestParticipant {
 func (c *ContestContext) SetEffectiveParticipant(id int64) {
 	for i := range c.Participants {
 		if c.Participants[i].ID == id {
-			if checkEffectiveParticipant(c.Stage, c.Participants[i]) {
+			if checkEffectiveParticipant(
+				c.GetParticipantStage(c.Participants[i]), c.Participants[i],
+			) {
 				c.effectivePos = i
 			}
 			break
@@ -251,7 +419,9 @@ func (c *ContestContext) GetEffectivePermissions() PermissionSet {
 	if participant == nil {
 		return PermissionSet{}
 	}
-	return getParticipantPermissions(c.Contest, c.Stage, *participant)
+	return getParticipantPermissions(
+		c.Contest, c.GetParticipantStage(*participant), *participant,
+	)
 }
 
 func (c *ContestContext) HasEffectivePermission(name string) bool {
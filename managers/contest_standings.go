@@ -19,6 +19,13 @@ type ContestStandingsCell struct {
 	Verdict models.Verdict
 	Attempt int
 	Time    int64
+	// Pretests means that verdict is based on pretests.
+	Pretests bool
+	// Points contains points of cell in IOI standings.
+	Points float64
+	// Frozen means that cell contains solutions submitted after
+	// standings freeze, so verdict is hidden.
+	Frozen bool
 }
 
 type ContestStandingsRow struct {
@@ -26,11 +33,24 @@ type ContestStandingsRow struct {
 	Cells       []ContestStandingsCell
 	Score       int
 	Penalty     int64
+	// Points contains sum of points in IOI standings.
+	Points float64
+	// LastImprovement contains time of last improvement of points.
+	LastImprovement int64
 }
 
 type ContestStandings struct {
+	Kind    models.ContestStandingsKind
 	Columns []ContestStandingsColumn
 	Rows    []ContestStandingsRow
+	// Frozen means that standings are frozen.
+	Frozen bool
+}
+
+// BuildStandingsOptions represents options for building standings.
+type BuildStandingsOptions struct {
+	// IgnoreFreeze means that live standings should be built.
+	IgnoreFreeze bool
 }
 
 type ContestStandingsManager struct {
@@ -49,7 +69,10 @@ func NewContestStandingsManager(core *core.Core) *ContestStandingsManager {
 	}
 }
 
-func (m *ContestStandingsManager) BuildStandings(ctx context.Context, contest models.Contest, now time.Time) (*ContestStandings, error) {
+func (m *ContestStandingsManager) BuildStandings(
+	ctx context.Context, contest models.Contest, now time.Time,
+	options BuildStandingsOptions,
+) (*ContestStandings, error) {
 	contestConfig, err := contest.GetConfig()
 	if err != nil {
 		return nil, err
@@ -65,7 +88,16 @@ func (m *ContestStandingsManager) BuildStandings(ctx context.Context, contest mo
 	sortFunc(contestProblems, func(lhs, rhs models.ContestProblem) bool {
 		return lhs.Code < rhs.Code
 	})
-	standings := ContestStandings{}
+	standings := ContestStandings{Kind: models.ICPCStandings}
+	if contestConfig.StandingsKind == models.IOIStandings {
+		standings.Kind = models.IOIStandings
+	}
+	freeze := contestConfig.FreezeBeginDuration > 0 &&
+		!contestConfig.Unfrozen && !options.IgnoreFreeze
+	if freeze && contestConfig.BeginTime != 0 {
+		freezeTime := int64(contestConfig.BeginTime) + int64(contestConfig.FreezeBeginDuration)
+		standings.Frozen = now.Unix() >= freezeTime
+	}
 	columnByProblem := map[int64]int{}
 	for i, problem := range contestProblems {
 		standings.Columns = append(standings.Columns, ContestStandingsColumn{
@@ -85,7 +117,8 @@ func (m *ContestStandingsManager) BuildStandings(ctx context.Context, contest mo
 	}
 	for _, participant := range participants {
 		beginTime := int64(contestConfig.BeginTime)
-		if participant.Kind == models.RegularParticipant {
+		switch participant.Kind {
+		case models.RegularParticipant, models.VirtualParticipant:
 			var participantConfig models.RegularParticipantConfig
 			if err := participant.ScanConfig(&participantConfig); err != nil {
 				continue
@@ -127,42 +160,38 @@ func (m *ContestStandingsManager) BuildStandings(ctx context.Context, contest mo
 				}
 				return lhs.ID < rhs.ID
 			})
-			cell := ContestStandingsCell{
-				Column: i,
+			var freezeTime int64
+			if freeze && beginTime != 0 && !contestConfig.IsCellUnfrozen(
+				models.ContestStandingsCellKey{
+					ParticipantID: participant.ID,
+					ProblemID:     standings.Columns[i].Problem.ID,
+				},
+			) {
+				freezeTime = beginTime + int64(contestConfig.FreezeBeginDuration)
 			}
-			for _, solution := range solutions {
-				if solution.CreateTime >= now.Unix() {
-					continue
-				}
-				report, err := solution.GetReport()
-				if err != nil {
-					continue
-				}
-				if report == nil {
-					cell.Attempt++
-					cell.Verdict = 0
-					break
-				}
-				if report.Verdict == models.CompilationError {
-					continue
-				}
-				cell.Attempt++
-				cell.Verdict = report.Verdict
-				if beginTime != 0 {
-					cell.Time = solution.CreateTime - beginTime
-					if cell.Time < 0 {
-						cell.Time = 0
-					}
-				}
-				if report.Verdict == models.Accepted {
-					break
-				}
+			var cell ContestStandingsCell
+			if standings.Kind == models.IOIStandings {
+				cell = buildIOICell(
+					i, standings.Columns[i].Problem, solutions,
+					contestConfig, beginTime, freezeTime, now,
+				)
+			} else {
+				cell = buildICPCCell(
+					i, solutions, contestConfig, beginTime, freezeTime, now,
+				)
 			}
 			if cell.Attempt > 0 {
 				row.Cells = append(row.Cells, cell)
 			}
 		}
 		for _, cell := range row.Cells {
+			if standings.Kind == models.IOIStandings {
+				row.Points += cell.Points
+				if cell.Points > 0 && cell.Time > row.LastImprovement {
+					row.LastImprovement = cell.Time
+				}
+				continue
+			}
 			if cell.Verdict == models.Accepted {
 				problem := standings.Columns[cell.Column].Problem
 				row.Score += getProblemScore(problem)
@@ -177,6 +206,12 @@ func (m *ContestStandingsManager) BuildStandings(ctx context.Context, contest mo
 		if lhsOrder != rhsOrder {
 			return lhsOrder < rhsOrder
 		}
+		if standings.Kind == models.IOIStandings {
+			if lhs.Points != rhs.Points {
+				return lhs.Points > rhs.Points
+			}
+			return lhs.LastImprovement < rhs.LastImprovement
+		}
 		if lhs.Score != rhs.Score {
 			return lhs.Score > rhs.Score
 		}
@@ -185,11 +220,147 @@ func (m *ContestStandingsManager) BuildStandings(ctx context.Context, contest mo
 	return &standings, nil
 }
 
+// getCellTime returns time of solution relative to participant begin.
+func getCellTime(solution models.Solution, beginTime int64) int64 {
+	if beginTime == 0 {
+		return 0
+	}
+	if solution.CreateTime < beginTime {
+		return 0
+	}
+	return solution.CreateTime - beginTime
+}
+
+// buildICPCCell builds cell with verdict of first accepted solution.
+//
+// Solutions submitted after non-zero freeze time are counted as attempts
+// with hidden verdicts.
+func buildICPCCell(
+	column int, solutions []models.Solution,
+	contestConfig models.ContestConfig, beginTime, freezeTime int64,
+	now time.Time,
+) ContestStandingsCell {
+	cell := ContestStandingsCell{
+		Column: column,
+	}
+	for _, solution := range solutions {
+		if solution.CreateTime >= now.Unix() {
+			continue
+		}
+		if freezeTime != 0 && solution.CreateTime >= freezeTime {
+			cell.Attempt++
+			cell.Verdict = 0
+			cell.Pretests = false
+			cell.Frozen = true
+			cell.Time = getCellTime(solution, beginTime)
+			continue
+		}
+		report, err := solution.GetReport()
+		if err != nil {
+			continue
+		}
+		if report == nil {
+			cell.Attempt++
+			cell.Verdict = 0
+			break
+		}
+		if report.Verdict == models.CompilationError {
+			continue
+		}
+		pretested := report.Pretests && report.Verdict == models.Accepted
+		// After system testing solutions that passed pretests but
+		// were not retested are skipped.
+		if pretested && contestConfig.SystemTestTime != 0 {
+			continue
+		}
+		cell.Attempt++
+		cell.Verdict = report.Verdict
+		cell.Pretests = pretested
+		cell.Time = getCellTime(solution, beginTime)
+		if report.Verdict == models.Accepted {
+			break
+		}
+	}
+	return cell
+}
+
+// buildIOICell builds cell with best or last points of solutions.
+//
+// Time of cell contains time of last improvement of points.
+func buildIOICell(
+	column int, problem models.ContestProblem, solutions []models.Solution,
+	contestConfig models.ContestConfig, beginTime, freezeTime int64,
+	now time.Time,
+) ContestStandingsCell {
+	cell := ContestStandingsCell{
+		Column: column,
+	}
+	for _, solution := range solutions {
+		if solution.CreateTime >= now.Unix() {
+			continue
+		}
+		if freezeTime != 0 && solution.CreateTime >= freezeTime {
+			// Points of frozen solutions are hidden.
+			cell.Attempt++
+			cell.Frozen = true
+			continue
+		}
+		report, err := solution.GetReport()
+		if err != nil {
+			continue
+		}
+		if report == nil {
+			// Points of pending solution are not known yet.
+			cell.Attempt++
+			continue
+		}
+		if report.Verdict == models.CompilationError {
+			continue
+		}
+		pretested := report.Pretests && report.Verdict == models.Accepted
+		if pretested && contestConfig.SystemTestTime != 0 {
+			continue
+		}
+		cell.Attempt++
+		points := getSolutionPoints(problem, report)
+		switch contestConfig.IOIScoring {
+		case models.LastIOIScoring:
+			if cell.Verdict == 0 || points != cell.Points {
+				cell.Time = getCellTime(solution, beginTime)
+			}
+		default:
+			if cell.Verdict != 0 && points <= cell.Points {
+				continue
+			}
+			cell.Time = getCellTime(solution, beginTime)
+		}
+		cell.Points = points
+		cell.Verdict = report.Verdict
+		cell.Pretests = pretested
+	}
+	return cell
+}
+
+// getSolutionPoints returns points of solution for IOI standings.
+//
+// Accepted solutions without points get full points of problem.
+func getSolutionPoints(
+	problem models.ContestProblem, report *models.SolutionReport,
+) float64 {
+	if report.Points != nil {
+		return *report.Points
+	}
+	if report.Verdict == models.Accepted {
+		return float64(getProblemScore(problem))
+	}
+	return 0
+}
+
 func getParticipantOrder(kind models.ParticipantKind) int {
 	switch kind {
 	case models.ManagerParticipant:
 		return 0
-	case models.RegularParticipant:
+	case models.RegularParticipant, models.VirtualParticipant:
 		return 1
 	default:
 		return 2
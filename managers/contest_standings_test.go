@@ -0,0 +1,88 @@
+package managers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestBuildIOICell(t *testing.T) {
+	points := func(v float64) *float64 { return &v }
+	tests := []struct {
+		CreateTime int64
+		Report     models.SolutionReport
+	}{
+		{110, models.SolutionReport{Verdict: models.WrongAnswer, Points: points(30)}},
+		{120, models.SolutionReport{Verdict: models.CompilationError}},
+		{130, models.SolutionReport{Verdict: models.WrongAnswer, Points: points(60)}},
+		{140, models.SolutionReport{Verdict: models.WrongAnswer, Points: points(10)}},
+	}
+	var solutions []models.Solution
+	for _, test := range tests {
+		solution := models.Solution{CreateTime: test.CreateTime}
+		if err := solution.SetReport(&test.Report); err != nil {
+			t.Fatal("Error:", err)
+		}
+		solutions = append(solutions, solution)
+	}
+	now := time.Unix(1000, 0)
+	problem := models.ContestProblem{}
+	best := buildIOICell(0, problem, solutions, models.ContestConfig{}, 100, 0, now)
+	if best.Points != 60 || best.Time != 30 || best.Attempt != 3 {
+		t.Fatalf("Invalid best cell: %+v", best)
+	}
+	config := models.ContestConfig{IOIScoring: models.LastIOIScoring}
+	last := buildIOICell(0, problem, solutions, config, 100, 0, now)
+	if last.Points != 10 || last.Time != 40 {
+		t.Fatalf("Invalid last cell: %+v", last)
+	}
+	solution := models.Solution{CreateTime: 150}
+	if err := solution.SetReport(&models.SolutionReport{
+		Verdict: models.Accepted,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	accepted := buildIOICell(
+		0, problem, []models.Solution{solution}, models.ContestConfig{}, 100, 0, now,
+	)
+	if accepted.Points != 1 || accepted.Verdict != models.Accepted {
+		t.Fatalf("Invalid accepted cell: %+v", accepted)
+	}
+}
+
+func TestBuildFrozenCell(t *testing.T) {
+	tests := []struct {
+		CreateTime int64
+		Verdict    models.Verdict
+	}{
+		{110, models.WrongAnswer},
+		{130, models.WrongAnswer},
+		{140, models.Accepted},
+	}
+	var solutions []models.Solution
+	for _, test := range tests {
+		solution := models.Solution{CreateTime: test.CreateTime}
+		if err := solution.SetReport(&models.SolutionReport{
+			Verdict: test.Verdict,
+		}); err != nil {
+			t.Fatal("Error:", err)
+		}
+		solutions = append(solutions, solution)
+	}
+	now := time.Unix(1000, 0)
+	config := models.ContestConfig{}
+	live := buildICPCCell(0, solutions, config, 100, 0, now)
+	if live.Verdict != models.Accepted || live.Attempt != 3 || live.Frozen {
+		t.Fatalf("Invalid live cell: %+v", live)
+	}
+	frozen := buildICPCCell(0, solutions, config, 100, 120, now)
+	if frozen.Verdict != 0 || frozen.Attempt != 3 || !frozen.Frozen || frozen.Time != 40 {
+		t.Fatalf("Invalid frozen cell: %+v", frozen)
+	}
+	// Accepted solution before freeze is visible.
+	accepted := buildICPCCell(0, solutions, config, 100, 141, now)
+	if accepted.Verdict != models.Accepted || accepted.Attempt != 3 || accepted.Frozen {
+		t.Fatalf("Invalid accepted cell: %+v", accepted)
+	}
+}
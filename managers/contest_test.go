@@ -0,0 +1,71 @@
+package managers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/udovin/solve/models"
+)
+
+func TestGetParticipantStage(t *testing.T) {
+	config := models.ContestConfig{BeginTime: 100, Duration: 50}
+	virtual := models.ContestParticipant{Kind: models.VirtualParticipant}
+	if err := virtual.SetConfig(models.RegularParticipantConfig{
+		BeginTime: 1000,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	tests := []struct {
+		Now   int64
+		Stage ContestStage
+	}{
+		{999, ContestNotStarted},
+		{1000, ContestStarted},
+		{1049, ContestStarted},
+		{1050, ContestFinished},
+	}
+	for _, test := range tests {
+		stage := getParticipantStage(
+			config, ContestFinished, virtual, time.Unix(test.Now, 0),
+		)
+		if stage != test.Stage {
+			t.Fatalf("Expected stage %v, got %v at %d", test.Stage, stage, test.Now)
+		}
+	}
+	regular := models.ContestParticipant{Kind: models.RegularParticipant}
+	if stage := getParticipantStage(
+		config, ContestFinished, regular, time.Unix(1000, 0),
+	); stage != ContestFinished {
+		t.Fatalf("Expected stage %v, got %v", ContestFinished, stage)
+	}
+	window := models.ContestConfig{
+		BeginTime: 100, Duration: 1000, PersonalDuration: 300,
+	}
+	if stage := getParticipantStage(
+		window, ContestStarted, regular, time.Unix(500, 0),
+	); stage != ContestNotStarted {
+		t.Fatalf("Expected stage %v, got %v", ContestNotStarted, stage)
+	}
+	if err := regular.SetConfig(models.RegularParticipantConfig{
+		BeginTime: 900,
+	}); err != nil {
+		t.Fatal("Error:", err)
+	}
+	windowTests := []struct {
+		Now   int64
+		Stage ContestStage
+	}{
+		{1000, ContestStarted},
+		{1099, ContestStarted},
+		// Personal timer cannot exceed end of contest.
+		{1100, ContestFinished},
+	}
+	for _, test := range windowTests {
+		stage := getParticipantStage(
+			window, ContestStarted, regular, time.Unix(test.Now, 0),
+		)
+		if stage != test.Stage {
+			t.Fatalf("Expected stage %v, got %v at %d", test.Stage, stage, test.Now)
+		}
+	}
+}
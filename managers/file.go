@@ -22,6 +22,7 @@ import (
 	"github.com/udovin/solve/core"
 	"github.com/udovin/solve/db"
 	"github.com/udovin/solve/models"
+	"github.com/udovin/solve/pkg/logs"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
@@ -188,6 +189,7 @@ type FileManager struct {
 	files         *models.FileStore
 	storage       FileStorage
 	uploadTimeout time.Duration
+	logger        *logs.Logger
 }
 
 func NewFileManager(c *core.Core) *FileManager {
@@ -230,6 +232,7 @@ func NewFileManager(c *core.Core) *FileManager {
 		files:         c.Files,
 		storage:       storage,
 		uploadTimeout: 10 * time.Minute,
+		logger:        c.Logger(),
 	}
 }
 
@@ -324,6 +327,56 @@ func (m *FileManager) ConfirmUploadFile(
 	return nil
 }
 
+// ConfirmUploadTemporaryFile marks file available until specified time.
+//
+// Expired files are removed by DeleteExpiredFiles.
+func (m *FileManager) ConfirmUploadTemporaryFile(
+	ctx context.Context, file *models.File, expireTime time.Time,
+) error {
+	if file.Status != models.PendingFile {
+		return fmt.Errorf("file shoud be in pending status")
+	}
+	clone := file.Clone()
+	clone.Status = models.AvailableFile
+	clone.ExpireTime = models.NInt64(expireTime.Unix())
+	if err := m.files.Update(ctx, clone); err != nil {
+		return err
+	}
+	*file = clone
+	return nil
+}
+
+// DeleteExpiredFiles deletes temporary files and abandoned uploads
+// that are expired at specified time.
+func (m *FileManager) DeleteExpiredFiles(
+	ctx context.Context, now time.Time,
+) (int, error) {
+	if err := m.files.Sync(ctx); err != nil {
+		return 0, err
+	}
+	files, err := m.files.All()
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, file := range files {
+		if file.ExpireTime == 0 || now.Unix() < int64(file.ExpireTime) {
+			continue
+		}
+		// Failed file should not block deletion of other files,
+		// it will be deleted during next call.
+		if err := m.DeleteFile(ctx, file.ID); err != nil {
+			m.logger.Warn(
+				"Cannot delete expired file",
+				logs.Any("id", file.ID), err,
+			)
+			continue
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
 func (m *FileManager) DeleteFile(ctx context.Context, id int64) error {
 	file, err := m.files.Get(id)
 	if err != nil {
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/models"
+)
+
+func init() {
+	Data.AddMigration("003_create_missing_roles", d003{})
+}
+
+// d003 creates built-in roles for virtual participation, windowed
+// contests and teams that were added after 001_create_roles was
+// applied and grants them to admin group.
+type d003 struct{}
+
+var d003Roles = []string{
+	models.StartVirtualContestRole,
+	models.StartContestRole,
+	models.ObserveTeamsRole,
+	models.ObserveTeamRole,
+	models.CreateTeamRole,
+	models.UpdateTeamRole,
+	models.DeleteTeamRole,
+}
+
+func (m d003) Apply(ctx context.Context, db *gosql.DB) error {
+	return createRoles(ctx, db, d003Roles...)
+}
+
+// Unapply does nothing, because data migrations are unapplied only
+// by zero migration after schema, when roles are already dropped with
+// their tables.
+func (m d003) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
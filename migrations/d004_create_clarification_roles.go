@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/models"
+)
+
+func init() {
+	Data.AddMigration("004_create_clarification_roles", d004{})
+}
+
+// d004 creates built-in roles for contest clarifications and grants
+// them to admin group.
+type d004 struct{}
+
+var d004Roles = []string{
+	models.ObserveContestClarificationsRole,
+	models.CreateContestClarificationRole,
+	models.UpdateContestClarificationRole,
+}
+
+func (m d004) Apply(ctx context.Context, db *gosql.DB) error {
+	return createRoles(ctx, db, d004Roles...)
+}
+
+// Unapply does nothing, because roles are dropped with their tables by
+// zero migration of schema.
+func (m d004) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
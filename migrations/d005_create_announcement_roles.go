@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/models"
+)
+
+func init() {
+	Data.AddMigration("005_create_announcement_roles", d005{})
+}
+
+// d005 creates built-in roles for contest announcements and grants
+// them to admin group.
+type d005 struct{}
+
+var d005Roles = []string{
+	models.CreateContestAnnouncementRole,
+}
+
+func (m d005) Apply(ctx context.Context, db *gosql.DB) error {
+	return createRoles(ctx, db, d005Roles...)
+}
+
+// Unapply does nothing, because roles are dropped with their tables by
+// zero migration of schema.
+func (m d005) Unapply(ctx context.Context, db *gosql.DB) error {
+	return nil
+}
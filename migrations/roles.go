@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/udovin/gosql"
+	"github.com/udovin/solve/models"
+)
+
+// createRoles creates missing built-in roles and grants them to admin
+// group.
+//
+// Roles that already exist are skipped, because 001_create_roles
+// creates all built-in roles on new installations.
+func createRoles(ctx context.Context, db *gosql.DB, names ...string) error {
+	roleStore := models.NewRoleStore(db, "solve_role", "solve_role_event")
+	roleEdgeStore := models.NewRoleEdgeStore(db, "solve_role_edge", "solve_role_edge_event")
+	if err := roleStore.Init(ctx); err != nil {
+		return err
+	}
+	adminGroup, err := roleStore.GetByName("admin_group")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := roleStore.GetByName(name); err == nil {
+			continue
+		} else if err != sql.ErrNoRows {
+			return err
+		}
+		role := models.Role{Name: name}
+		if err := roleStore.Create(ctx, &role); err != nil {
+			return err
+		}
+		edge := models.RoleEdge{
+			RoleID:  adminGroup.ID,
+			ChildID: role.ID,
+		}
+		if err := roleEdgeStore.Create(ctx, &edge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/db"
+	"github.com/udovin/solve/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("002_problem_revisions", db.NewMigration(s002))
+}
+
+var s002 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_problem_revision",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64, Nullable: true},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "config", Type: schema.JSON},
+			{Name: "package_id", Type: schema.Int64},
+			{Name: "compiled_id", Type: schema.Int64, Nullable: true},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "problem_id", ParentTable: "solve_problem", ParentColumn: "id"},
+			{Column: "author_id", ParentTable: "solve_account", ParentColumn: "id"},
+			{Column: "package_id", ParentTable: "solve_file", ParentColumn: "id"},
+			{Column: "compiled_id", ParentTable: "solve_file", ParentColumn: "id"},
+		},
+	},
+	schema.CreateTable{
+		Name: "solve_problem_revision_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64},
+			{Name: "author_id", Type: schema.Int64, Nullable: true},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "config", Type: schema.JSON},
+			{Name: "package_id", Type: schema.Int64},
+			{Name: "compiled_id", Type: schema.Int64, Nullable: true},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_problem_revision_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/db"
+	"github.com/udovin/solve/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("003_compiler_state", db.NewMigration(s003))
+}
+
+var s003 = []schema.Operation{
+	schema.AddColumn{
+		Table:  "solve_compiler",
+		Column: schema.Column{Name: "state", Type: schema.JSON, Nullable: true},
+	},
+	schema.AddColumn{
+		Table:  "solve_compiler_event",
+		Column: schema.Column{Name: "state", Type: schema.JSON, Nullable: true},
+	},
+}
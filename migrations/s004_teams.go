@@ -0,0 +1,81 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/db"
+	"github.com/udovin/solve/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("004_teams", db.NewMigration(s004))
+}
+
+var s004 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_team",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "owner_id", Type: schema.Int64, Nullable: true},
+			{Name: "title", Type: schema.String},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "account_id", ParentTable: "solve_account", ParentColumn: "id"},
+			{Column: "owner_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_team",
+		Columns: []string{"account_id"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_team_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "owner_id", Type: schema.Int64, Nullable: true},
+			{Name: "title", Type: schema.String},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_team_event",
+		Columns: []string{"id", "event_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_team_member",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "team_id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "team_id", ParentTable: "solve_team", ParentColumn: "id"},
+			{Column: "account_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_team_member",
+		Columns: []string{"team_id", "account_id"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_team_member_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "team_id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_team_member_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
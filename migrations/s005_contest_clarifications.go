@@ -0,0 +1,93 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/db"
+	"github.com/udovin/solve/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("005_contest_clarifications", db.NewMigration(s005))
+}
+
+var s005 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_clarification",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64, Nullable: true},
+			{Name: "problem_id", Type: schema.Int64, Nullable: true},
+			{Name: "kind", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "question", Type: schema.String},
+			{Name: "answer", Type: schema.String, Nullable: true},
+			{Name: "answer_time", Type: schema.Int64, Nullable: true},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "participant_id", ParentTable: "solve_contest_participant", ParentColumn: "id"},
+			{Column: "problem_id", ParentTable: "solve_contest_problem", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_clarification",
+		Columns: []string{"contest_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_clarification_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64, Nullable: true},
+			{Name: "problem_id", Type: schema.Int64, Nullable: true},
+			{Name: "kind", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "question", Type: schema.String},
+			{Name: "answer", Type: schema.String, Nullable: true},
+			{Name: "answer_time", Type: schema.Int64, Nullable: true},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_clarification_event",
+		Columns: []string{"id", "event_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_clarification_read",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "read_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "account_id", ParentTable: "solve_account", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_clarification_read",
+		Columns: []string{"contest_id", "account_id"},
+		Unique:  true,
+	},
+	schema.CreateTable{
+		Name: "solve_contest_clarification_read_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "account_id", Type: schema.Int64},
+			{Name: "read_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_clarification_read_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"github.com/udovin/solve/db"
+	"github.com/udovin/solve/db/schema"
+)
+
+func init() {
+	Schema.AddMigration("006_contest_announcements", db.NewMigration(s006))
+}
+
+var s006 = []schema.Operation{
+	schema.CreateTable{
+		Name: "solve_contest_announcement",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64, Nullable: true},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "text", Type: schema.String},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "problem_id", ParentTable: "solve_contest_problem", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_announcement",
+		Columns: []string{"contest_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_announcement_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "problem_id", Type: schema.Int64, Nullable: true},
+			{Name: "create_time", Type: schema.Int64},
+			{Name: "text", Type: schema.String},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_announcement_event",
+		Columns: []string{"id", "event_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_notification",
+		Columns: []schema.Column{
+			{Name: "id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64, Nullable: true},
+			{Name: "kind", Type: schema.Int64},
+			{Name: "target_id", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+		},
+		ForeignKeys: []schema.ForeignKey{
+			{Column: "contest_id", ParentTable: "solve_contest", ParentColumn: "id"},
+			{Column: "participant_id", ParentTable: "solve_contest_participant", ParentColumn: "id"},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_notification",
+		Columns: []string{"contest_id"},
+	},
+	schema.CreateTable{
+		Name: "solve_contest_notification_event",
+		Columns: []schema.Column{
+			{Name: "event_id", Type: schema.Int64, PrimaryKey: true, AutoIncrement: true},
+			{Name: "event_kind", Type: schema.Int64},
+			{Name: "event_time", Type: schema.Int64},
+			{Name: "event_account_id", Type: schema.Int64, Nullable: true},
+			{Name: "id", Type: schema.Int64},
+			{Name: "contest_id", Type: schema.Int64},
+			{Name: "participant_id", Type: schema.Int64, Nullable: true},
+			{Name: "kind", Type: schema.Int64},
+			{Name: "target_id", Type: schema.Int64},
+			{Name: "create_time", Type: schema.Int64},
+		},
+	},
+	schema.CreateIndex{
+		Table:   "solve_contest_notification_event",
+		Columns: []string{"id", "event_id"},
+	},
+}
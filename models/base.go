@@ -340,6 +340,11 @@ func (s *baseStore[T, E, TPtr, EPtr]) Find(ctx context.Context, where gosql.Bool
 	return s.store.FindObjects(ctx, where)
 }
 
+// Events returns store of events.
+func (s *baseStore[T, E, TPtr, EPtr]) Events() db.EventROStore[E] {
+	return s.events
+}
+
 // Get returns object by id.
 //
 // Returns sql.ErrNoRows if object does not exist.
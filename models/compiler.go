@@ -3,16 +3,101 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"math"
 
 	"github.com/udovin/gosql"
 )
 
+// CompilerSandboxConfig represents sandbox restrictions for command.
+type CompilerSandboxConfig struct {
+	// PidsLimit limits count of processes and threads.
+	PidsLimit int64 `json:"pids_limit,omitempty"`
+	// FileSizeLimit limits size of each written file in bytes.
+	FileSizeLimit int64 `json:"file_size_limit,omitempty"`
+	// Syscalls contains allowlist of syscalls for seccomp filter.
+	//
+	// Empty allowlist means that seccomp filter is disabled.
+	Syscalls []string `json:"syscalls,omitempty"`
+}
+
 type CompilerCommandConfig struct {
-	Command string   `json:"command"`
-	Environ []string `json:"environ"`
-	Workdir string   `json:"workdir"`
-	Source  *string  `json:"source,omitempty"`
-	Binary  *string  `json:"binary,omitempty"`
+	Command string                 `json:"command"`
+	Environ []string               `json:"environ"`
+	Workdir string                 `json:"workdir"`
+	Source  *string                `json:"source,omitempty"`
+	Binary  *string                `json:"binary,omitempty"`
+	Sandbox *CompilerSandboxConfig `json:"sandbox,omitempty"`
+}
+
+// CompilerTestsConfig represents programs for compiler self-test.
+type CompilerTestsConfig struct {
+	// Version contains command that prints version of compiler.
+	Version string `json:"version,omitempty"`
+	// HelloWorld contains source of program that prints "Hello, World!".
+	HelloWorld string `json:"hello_world,omitempty"`
+	// APlusB contains source of program that prints sum of two integers
+	// from standard input.
+	APlusB string `json:"a_plus_b,omitempty"`
+}
+
+// CompilerLimitsConfig represents adjustment of problem limits for
+// solutions written in specific language.
+type CompilerLimitsConfig struct {
+	// TimeMultiplier contains multiplier for time limit.
+	//
+	// Zero value means that time limit is not multiplied.
+	TimeMultiplier float64 `json:"time_multiplier,omitempty"`
+	// TimeOverhead contains extra time in milliseconds.
+	TimeOverhead int64 `json:"time_overhead,omitempty"`
+	// MemoryMultiplier contains multiplier for memory limit.
+	//
+	// Zero value means that memory limit is not multiplied.
+	MemoryMultiplier float64 `json:"memory_multiplier,omitempty"`
+	// MemoryOverhead contains extra memory in bytes.
+	MemoryOverhead int64 `json:"memory_overhead,omitempty"`
+}
+
+// TimeLimit returns effective time limit in milliseconds.
+func (c CompilerLimitsConfig) TimeLimit(limit int64) int64 {
+	return applyLimitMultiplier(limit, c.TimeMultiplier) + c.TimeOverhead
+}
+
+// MemoryLimit returns effective memory limit in bytes.
+func (c CompilerLimitsConfig) MemoryLimit(limit int64) int64 {
+	return applyLimitMultiplier(limit, c.MemoryMultiplier) + c.MemoryOverhead
+}
+
+func applyLimitMultiplier(limit int64, multiplier float64) int64 {
+	if multiplier == 0 {
+		return limit
+	}
+	return int64(math.Ceil(float64(limit) * multiplier))
+}
+
+// CompilerLimitsOverrides contains compiler limits overridden by name
+// of compiler or by its language.
+type CompilerLimitsOverrides map[string]CompilerLimitsConfig
+
+// GetCompilerLimits returns limits config for specified compiler.
+//
+// Overrides are applied in specified order, so latter overrides have
+// higher priority. Override for compiler name has higher priority than
+// override for language.
+func GetCompilerLimits(
+	name string, config CompilerConfig, overrides ...CompilerLimitsOverrides,
+) CompilerLimitsConfig {
+	var limits CompilerLimitsConfig
+	if config.Limits != nil {
+		limits = *config.Limits
+	}
+	for _, override := range overrides {
+		if value, ok := override[name]; ok {
+			limits = value
+		} else if value, ok := override[config.Language]; ok && config.Language != "" {
+			limits = value
+		}
+	}
+	return limits
 }
 
 type CompilerConfig struct {
@@ -21,6 +106,35 @@ type CompilerConfig struct {
 	Extensions []string               `json:"extensions"`
 	Compile    *CompilerCommandConfig `json:"compile,omitempty"`
 	Execute    *CompilerCommandConfig `json:"execute,omitempty"`
+	Tests      *CompilerTestsConfig   `json:"tests,omitempty"`
+	Limits     *CompilerLimitsConfig  `json:"limits,omitempty"`
+}
+
+// CompilerStatus represents status of compiler self-test.
+type CompilerStatus string
+
+const (
+	// PendingCompiler represents compiler that waits for self-test.
+	PendingCompiler CompilerStatus = "pending"
+	// HealthyCompiler represents compiler that passed self-test.
+	HealthyCompiler CompilerStatus = "healthy"
+	// BrokenCompiler represents compiler that failed self-test.
+	BrokenCompiler CompilerStatus = "broken"
+)
+
+// CompilerState represents result of compiler self-test.
+type CompilerState struct {
+	Status CompilerStatus `json:"status,omitempty"`
+	// Version contains output of version command.
+	Version string `json:"version,omitempty"`
+	// Error contains description of self-test failure.
+	Error string `json:"error,omitempty"`
+	// CompileTime contains maximal compilation time in milliseconds.
+	CompileTime int64 `json:"compile_time,omitempty"`
+	// ExecuteTime contains maximal execution time in milliseconds.
+	ExecuteTime int64 `json:"execute_time,omitempty"`
+	// TestTime contains time of last self-test.
+	TestTime int64 `json:"test_time,omitempty"`
 }
 
 // Compiler represents compiler.
@@ -30,11 +144,13 @@ type Compiler struct {
 	Name    string `db:"name"`
 	Config  JSON   `db:"config"`
 	ImageID int64  `db:"image_id"`
+	State   JSON   `db:"state"`
 }
 
 // Clone create copy of compiler.
 func (o Compiler) Clone() Compiler {
 	o.Config = o.Config.Clone()
+	o.State = o.State.Clone()
 	return o
 }
 
@@ -56,6 +172,34 @@ func (o *Compiler) SetConfig(config CompilerConfig) error {
 	return nil
 }
 
+// GetState returns result of compiler self-test.
+func (o Compiler) GetState() (CompilerState, error) {
+	var state CompilerState
+	if len(o.State) == 0 || string(o.State) == nullJSON {
+		return state, nil
+	}
+	err := json.Unmarshal(o.State, &state)
+	return state, err
+}
+
+// SetState updates result of compiler self-test.
+func (o *Compiler) SetState(state CompilerState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	o.State = raw
+	return nil
+}
+
+// IsBroken returns true if compiler failed self-test.
+//
+// Compilers without self-test results are not considered broken.
+func (o Compiler) IsBroken() bool {
+	state, err := o.GetState()
+	return err == nil && state.Status == BrokenCompiler
+}
+
 // CompilerEvent represents compiler event.
 type CompilerEvent struct {
 	baseEvent
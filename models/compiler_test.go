@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestGetCompilerLimits(t *testing.T) {
+	config := CompilerConfig{
+		Language: "Python",
+		Limits: &CompilerLimitsConfig{
+			TimeMultiplier: 2,
+			TimeOverhead:   100,
+		},
+	}
+	limits := GetCompilerLimits("python3", config)
+	if v := limits.TimeLimit(1000); v != 2100 {
+		t.Fatalf("Expected %d, got %d", 2100, v)
+	}
+	if v := limits.MemoryLimit(1024); v != 1024 {
+		t.Fatalf("Expected %d, got %d", 1024, v)
+	}
+	problem := CompilerLimitsOverrides{
+		"Python": {MemoryMultiplier: 1.5},
+	}
+	contest := CompilerLimitsOverrides{
+		"python3": {TimeMultiplier: 3},
+	}
+	limits = GetCompilerLimits("python3", config, problem)
+	if v := limits.TimeLimit(1000); v != 1000 {
+		t.Fatalf("Expected %d, got %d", 1000, v)
+	}
+	if v := limits.MemoryLimit(1024); v != 1536 {
+		t.Fatalf("Expected %d, got %d", 1536, v)
+	}
+	limits = GetCompilerLimits("python3", config, problem, contest)
+	if v := limits.TimeLimit(1000); v != 3000 {
+		t.Fatalf("Expected %d, got %d", 3000, v)
+	}
+	if v := limits.MemoryLimit(1024); v != 1024 {
+		t.Fatalf("Expected %d, got %d", 1024, v)
+	}
+	limits = GetCompilerLimits("pypy3", config, contest)
+	if v := limits.TimeLimit(1000); v != 2100 {
+		t.Fatalf("Expected %d, got %d", 2100, v)
+	}
+}
@@ -6,11 +6,94 @@ import (
 	"github.com/udovin/gosql"
 )
 
+// ContestStandingsKind represents kind of contest standings.
+type ContestStandingsKind string
+
+const (
+	// ICPCStandings represents standings where score is amount of
+	// accepted problems and ties are broken by penalty.
+	ICPCStandings ContestStandingsKind = "icpc"
+	// IOIStandings represents standings where score is sum of points
+	// and ties are broken by time of last improvement.
+	IOIStandings ContestStandingsKind = "ioi"
+)
+
+// IOIScoringRule represents rule for selecting points of problem in
+// IOI standings.
+type IOIScoringRule string
+
+const (
+	// BestIOIScoring means that best points of solutions are used.
+	BestIOIScoring IOIScoringRule = "best"
+	// LastIOIScoring means that points of last solution are used.
+	LastIOIScoring IOIScoringRule = "last"
+)
+
+// ContestStandingsCellKey represents key of standings cell.
+type ContestStandingsCellKey struct {
+	ParticipantID int64 `json:"participant_id"`
+	// ProblemID contains ID of contest problem.
+	ProblemID int64 `json:"problem_id"`
+}
+
 type ContestConfig struct {
 	BeginTime          NInt64 `json:"begin_time"`
 	Duration           int    `json:"duration"`
 	EnableRegistration bool   `json:"enable_registration"`
 	EnableUpsolving    bool   `json:"enable_upsolving"`
+	// CompilerLimits contains contest specific compiler limits.
+	//
+	// Contest overrides have higher priority than problem overrides.
+	CompilerLimits CompilerLimitsOverrides `json:"compiler_limits,omitempty"`
+	// EnablePretests enables judging on pretests during contest.
+	//
+	// Full judging of last solutions passed pretests is performed
+	// after contest is finished.
+	EnablePretests bool `json:"enable_pretests,omitempty"`
+	// SystemTestTime contains time when system testing was started.
+	SystemTestTime NInt64 `json:"system_test_time,omitempty"`
+	// StandingsKind contains kind of standings.
+	//
+	// Empty kind means ICPC standings.
+	StandingsKind ContestStandingsKind `json:"standings_kind,omitempty"`
+	// IOIScoring contains rule for IOI standings.
+	//
+	// Empty rule means best points.
+	IOIScoring IOIScoringRule `json:"ioi_scoring,omitempty"`
+	// FreezeBeginDuration contains duration in seconds from begin of
+	// contest after which standings are frozen.
+	//
+	// Zero duration means that standings are never frozen.
+	FreezeBeginDuration int `json:"freeze_begin_duration,omitempty"`
+	// Unfrozen means that all cells of standings are unfrozen.
+	Unfrozen bool `json:"unfrozen,omitempty"`
+	// UnfrozenCells contains cells that are unfrozen one by one.
+	UnfrozenCells []ContestStandingsCellKey `json:"unfrozen_cells,omitempty"`
+	// EnableVirtual means that users can start virtual participation
+	// after contest is finished.
+	EnableVirtual bool `json:"enable_virtual,omitempty"`
+	// PersonalDuration contains duration in seconds of personal timer
+	// of regular participants.
+	//
+	// Non-zero duration enables window mode, where contest can be
+	// started by participant at any time between begin and end of contest.
+	PersonalDuration int `json:"personal_duration,omitempty"`
+	// Template means that contest is used only as template for cloning
+	// and can not be started.
+	Template bool `json:"template,omitempty"`
+}
+
+// IsCellUnfrozen returns true if specified cell of standings is unfrozen.
+func (c ContestConfig) IsCellUnfrozen(key ContestStandingsCellKey) bool {
+	if c.Unfrozen {
+		return true
+	}
+	for _, cell := range c.UnfrozenCells {
+		if cell == key {
+			return true
+		}
+	}
+	return false
 }
 
 // Contest represents a contest.
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"github.com/udovin/gosql"
+)
+
+// ContestAnnouncement represents announcement of contest jury.
+type ContestAnnouncement struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// ProblemID contains ID of contest problem.
+	//
+	// Empty problem ID means general announcement.
+	ProblemID NInt64 `db:"problem_id"`
+	// CreateTime contains time of announcement.
+	CreateTime int64 `db:"create_time"`
+	// Text contains text of announcement.
+	Text string `db:"text"`
+}
+
+// Clone creates copy of contest announcement.
+func (o ContestAnnouncement) Clone() ContestAnnouncement {
+	return o
+}
+
+// ContestAnnouncementEvent represents contest announcement event.
+type ContestAnnouncementEvent struct {
+	baseEvent
+	ContestAnnouncement
+}
+
+// Object returns event contest announcement.
+func (e ContestAnnouncementEvent) Object() ContestAnnouncement {
+	return e.ContestAnnouncement
+}
+
+// SetObject sets event contest announcement.
+func (e *ContestAnnouncementEvent) SetObject(o ContestAnnouncement) {
+	e.ContestAnnouncement = o
+}
+
+// ContestAnnouncementStore represents store for contest announcements.
+type ContestAnnouncementStore struct {
+	baseStore[
+		ContestAnnouncement, ContestAnnouncementEvent,
+		*ContestAnnouncement, *ContestAnnouncementEvent,
+	]
+	byContest *index[int64, ContestAnnouncement, *ContestAnnouncement]
+}
+
+// FindByContest returns announcements by contest ID.
+func (s *ContestAnnouncementStore) FindByContest(
+	id int64,
+) ([]ContestAnnouncement, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []ContestAnnouncement
+	for id := range s.byContest.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
+var _ baseStoreImpl[ContestAnnouncement] = (*ContestAnnouncementStore)(nil)
+
+// NewContestAnnouncementStore creates a new instance of
+// ContestAnnouncementStore.
+func NewContestAnnouncementStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestAnnouncementStore {
+	impl := &ContestAnnouncementStore{
+		byContest: newIndex(func(o ContestAnnouncement) int64 {
+			return o.ContestID
+		}),
+	}
+	impl.baseStore = makeBaseStore[
+		ContestAnnouncement, ContestAnnouncementEvent,
+	](db, table, eventTable, impl, impl.byContest)
+	return impl
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type contestAnnouncementStoreTest struct{}
+
+func (t *contestAnnouncementStoreTest) prepareDB(tx *sql.Tx) error {
+	if _, err := tx.Exec(
+		`CREATE TABLE "contest_announcement" (` +
+			`"id" integer PRIMARY KEY,` +
+			`"contest_id" integer NOT NULL,` +
+			`"problem_id" integer,` +
+			`"create_time" bigint NOT NULL,` +
+			`"text" text NOT NULL)`,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		`CREATE TABLE "contest_announcement_event" (` +
+			`"event_id" integer PRIMARY KEY,` +
+			`"event_kind" int8 NOT NULL,` +
+			`"event_time" bigint NOT NULL,` +
+			`"event_account_id" integer NULL,` +
+			`"id" integer NOT NULL,` +
+			`"contest_id" integer NOT NULL,` +
+			`"problem_id" integer,` +
+			`"create_time" bigint NOT NULL,` +
+			`"text" text NOT NULL)`,
+	)
+	return err
+}
+
+func (t *contestAnnouncementStoreTest) newStore() Store {
+	return NewContestAnnouncementStore(
+		testDB, "contest_announcement", "contest_announcement_event",
+	)
+}
+
+func (t *contestAnnouncementStoreTest) newObject() object {
+	return ContestAnnouncement{}
+}
+
+func (t *contestAnnouncementStoreTest) createObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	announcement := o.(ContestAnnouncement)
+	if err := s.(*ContestAnnouncementStore).Create(
+		wrapContext(tx), &announcement,
+	); err != nil {
+		return ContestAnnouncement{}, err
+	}
+	return announcement, nil
+}
+
+func (t *contestAnnouncementStoreTest) updateObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	return o, s.(*ContestAnnouncementStore).Update(
+		wrapContext(tx), o.(ContestAnnouncement),
+	)
+}
+
+func (t *contestAnnouncementStoreTest) deleteObject(
+	s Store, tx *sql.Tx, id int64,
+) error {
+	return s.(*ContestAnnouncementStore).Delete(wrapContext(tx), id)
+}
+
+func TestContestAnnouncementStore(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	tester := StoreTester{&contestAnnouncementStoreTest{}}
+	tester.Test(t)
+}
+
+func TestNotificationKind(t *testing.T) {
+	for _, kind := range []NotificationKind{
+		AnnouncementNotification,
+		ClarificationNotification,
+		SolutionNotification,
+	} {
+		text, err := kind.MarshalText()
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		var parsed NotificationKind
+		if err := parsed.UnmarshalText(text); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if !reflect.DeepEqual(kind, parsed) {
+			t.Fatalf("Expected %v, got %v", kind, parsed)
+		}
+	}
+	var kind NotificationKind
+	if err := kind.UnmarshalText([]byte("unknown")); err == nil {
+		t.Fatal("Expected error")
+	}
+}
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/udovin/gosql"
+)
+
+// ClarificationKind represents visibility of clarification.
+type ClarificationKind int
+
+const (
+	// PrivateClarification is visible only for asker and jury.
+	PrivateClarification ClarificationKind = 1
+	// PublicClarification is visible for everyone who can observe contest.
+	PublicClarification ClarificationKind = 2
+)
+
+// String returns string representation.
+func (k ClarificationKind) String() string {
+	switch k {
+	case PrivateClarification:
+		return "private"
+	case PublicClarification:
+		return "public"
+	default:
+		return fmt.Sprintf("ClarificationKind(%d)", k)
+	}
+}
+
+// ContestClarification represents question of participant and
+// answer of jury.
+type ContestClarification struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// ParticipantID contains ID of participant that asked question.
+	//
+	// Clarifications created by jury have empty participant ID.
+	ParticipantID NInt64 `db:"participant_id"`
+	// ProblemID contains ID of contest problem.
+	//
+	// Empty problem ID means general question.
+	ProblemID NInt64 `db:"problem_id"`
+	// Kind contains visibility of clarification.
+	Kind ClarificationKind `db:"kind"`
+	// CreateTime contains time when question was asked.
+	CreateTime int64 `db:"create_time"`
+	// Question contains text of question.
+	Question string `db:"question"`
+	// Answer contains text of jury answer.
+	Answer NString `db:"answer"`
+	// AnswerTime contains time of last answer update.
+	AnswerTime NInt64 `db:"answer_time"`
+}
+
+// Clone creates copy of contest clarification.
+func (o ContestClarification) Clone() ContestClarification {
+	return o
+}
+
+// ContestClarificationEvent represents contest clarification event.
+type ContestClarificationEvent struct {
+	baseEvent
+	ContestClarification
+}
+
+// Object returns event contest clarification.
+func (e ContestClarificationEvent) Object() ContestClarification {
+	return e.ContestClarification
+}
+
+// SetObject sets event contest clarification.
+func (e *ContestClarificationEvent) SetObject(o ContestClarification) {
+	e.ContestClarification = o
+}
+
+// ContestClarificationStore represents store for contest clarifications.
+type ContestClarificationStore struct {
+	baseStore[
+		ContestClarification, ContestClarificationEvent,
+		*ContestClarification, *ContestClarificationEvent,
+	]
+	byContest *index[int64, ContestClarification, *ContestClarification]
+}
+
+// FindByContest returns clarifications by contest ID.
+func (s *ContestClarificationStore) FindByContest(
+	id int64,
+) ([]ContestClarification, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []ContestClarification
+	for id := range s.byContest.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
+var _ baseStoreImpl[ContestClarification] = (*ContestClarificationStore)(nil)
+
+// NewContestClarificationStore creates a new instance of
+// ContestClarificationStore.
+func NewContestClarificationStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestClarificationStore {
+	impl := &ContestClarificationStore{
+		byContest: newIndex(func(o ContestClarification) int64 {
+			return o.ContestID
+		}),
+	}
+	impl.baseStore = makeBaseStore[
+		ContestClarification, ContestClarificationEvent,
+	](db, table, eventTable, impl, impl.byContest)
+	return impl
+}
+
+// ContestClarificationRead represents last time when account has read
+// clarifications of contest.
+type ContestClarificationRead struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// AccountID contains ID of account.
+	AccountID int64 `db:"account_id"`
+	// ReadTime contains time of last read.
+	ReadTime int64 `db:"read_time"`
+}
+
+// Clone creates copy of contest clarification read.
+func (o ContestClarificationRead) Clone() ContestClarificationRead {
+	return o
+}
+
+// ContestClarificationReadEvent represents contest clarification read event.
+type ContestClarificationReadEvent struct {
+	baseEvent
+	ContestClarificationRead
+}
+
+// Object returns event contest clarification read.
+func (e ContestClarificationReadEvent) Object() ContestClarificationRead {
+	return e.ContestClarificationRead
+}
+
+// SetObject sets event contest clarification read.
+func (e *ContestClarificationReadEvent) SetObject(o ContestClarificationRead) {
+	e.ContestClarificationRead = o
+}
+
+// ContestClarificationReadStore represents store for contest
+// clarification reads.
+type ContestClarificationReadStore struct {
+	baseStore[
+		ContestClarificationRead, ContestClarificationReadEvent,
+		*ContestClarificationRead, *ContestClarificationReadEvent,
+	]
+	byContestAccount *index[pair[int64, int64], ContestClarificationRead, *ContestClarificationRead]
+}
+
+// GetByContestAccount returns clarification read by contest ID
+// and account ID.
+func (s *ContestClarificationReadStore) GetByContestAccount(
+	contestID, accountID int64,
+) (ContestClarificationRead, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	key := makePair(contestID, accountID)
+	for id := range s.byContestAccount.Get(key) {
+		if object, ok := s.objects[id]; ok {
+			return object.Clone(), nil
+		}
+	}
+	return ContestClarificationRead{}, sql.ErrNoRows
+}
+
+var _ baseStoreImpl[ContestClarificationRead] = (*ContestClarificationReadStore)(nil)
+
+// NewContestClarificationReadStore creates a new instance of
+// ContestClarificationReadStore.
+func NewContestClarificationReadStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestClarificationReadStore {
+	impl := &ContestClarificationReadStore{
+		byContestAccount: newIndex(func(o ContestClarificationRead) pair[int64, int64] {
+			return makePair(o.ContestID, o.AccountID)
+		}),
+	}
+	impl.baseStore = makeBaseStore[
+		ContestClarificationRead, ContestClarificationReadEvent,
+	](db, table, eventTable, impl, impl.byContestAccount)
+	return impl
+}
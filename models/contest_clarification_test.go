@@ -0,0 +1,100 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type contestClarificationStoreTest struct{}
+
+func (t *contestClarificationStoreTest) prepareDB(tx *sql.Tx) error {
+	if _, err := tx.Exec(
+		`CREATE TABLE "contest_clarification" (` +
+			`"id" integer PRIMARY KEY,` +
+			`"contest_id" integer NOT NULL,` +
+			`"participant_id" integer,` +
+			`"problem_id" integer,` +
+			`"kind" integer NOT NULL,` +
+			`"create_time" bigint NOT NULL,` +
+			`"question" text NOT NULL,` +
+			`"answer" text,` +
+			`"answer_time" bigint)`,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		`CREATE TABLE "contest_clarification_event" (` +
+			`"event_id" integer PRIMARY KEY,` +
+			`"event_kind" int8 NOT NULL,` +
+			`"event_time" bigint NOT NULL,` +
+			`"event_account_id" integer NULL,` +
+			`"id" integer NOT NULL,` +
+			`"contest_id" integer NOT NULL,` +
+			`"participant_id" integer,` +
+			`"problem_id" integer,` +
+			`"kind" integer NOT NULL,` +
+			`"create_time" bigint NOT NULL,` +
+			`"question" text NOT NULL,` +
+			`"answer" text,` +
+			`"answer_time" bigint)`,
+	)
+	return err
+}
+
+func (t *contestClarificationStoreTest) newStore() Store {
+	return NewContestClarificationStore(
+		testDB, "contest_clarification", "contest_clarification_event",
+	)
+}
+
+func (t *contestClarificationStoreTest) newObject() object {
+	return ContestClarification{Kind: PrivateClarification}
+}
+
+func (t *contestClarificationStoreTest) createObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	clarification := o.(ContestClarification)
+	if err := s.(*ContestClarificationStore).Create(
+		wrapContext(tx), &clarification,
+	); err != nil {
+		return ContestClarification{}, err
+	}
+	return clarification, nil
+}
+
+func (t *contestClarificationStoreTest) updateObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	return o, s.(*ContestClarificationStore).Update(
+		wrapContext(tx), o.(ContestClarification),
+	)
+}
+
+func (t *contestClarificationStoreTest) deleteObject(
+	s Store, tx *sql.Tx, id int64,
+) error {
+	return s.(*ContestClarificationStore).Delete(wrapContext(tx), id)
+}
+
+func TestContestClarificationStore(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	tester := StoreTester{&contestClarificationStoreTest{}}
+	tester.Test(t)
+}
+
+func TestContestClarificationClone(t *testing.T) {
+	clarification := ContestClarification{
+		ContestID: 1,
+		Kind:      PublicClarification,
+		Question:  "Question",
+		Answer:    "Answer",
+	}
+	clarification.ID = 12345
+	clone := clarification.Clone()
+	if !reflect.DeepEqual(clarification, clone) {
+		t.Fatalf("Clarification clone is invalid, %v != %v", clarification, clone)
+	}
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/udovin/gosql"
+)
+
+// NotificationKind represents kind of contest notification.
+type NotificationKind int
+
+const (
+	// AnnouncementNotification represents notification about
+	// new announcement.
+	AnnouncementNotification NotificationKind = 1
+	// ClarificationNotification represents notification about
+	// answer to clarification.
+	ClarificationNotification NotificationKind = 2
+	// SolutionNotification represents notification about
+	// finished verdict of solution.
+	SolutionNotification NotificationKind = 3
+)
+
+// String returns string representation.
+func (k NotificationKind) String() string {
+	switch k {
+	case AnnouncementNotification:
+		return "announcement"
+	case ClarificationNotification:
+		return "clarification"
+	case SolutionNotification:
+		return "solution"
+	default:
+		return fmt.Sprintf("NotificationKind(%d)", k)
+	}
+}
+
+func (k NotificationKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *NotificationKind) UnmarshalText(data []byte) error {
+	switch s := string(data); s {
+	case "announcement":
+		*k = AnnouncementNotification
+	case "clarification":
+		*k = ClarificationNotification
+	case "solution":
+		*k = SolutionNotification
+	default:
+		return fmt.Errorf("unsupported kind: %q", s)
+	}
+	return nil
+}
+
+// ContestNotification represents notification for contest participants.
+type ContestNotification struct {
+	baseObject
+	// ContestID contains ID of contest.
+	ContestID int64 `db:"contest_id"`
+	// ParticipantID contains ID of notified participant.
+	//
+	// Empty participant ID means notification for everyone.
+	ParticipantID NInt64 `db:"participant_id"`
+	// Kind contains kind of notification.
+	Kind NotificationKind `db:"kind"`
+	// TargetID contains ID of announcement, clarification or
+	// contest solution depending on kind.
+	TargetID int64 `db:"target_id"`
+	// CreateTime contains time of notification.
+	CreateTime int64 `db:"create_time"`
+}
+
+// Clone creates copy of contest notification.
+func (o ContestNotification) Clone() ContestNotification {
+	return o
+}
+
+// ContestNotificationEvent represents contest notification event.
+type ContestNotificationEvent struct {
+	baseEvent
+	ContestNotification
+}
+
+// Object returns event contest notification.
+func (e ContestNotificationEvent) Object() ContestNotification {
+	return e.ContestNotification
+}
+
+// SetObject sets event contest notification.
+func (e *ContestNotificationEvent) SetObject(o ContestNotification) {
+	e.ContestNotification = o
+}
+
+// ContestNotificationStore represents store for contest notifications.
+type ContestNotificationStore struct {
+	baseStore[
+		ContestNotification, ContestNotificationEvent,
+		*ContestNotification, *ContestNotificationEvent,
+	]
+	byContest *index[int64, ContestNotification, *ContestNotification]
+}
+
+// FindByContest returns notifications by contest ID.
+func (s *ContestNotificationStore) FindByContest(
+	id int64,
+) ([]ContestNotification, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []ContestNotification
+	for id := range s.byContest.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
+var _ baseStoreImpl[ContestNotification] = (*ContestNotificationStore)(nil)
+
+// NewContestNotificationStore creates a new instance of
+// ContestNotificationStore.
+func NewContestNotificationStore(
+	db *gosql.DB, table, eventTable string,
+) *ContestNotificationStore {
+	impl := &ContestNotificationStore{
+		byContest: newIndex(func(o ContestNotification) int64 {
+			return o.ContestID
+		}),
+	}
+	impl.baseStore = makeBaseStore[
+		ContestNotification, ContestNotificationEvent,
+	](db, table, eventTable, impl, impl.byContest)
+	return impl
+}
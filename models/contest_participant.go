@@ -13,6 +13,9 @@ const (
 	RegularParticipant   ParticipantKind = 1
 	UpsolvingParticipant ParticipantKind = 2
 	ManagerParticipant   ParticipantKind = 3
+	// VirtualParticipant represents participant that takes part in
+	// finished contest with its own begin time.
+	VirtualParticipant ParticipantKind = 4
 )
 
 // String returns string representation.
@@ -24,6 +27,8 @@ func (k ParticipantKind) String() string {
 		return "upsolving"
 	case ManagerParticipant:
 		return "manager"
+	case VirtualParticipant:
+		return "virtual"
 	default:
 		return fmt.Sprintf("ParticipantKind(%d)", k)
 	}
@@ -41,12 +46,16 @@ func (k *ParticipantKind) UnmarshalText(data []byte) error {
 		*k = UpsolvingParticipant
 	case "manager":
 		*k = ManagerParticipant
+	case "virtual":
+		*k = VirtualParticipant
 	default:
 		return fmt.Errorf("unsupported kind: %q", s)
 	}
 	return nil
 }
 
+// RegularParticipantConfig represents config of regular and virtual
+// participants.
 type RegularParticipantConfig struct {
 	BeginTime NInt64 `json:"begin_time,omitempty"`
 }
@@ -8,6 +8,10 @@ import (
 
 type ContestProblemConfig struct {
 	Points *int `json:"points,omitempty"`
+	// RevisionID contains ID of pinned problem revision.
+	//
+	// Zero value means that current problem revision is used.
+	RevisionID int64 `json:"revision_id,omitempty"`
 }
 
 // ContestProblem represents connection for problems.
@@ -43,6 +43,7 @@ type ContestSolutionStore struct {
 	baseStore[ContestSolution, ContestSolutionEvent, *ContestSolution, *ContestSolutionEvent]
 	byContest     *index[int64, ContestSolution, *ContestSolution]
 	byParticipant *index[int64, ContestSolution, *ContestSolution]
+	bySolution    *index[int64, ContestSolution, *ContestSolution]
 }
 
 // FindByContest returns solutions by contest ID.
@@ -75,6 +76,21 @@ func (s *ContestSolutionStore) FindByParticipant(
 	return objects, nil
 }
 
+// FindBySolution returns contest solutions by solution ID.
+func (s *ContestSolutionStore) FindBySolution(
+	id int64,
+) ([]ContestSolution, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []ContestSolution
+	for id := range s.bySolution.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
 var _ baseStoreImpl[ContestSolution] = (*ContestSolutionStore)(nil)
 
 // NewContestSolutionStore creates a new instance of ContestSolutionStore.
@@ -84,9 +100,11 @@ func NewContestSolutionStore(
 	impl := &ContestSolutionStore{
 		byContest:     newIndex(func(o ContestSolution) int64 { return o.ContestID }),
 		byParticipant: newIndex(func(o ContestSolution) int64 { return o.ParticipantID }),
+		bySolution:    newIndex(func(o ContestSolution) int64 { return o.SolutionID }),
 	}
 	impl.baseStore = makeBaseStore[ContestSolution, ContestSolutionEvent](
 		db, table, eventTable, impl, impl.byContest, impl.byParticipant,
+		impl.bySolution,
 	)
 	return impl
 }
@@ -6,9 +6,88 @@ import (
 	"github.com/udovin/gosql"
 )
 
+// ProblemPackageKind represents kind of problem package.
+type ProblemPackageKind string
+
+const (
+	// PolygonProblemPackage represents package in Polygon format.
+	PolygonProblemPackage ProblemPackageKind = "polygon"
+	// ICPCProblemPackage represents package in ICPC (Kattis) format.
+	ICPCProblemPackage ProblemPackageKind = "icpc"
+)
+
+// ProblemPretestsConfig represents tests that are used as pretests.
+type ProblemPretestsConfig struct {
+	// Groups contains names of test groups that are pretests.
+	Groups []string `json:"groups,omitempty"`
+	// Count contains amount of first tests that are pretests.
+	Count int `json:"count,omitempty"`
+}
+
+// IsPretest returns true if test with specified group and number is
+// pretest. Number of test is zero-based.
+func (c ProblemPretestsConfig) IsPretest(group string, number int) bool {
+	if number < c.Count {
+		return true
+	}
+	for _, name := range c.Groups {
+		if name == group {
+			return true
+		}
+	}
+	return false
+}
+
+// ProblemCalibrationGroup represents calibration result of test group.
+type ProblemCalibrationGroup struct {
+	Name string `json:"name"`
+	// TimeLimit contains time limit of group from package.
+	TimeLimit int64 `json:"time_limit"`
+	// MaxTime contains max time used by model solutions on group.
+	MaxTime int64 `json:"max_time"`
+	// SuggestedTimeLimit contains suggested time limit of group.
+	SuggestedTimeLimit int64 `json:"suggested_time_limit,omitempty"`
+}
+
+// ProblemCalibrationSolution represents calibration result of model
+// solution.
+type ProblemCalibrationSolution struct {
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	MaxTime int64  `json:"max_time,omitempty"`
+	// Slow means that solution uses more than time limit divided by
+	// time multiplier on some group.
+	Slow  bool   `json:"slow,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// ProblemCalibration represents result of time limit calibration.
+type ProblemCalibration struct {
+	CreateTime int64 `json:"create_time"`
+	// TimeLimit contains suggested time limit.
+	TimeLimit int64                        `json:"time_limit"`
+	Groups    []ProblemCalibrationGroup    `json:"groups,omitempty"`
+	Solutions []ProblemCalibrationSolution `json:"solutions,omitempty"`
+}
+
 type ProblemConfig struct {
 	TimeLimit   int64 `json:"time_limit,omitempty"`
 	MemoryLimit int64 `json:"memory_limit,omitempty"`
+	// PackageKind contains kind of problem package.
+	//
+	// Empty kind means Polygon package.
+	PackageKind ProblemPackageKind `json:"package_kind,omitempty"`
+	// RevisionID contains ID of current problem revision.
+	RevisionID int64 `json:"revision_id,omitempty"`
+	// CompilerLimits contains problem specific compiler limits.
+	CompilerLimits CompilerLimitsOverrides `json:"compiler_limits,omitempty"`
+	// Pretests contains tests used for judging during contest.
+	Pretests *ProblemPretestsConfig `json:"pretests,omitempty"`
+	// TimeLimitOverrides contains time limits that override time limits
+	// of test groups from package by names of groups.
+	TimeLimitOverrides map[string]int64 `json:"time_limit_overrides,omitempty"`
+	// Calibration contains result of last time limit calibration.
+	Calibration *ProblemCalibration `json:"calibration,omitempty"`
 }
 
 // Problem represents a problem.
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+
+	"github.com/udovin/gosql"
+)
+
+// ProblemRevisionConfig represents config of problem revision.
+type ProblemRevisionConfig struct {
+	// Changelog contains description of revision changes.
+	Changelog   string             `json:"changelog,omitempty"`
+	PackageKind ProblemPackageKind `json:"package_kind,omitempty"`
+	TimeLimit   int64              `json:"time_limit,omitempty"`
+	MemoryLimit int64              `json:"memory_limit,omitempty"`
+	// TestCount contains amount of tests in compiled package.
+	TestCount int `json:"test_count,omitempty"`
+	// CompilerLimits, Pretests and TimeLimitOverrides contain settings
+	// of problem that are used for judging, so contests pinned to
+	// revision are judged with settings of this revision.
+	CompilerLimits     CompilerLimitsOverrides `json:"compiler_limits,omitempty"`
+	Pretests           *ProblemPretestsConfig  `json:"pretests,omitempty"`
+	TimeLimitOverrides map[string]int64        `json:"time_limit_overrides,omitempty"`
+}
+
+// SetJudgeSettings copies settings of problem that are used for judging.
+func (c *ProblemRevisionConfig) SetJudgeSettings(config ProblemConfig) {
+	c.CompilerLimits = config.CompilerLimits
+	c.Pretests = config.Pretests
+	c.TimeLimitOverrides = config.TimeLimitOverrides
+}
+
+// GetProblemConfig returns config of problem with package and judging
+// settings of revision.
+func (c ProblemRevisionConfig) GetProblemConfig(
+	config ProblemConfig,
+) ProblemConfig {
+	config.PackageKind = c.PackageKind
+	config.TimeLimit = c.TimeLimit
+	config.MemoryLimit = c.MemoryLimit
+	config.CompilerLimits = c.CompilerLimits
+	config.Pretests = c.Pretests
+	config.TimeLimitOverrides = c.TimeLimitOverrides
+	return config
+}
+
+// ProblemRevision represents a revision of problem package.
+type ProblemRevision struct {
+	baseObject
+	ProblemID  int64  `db:"problem_id"`
+	AuthorID   NInt64 `db:"author_id"`
+	CreateTime int64  `db:"create_time"`
+	Config     JSON   `db:"config"`
+	PackageID  int64  `db:"package_id"`
+	CompiledID NInt64 `db:"compiled_id"`
+}
+
+func (o ProblemRevision) GetConfig() (ProblemRevisionConfig, error) {
+	var config ProblemRevisionConfig
+	if len(o.Config) == 0 {
+		return config, nil
+	}
+	err := json.Unmarshal(o.Config, &config)
+	return config, err
+}
+
+func (o *ProblemRevision) SetConfig(config ProblemRevisionConfig) error {
+	raw, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	o.Config = raw
+	return nil
+}
+
+// Clone creates copy of problem revision.
+func (o ProblemRevision) Clone() ProblemRevision {
+	o.Config = o.Config.Clone()
+	return o
+}
+
+// ProblemRevisionEvent represents a problem revision event.
+type ProblemRevisionEvent struct {
+	baseEvent
+	ProblemRevision
+}
+
+// Object returns event problem revision.
+func (e ProblemRevisionEvent) Object() ProblemRevision {
+	return e.ProblemRevision
+}
+
+// SetObject sets event problem revision.
+func (e *ProblemRevisionEvent) SetObject(o ProblemRevision) {
+	e.ProblemRevision = o
+}
+
+// ProblemRevisionStore represents store for problem revisions.
+type ProblemRevisionStore struct {
+	baseStore[ProblemRevision, ProblemRevisionEvent, *ProblemRevision, *ProblemRevisionEvent]
+	byProblem *index[int64, ProblemRevision, *ProblemRevision]
+}
+
+// FindByProblem returns revisions of specified problem.
+func (s *ProblemRevisionStore) FindByProblem(id int64) ([]ProblemRevision, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []ProblemRevision
+	for id := range s.byProblem.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
+var _ baseStoreImpl[ProblemRevision] = (*ProblemRevisionStore)(nil)
+
+// NewProblemRevisionStore creates a new instance of ProblemRevisionStore.
+func NewProblemRevisionStore(
+	db *gosql.DB, table, eventTable string,
+) *ProblemRevisionStore {
+	impl := &ProblemRevisionStore{
+		byProblem: newIndex(func(o ProblemRevision) int64 { return o.ProblemID }),
+	}
+	impl.baseStore = makeBaseStore[ProblemRevision, ProblemRevisionEvent](
+		db, table, eventTable, impl, impl.byProblem,
+	)
+	return impl
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type problemRevisionStoreTest struct{}
+
+func (t *problemRevisionStoreTest) prepareDB(tx *sql.Tx) error {
+	if _, err := tx.Exec(
+		`CREATE TABLE "problem_revision" (` +
+			`"id" integer PRIMARY KEY,` +
+			`"problem_id" integer NOT NULL,` +
+			`"author_id" integer,` +
+			`"create_time" bigint NOT NULL,` +
+			`"config" text NOT NULL,` +
+			`"package_id" integer NOT NULL,` +
+			`"compiled_id" integer)`,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		`CREATE TABLE "problem_revision_event" (` +
+			`"event_id" integer PRIMARY KEY,` +
+			`"event_kind" int8 NOT NULL,` +
+			`"event_time" bigint NOT NULL,` +
+			`"event_account_id" integer NULL,` +
+			`"id" integer NOT NULL,` +
+			`"problem_id" integer NOT NULL,` +
+			`"author_id" integer,` +
+			`"create_time" bigint NOT NULL,` +
+			`"config" text NOT NULL,` +
+			`"package_id" integer NOT NULL,` +
+			`"compiled_id" integer)`,
+	)
+	return err
+}
+
+func (t *problemRevisionStoreTest) newStore() Store {
+	return NewProblemRevisionStore(
+		testDB, "problem_revision", "problem_revision_event",
+	)
+}
+
+func (t *problemRevisionStoreTest) newObject() object {
+	return ProblemRevision{Config: JSON("{}")}
+}
+
+func (t *problemRevisionStoreTest) createObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	revision := o.(ProblemRevision)
+	if err := s.(*ProblemRevisionStore).Create(
+		wrapContext(tx), &revision,
+	); err != nil {
+		return ProblemRevision{}, err
+	}
+	return revision, nil
+}
+
+func (t *problemRevisionStoreTest) updateObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	return o, s.(*ProblemRevisionStore).Update(
+		wrapContext(tx), o.(ProblemRevision),
+	)
+}
+
+func (t *problemRevisionStoreTest) deleteObject(
+	s Store, tx *sql.Tx, id int64,
+) error {
+	return s.(*ProblemRevisionStore).Delete(wrapContext(tx), id)
+}
+
+func TestProblemRevisionStore(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	tester := StoreTester{&problemRevisionStoreTest{}}
+	tester.Test(t)
+}
+
+func TestProblemRevisionClone(t *testing.T) {
+	revision := ProblemRevision{Config: JSON("{}")}
+	revision.ID = 12345
+	clone := revision.Clone()
+	if !reflect.DeepEqual(revision, clone) {
+		t.Fatalf("Problem revision clone is invalid, %v != %v", revision, clone)
+	}
+}
+
+func TestProblemRevisionJudgeSettings(t *testing.T) {
+	problemConfig := ProblemConfig{
+		TimeLimit:  2000,
+		RevisionID: 2,
+		CompilerLimits: CompilerLimitsOverrides{
+			"python": {TimeMultiplier: 3},
+		},
+		Pretests:           &ProblemPretestsConfig{Count: 2},
+		TimeLimitOverrides: map[string]int64{"tests": 1500},
+	}
+	config := ProblemRevisionConfig{TimeLimit: 1000}
+	config.SetJudgeSettings(problemConfig)
+	current := ProblemConfig{TimeLimit: 3000, RevisionID: 3}
+	pinned := config.GetProblemConfig(current)
+	expected := ProblemConfig{
+		TimeLimit:          1000,
+		RevisionID:         3,
+		CompilerLimits:     problemConfig.CompilerLimits,
+		Pretests:           problemConfig.Pretests,
+		TimeLimitOverrides: problemConfig.TimeLimitOverrides,
+	}
+	if !reflect.DeepEqual(pinned, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, pinned)
+	}
+}
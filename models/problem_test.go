@@ -84,3 +84,22 @@ func TestProblemClone(t *testing.T) {
 		t.Fatalf("Problem clone is invalid, %v != %v", problem, clone)
 	}
 }
+
+func TestProblemPretestsConfig(t *testing.T) {
+	config := ProblemPretestsConfig{Groups: []string{"samples"}, Count: 2}
+	for _, test := range []struct {
+		Group    string
+		Number   int
+		Expected bool
+	}{
+		{"", 0, true},
+		{"", 1, true},
+		{"", 2, false},
+		{"samples", 5, true},
+		{"main", 5, false},
+	} {
+		if v := config.IsPretest(test.Group, test.Number); v != test.Expected {
+			t.Fatalf("Expected %v for test (%q, %d), got %v", test.Expected, test.Group, test.Number, v)
+		}
+	}
+}
@@ -164,6 +164,18 @@ const (
 	// DeleteContestSolutionRole represents role for deleting
 	// contest solution.
 	DeleteContestSolutionRole = "delete_contest_solution"
+	// ObserveContestClarificationsRole represents role for observing
+	// contest clarification list.
+	ObserveContestClarificationsRole = "observe_contest_clarifications"
+	// CreateContestClarificationRole represents role for creating
+	// contest clarification.
+	CreateContestClarificationRole = "create_contest_clarification"
+	// UpdateContestClarificationRole represents role for answering
+	// contest clarification.
+	UpdateContestClarificationRole = "update_contest_clarification"
+	// CreateContestAnnouncementRole represents role for creating
+	// contest announcement.
+	CreateContestAnnouncementRole = "create_contest_announcement"
 	//
 	ObserveContestStandingsRole = "observe_contest_standings"
 	//
@@ -180,6 +192,22 @@ const (
 	RegisterContestRole = "register_contest"
 	// DeregisterContestRole represents role for deregister from contest.
 	DeregisterContestRole = "deregister_contest"
+	// StartVirtualContestRole represents role for starting virtual
+	// participation in contest.
+	StartVirtualContestRole = "start_virtual_contest"
+	// StartContestRole represents role for starting personal timer in
+	// windowed contest.
+	StartContestRole = "start_contest"
+	// ObserveTeamsRole represents role for observing teams.
+	ObserveTeamsRole = "observe_teams"
+	// ObserveTeamRole represents role for observing team.
+	ObserveTeamRole = "observe_team"
+	// CreateTeamRole represents role for creating team.
+	CreateTeamRole = "create_team"
+	// UpdateTeamRole represents role for updating team and its members.
+	UpdateTeamRole = "update_team"
+	// DeleteTeamRole represents role for deleting team.
+	DeleteTeamRole = "delete_team"
 	// ObserveFileContentRole represents role for observing file content.
 	ObserveFileContentRole = "observe_file_content"
 	//
@@ -256,6 +284,10 @@ var builtInRoles = map[string]struct{}{
 	SubmitContestSolutionRole:        {},
 	UpdateContestSolutionRole:        {},
 	DeleteContestSolutionRole:        {},
+	ObserveContestClarificationsRole: {},
+	CreateContestClarificationRole:   {},
+	UpdateContestClarificationRole:   {},
+	CreateContestAnnouncementRole:    {},
 	ObserveContestStandingsRole:      {},
 	ObserveContestFullStandingsRole:  {},
 	ObserveContestsRole:              {},
@@ -266,6 +298,13 @@ var builtInRoles = map[string]struct{}{
 	RegisterContestsRole:             {},
 	RegisterContestRole:              {},
 	DeregisterContestRole:            {},
+	StartVirtualContestRole:          {},
+	StartContestRole:                 {},
+	ObserveTeamsRole:                 {},
+	ObserveTeamRole:                  {},
+	CreateTeamRole:                   {},
+	UpdateTeamRole:                   {},
+	DeleteTeamRole:                   {},
 	ObserveFileContentRole:           {},
 	ObserveScopesRole:                {},
 	ObserveScopeRole:                 {},
@@ -30,6 +30,8 @@ const (
 	PartiallyAccepted Verdict = 9
 	// Failed means that solution checker is failed.
 	Failed Verdict = 10
+	// SecurityViolation means that solution violates sandbox restrictions.
+	SecurityViolation Verdict = 11
 )
 
 func (v Verdict) String() string {
@@ -54,6 +56,8 @@ func (v Verdict) String() string {
 		return "partially_accepted"
 	case Failed:
 		return "failed"
+	case SecurityViolation:
+		return "security_violation"
 	default:
 		return fmt.Sprintf("Verdict(%d)", v)
 	}
@@ -85,6 +89,8 @@ func (v *Verdict) UnmarshalText(data []byte) error {
 		*v = PartiallyAccepted
 	case "failed":
 		*v = Failed
+	case "security_violation":
+		*v = SecurityViolation
 	default:
 		return fmt.Errorf("unsupported kind: %q", s)
 	}
@@ -92,8 +98,14 @@ func (v *Verdict) UnmarshalText(data []byte) error {
 }
 
 type UsageReport struct {
-	Time   int64 `json:"time,omitempty"`
-	Memory int64 `json:"memory,omitempty"`
+	Time       int64  `json:"time,omitempty"`
+	Memory     int64  `json:"memory,omitempty"`
+	UserTime   int64  `json:"user_time,omitempty"`
+	SystemTime int64  `json:"system_time,omitempty"`
+	WallTime   int64  `json:"wall_time,omitempty"`
+	PeakMemory int64  `json:"peak_memory,omitempty"`
+	Signal     string `json:"signal,omitempty"`
+	OOMKilled  bool   `json:"oom_killed,omitempty"`
 }
 
 type CompileReport struct {
@@ -106,6 +118,27 @@ type CheckReport struct {
 	Log   string      `json:"log"`
 }
 
+// TestArtifacts contains files with full artifacts of test.
+type TestArtifacts struct {
+	// OutputID contains ID of file with solution output.
+	OutputID int64 `json:"output_id,omitempty"`
+	// StderrID contains ID of file with solution stderr.
+	StderrID int64 `json:"stderr_id,omitempty"`
+	// CheckLogID contains ID of file with checker log.
+	CheckLogID int64 `json:"check_log_id,omitempty"`
+}
+
+// FileIDs returns IDs of all artifact files.
+func (a TestArtifacts) FileIDs() []int64 {
+	var ids []int64
+	for _, id := range []int64{a.OutputID, a.StderrID, a.CheckLogID} {
+		if id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 type TestReport struct {
 	Verdict Verdict     `json:"verdict"`
 	Usage   UsageReport `json:"usage"`
@@ -113,6 +146,12 @@ type TestReport struct {
 	Points  *float64    `json:"points,omitempty"`
 	Input   string      `json:"input,omitempty"`
 	Output  string      `json:"output,omitempty"`
+	// TimeLimit contains effective time limit in milliseconds.
+	TimeLimit int64 `json:"time_limit,omitempty"`
+	// MemoryLimit contains effective memory limit in bytes.
+	MemoryLimit int64 `json:"memory_limit,omitempty"`
+	// Artifacts contains full artifacts of test.
+	Artifacts *TestArtifacts `json:"artifacts,omitempty"`
 }
 
 type SolutionReport struct {
@@ -121,6 +160,10 @@ type SolutionReport struct {
 	Compile CompileReport `json:"compile"`
 	Tests   []TestReport  `json:"tests,omitempty"`
 	Points  *float64      `json:"points,omitempty"`
+	// Pretests means that solution was judged only on pretests.
+	Pretests bool `json:"pretests,omitempty"`
+	// ArtifactsExpireTime contains time when test artifacts expire.
+	ArtifactsExpireTime int64 `json:"artifacts_expire_time,omitempty"`
 }
 
 // Solution represents a solution.
@@ -54,6 +54,12 @@ const (
 	JudgeSolutionTask TaskKind = 1
 	// UpdateProblemPackageTask represents task for update problem package.
 	UpdateProblemPackageTask TaskKind = 2
+	// CheckCompilerTask represents task for compiler self-test.
+	CheckCompilerTask TaskKind = 3
+	// CalibrateProblemTask represents task for time limit calibration.
+	CalibrateProblemTask TaskKind = 4
+	// StressSolutionTask represents task for stress testing of solution.
+	StressSolutionTask TaskKind = 5
 )
 
 // String returns string representation.
@@ -63,6 +69,12 @@ func (t TaskKind) String() string {
 		return "judge_solution"
 	case UpdateProblemPackageTask:
 		return "update_problem_package"
+	case CheckCompilerTask:
+		return "check_compiler"
+	case CalibrateProblemTask:
+		return "calibrate_problem"
+	case StressSolutionTask:
+		return "stress_solution"
 	default:
 		return fmt.Sprintf("TaskKind(%d)", t)
 	}
@@ -76,6 +88,10 @@ func (t TaskKind) MarshalText() ([]byte, error) {
 // JudgeSolutionTaskConfig represets config for JudgeSolution.
 type JudgeSolutionTaskConfig struct {
 	SolutionID int64 `json:"solution_id"`
+	// RevisionID contains ID of problem revision used for judging.
+	RevisionID int64 `json:"revision_id,omitempty"`
+	// Pretests enables judging only on pretests.
+	Pretests bool `json:"pretests,omitempty"`
 }
 
 func (c JudgeSolutionTaskConfig) TaskKind() TaskKind {
@@ -88,15 +104,78 @@ type JudgeSolutionTaskState struct {
 
 // UpdateProblemPackageTaskConfig represets config for JudgeSolution.
 type UpdateProblemPackageTaskConfig struct {
-	ProblemID int64 `json:"problem_id"`
-	FileID    int64 `json:"file_id"`
-	Compile   bool  `json:"compile"`
+	ProblemID   int64              `json:"problem_id"`
+	FileID      int64              `json:"file_id"`
+	Compile     bool               `json:"compile"`
+	PackageKind ProblemPackageKind `json:"package_kind,omitempty"`
+	RevisionID  int64              `json:"revision_id,omitempty"`
 }
 
 func (c UpdateProblemPackageTaskConfig) TaskKind() TaskKind {
 	return UpdateProblemPackageTask
 }
 
+// CheckCompilerTaskConfig represents config for CheckCompiler.
+type CheckCompilerTaskConfig struct {
+	CompilerID int64 `json:"compiler_id"`
+}
+
+func (c CheckCompilerTaskConfig) TaskKind() TaskKind {
+	return CheckCompilerTask
+}
+
+// CalibrateProblemTaskConfig represents config for CalibrateProblem.
+type CalibrateProblemTaskConfig struct {
+	ProblemID int64 `json:"problem_id"`
+	// Runs contains amount of runs of every solution on every test.
+	Runs int `json:"runs,omitempty"`
+	// TimeMultiplier contains multiplier for max time of solutions.
+	TimeMultiplier float64 `json:"time_multiplier,omitempty"`
+	// TimeRounding contains rounding of suggested time limit.
+	TimeRounding int64 `json:"time_rounding,omitempty"`
+}
+
+func (c CalibrateProblemTaskConfig) TaskKind() TaskKind {
+	return CalibrateProblemTask
+}
+
+// StressSolutionTaskConfig represents config for StressSolution.
+type StressSolutionTaskConfig struct {
+	SolutionID int64 `json:"solution_id"`
+	// Generator contains generator name with arguments.
+	//
+	// Iteration number is appended to arguments as random seed.
+	Generator string `json:"generator"`
+	// Iterations contains max amount of generated tests.
+	Iterations int `json:"iterations,omitempty"`
+	// TimeBudget contains max duration of stress testing in seconds.
+	TimeBudget int64 `json:"time_budget,omitempty"`
+}
+
+func (c StressSolutionTaskConfig) TaskKind() TaskKind {
+	return StressSolutionTask
+}
+
+// StressCounterexample represents test on which solution fails.
+type StressCounterexample struct {
+	// Generator contains generator command for test.
+	Generator string  `json:"generator"`
+	Verdict   Verdict `json:"verdict"`
+	Input     string  `json:"input"`
+	Output    string  `json:"output,omitempty"`
+	Answer    string  `json:"answer,omitempty"`
+	CheckLog  string  `json:"check_log,omitempty"`
+	// InputSize contains full size of input in bytes.
+	InputSize int64 `json:"input_size"`
+}
+
+// StressSolutionTaskState represents state of StressSolution.
+type StressSolutionTaskState struct {
+	Iterations int `json:"iterations"`
+	// Counterexample contains minimal found failing test.
+	Counterexample *StressCounterexample `json:"counterexample,omitempty"`
+}
+
 type TaskConfig interface {
 	TaskKind() TaskKind
 }
@@ -241,6 +320,11 @@ func NewTaskStore(
 				if err := o.ScanConfig(&config); err == nil {
 					return config.SolutionID
 				}
+			case StressSolutionTask:
+				var config StressSolutionTaskConfig
+				if err := o.ScanConfig(&config); err == nil {
+					return config.SolutionID
+				}
 			}
 			return 0
 		}),
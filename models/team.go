@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/udovin/gosql"
+)
+
+// Team represents a team of users that takes part in contests using
+// shared team account.
+type Team struct {
+	baseObject
+	// AccountID contains ID of team account.
+	AccountID int64  `db:"account_id"`
+	OwnerID   NInt64 `db:"owner_id"`
+	Title     string `db:"title"`
+}
+
+// AccountKind returns TeamAccount kind.
+func (o Team) AccountKind() AccountKind {
+	return TeamAccount
+}
+
+// Clone creates copy of team.
+func (o Team) Clone() Team {
+	return o
+}
+
+// TeamEvent represents a team event.
+type TeamEvent struct {
+	baseEvent
+	Team
+}
+
+// Object returns event team.
+func (e TeamEvent) Object() Team {
+	return e.Team
+}
+
+// SetObject sets event team.
+func (e *TeamEvent) SetObject(o Team) {
+	e.Team = o
+}
+
+// TeamStore represents store for teams.
+type TeamStore struct {
+	baseStore[Team, TeamEvent, *Team, *TeamEvent]
+	byAccount *index[int64, Team, *Team]
+}
+
+// GetByAccount returns team by account id.
+func (s *TeamStore) GetByAccount(id int64) (Team, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for id := range s.byAccount.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			return object.Clone(), nil
+		}
+	}
+	return Team{}, sql.ErrNoRows
+}
+
+var _ baseStoreImpl[Team] = (*TeamStore)(nil)
+
+// NewTeamStore creates a new instance of TeamStore.
+func NewTeamStore(
+	db *gosql.DB, table, eventTable string,
+) *TeamStore {
+	impl := &TeamStore{
+		byAccount: newIndex(func(o Team) int64 { return o.AccountID }),
+	}
+	impl.baseStore = makeBaseStore[Team, TeamEvent](
+		db, table, eventTable, impl, impl.byAccount,
+	)
+	return impl
+}
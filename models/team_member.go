@@ -0,0 +1,83 @@
+package models
+
+import (
+	"github.com/udovin/gosql"
+)
+
+// TeamMember represents a member of team.
+type TeamMember struct {
+	baseObject
+	TeamID int64 `db:"team_id"`
+	// AccountID contains ID of member account.
+	AccountID int64 `db:"account_id"`
+}
+
+// Clone creates copy of team member.
+func (o TeamMember) Clone() TeamMember {
+	return o
+}
+
+// TeamMemberEvent represents a team member event.
+type TeamMemberEvent struct {
+	baseEvent
+	TeamMember
+}
+
+// Object returns event team member.
+func (e TeamMemberEvent) Object() TeamMember {
+	return e.TeamMember
+}
+
+// SetObject sets event team member.
+func (e *TeamMemberEvent) SetObject(o TeamMember) {
+	e.TeamMember = o
+}
+
+// TeamMemberStore represents store for team members.
+type TeamMemberStore struct {
+	baseStore[TeamMember, TeamMemberEvent, *TeamMember, *TeamMemberEvent]
+	byTeam    *index[int64, TeamMember, *TeamMember]
+	byAccount *index[int64, TeamMember, *TeamMember]
+}
+
+// FindByTeam returns members of specified team.
+func (s *TeamMemberStore) FindByTeam(id int64) ([]TeamMember, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []TeamMember
+	for id := range s.byTeam.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
+// FindByAccount returns memberships of specified account.
+func (s *TeamMemberStore) FindByAccount(id int64) ([]TeamMember, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var objects []TeamMember
+	for id := range s.byAccount.Get(id) {
+		if object, ok := s.objects[id]; ok {
+			objects = append(objects, object.Clone())
+		}
+	}
+	return objects, nil
+}
+
+var _ baseStoreImpl[TeamMember] = (*TeamMemberStore)(nil)
+
+// NewTeamMemberStore creates a new instance of TeamMemberStore.
+func NewTeamMemberStore(
+	db *gosql.DB, table, eventTable string,
+) *TeamMemberStore {
+	impl := &TeamMemberStore{
+		byTeam:    newIndex(func(o TeamMember) int64 { return o.TeamID }),
+		byAccount: newIndex(func(o TeamMember) int64 { return o.AccountID }),
+	}
+	impl.baseStore = makeBaseStore[TeamMember, TeamMemberEvent](
+		db, table, eventTable, impl, impl.byTeam, impl.byAccount,
+	)
+	return impl
+}
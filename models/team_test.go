@@ -0,0 +1,79 @@
+package models
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+type teamStoreTest struct{}
+
+func (t *teamStoreTest) prepareDB(tx *sql.Tx) error {
+	if _, err := tx.Exec(
+		`CREATE TABLE "team" (` +
+			`"id" integer PRIMARY KEY,` +
+			`"account_id" integer NOT NULL,` +
+			`"owner_id" integer,` +
+			`"title" varchar(255) NOT NULL)`,
+	); err != nil {
+		return err
+	}
+	_, err := tx.Exec(
+		`CREATE TABLE "team_event" (` +
+			`"event_id" integer PRIMARY KEY,` +
+			`"event_kind" int8 NOT NULL,` +
+			`"event_time" bigint NOT NULL,` +
+			`"event_account_id" integer NULL,` +
+			`"id" integer NOT NULL,` +
+			`"account_id" integer NOT NULL,` +
+			`"owner_id" integer,` +
+			`"title" varchar(255) NOT NULL)`,
+	)
+	return err
+}
+
+func (t *teamStoreTest) newStore() Store {
+	return NewTeamStore(testDB, "team", "team_event")
+}
+
+func (t *teamStoreTest) newObject() object {
+	return Team{}
+}
+
+func (t *teamStoreTest) createObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	team := o.(Team)
+	if err := s.(*TeamStore).Create(wrapContext(tx), &team); err != nil {
+		return Team{}, err
+	}
+	return team, nil
+}
+
+func (t *teamStoreTest) updateObject(
+	s Store, tx *sql.Tx, o object,
+) (object, error) {
+	return o, s.(*TeamStore).Update(wrapContext(tx), o.(Team))
+}
+
+func (t *teamStoreTest) deleteObject(
+	s Store, tx *sql.Tx, id int64,
+) error {
+	return s.(*TeamStore).Delete(wrapContext(tx), id)
+}
+
+func TestTeamStore(t *testing.T) {
+	testSetup(t)
+	defer testTeardown(t)
+	tester := StoreTester{&teamStoreTest{}}
+	tester.Test(t)
+}
+
+func TestTeamClone(t *testing.T) {
+	team := Team{AccountID: 1, Title: "Team"}
+	team.ID = 12345
+	clone := team.Clone()
+	if !reflect.DeepEqual(team, clone) {
+		t.Fatalf("Team clone is invalid, %v != %v", team, clone)
+	}
+}
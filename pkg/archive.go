@@ -34,11 +34,15 @@ func ExtractZip(source, target string) (errRes error) {
 		}
 		path := filepath.Join(target, file.Name)
 		if file.FileInfo().IsDir() {
-			if err := os.Mkdir(path, file.Mode()); err != nil {
+			if err := os.MkdirAll(path, file.Mode()); err != nil {
 				return err
 			}
 			continue
 		}
+		// Archives are not required to contain entries for directories.
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
 		if err := func() error {
 			input, err := file.Open()
 			if err != nil {
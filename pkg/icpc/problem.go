@@ -0,0 +1,187 @@
+// Package icpc implements reading of ICPC (Kattis) problem packages.
+package icpc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Limits represents problem limits.
+type Limits struct {
+	// TimeLimit contains time limit in seconds.
+	TimeLimit float64 `yaml:"time_limit"`
+	// TimeMultiplier contains multiplier for time limit.
+	TimeMultiplier float64 `yaml:"time_multiplier"`
+	// Memory contains memory limit in MiB.
+	Memory int64 `yaml:"memory"`
+}
+
+// Problem represents problem config from problem.yaml.
+type Problem struct {
+	// Name contains problem name. It can be either string or map
+	// from language code to string.
+	Name           any    `yaml:"name"`
+	Validation     string `yaml:"validation"`
+	ValidatorFlags string `yaml:"validator_flags"`
+	Limits         Limits `yaml:"limits"`
+}
+
+// DefaultMemoryLimit contains default memory limit in MiB.
+const DefaultMemoryLimit = 2048
+
+// GetNames returns problem names for all languages.
+func (p Problem) GetNames() map[string]string {
+	names := map[string]string{}
+	switch name := p.Name.(type) {
+	case string:
+		names["en"] = name
+	case map[string]any:
+		for lang, value := range name {
+			if s, ok := value.(string); ok {
+				names[lang] = s
+			}
+		}
+	}
+	return names
+}
+
+// IsCustomValidation returns true if problem uses custom output validator.
+func (p Problem) IsCustomValidation() bool {
+	for _, field := range strings.Fields(p.Validation) {
+		if field == "custom" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInteractive returns true if problem uses interactive validation.
+func (p Problem) IsInteractive() bool {
+	for _, field := range strings.Fields(p.Validation) {
+		if field == "interactive" {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadProblemConfig reads problem config from file.
+func ReadProblemConfig(path string) (Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Problem{}, err
+	}
+	var problem Problem
+	if err := yaml.Unmarshal(data, &problem); err != nil {
+		return Problem{}, err
+	}
+	return problem, nil
+}
+
+// ReadTimeLimit reads legacy time limit in seconds from .timelimit file.
+func ReadTimeLimit(path string) (float64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+}
+
+// Test represents pair of input and answer files.
+type Test struct {
+	Input  string
+	Answer string
+}
+
+// ReadTests reads all tests from specified directory.
+//
+// Tests are sorted by name of input file.
+func ReadTests(dir string) ([]Test, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var tests []Test
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".in") {
+			continue
+		}
+		name := strings.TrimSuffix(file.Name(), ".in")
+		answer := filepath.Join(dir, name+".ans")
+		if _, err := os.Stat(answer); err != nil {
+			return nil, fmt.Errorf("cannot find answer for %q: %w", file.Name(), err)
+		}
+		tests = append(tests, Test{
+			Input:  filepath.Join(dir, file.Name()),
+			Answer: answer,
+		})
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		return tests[i].Input < tests[j].Input
+	})
+	return tests, nil
+}
+
+// Statement represents parsed LaTeX statement.
+type Statement struct {
+	Name   string
+	Legend string
+	Input  string
+	Output string
+	Notes  string
+}
+
+var (
+	problemNameRegexp = regexp.MustCompile(`\\problemname\{([^}]*)\}`)
+	sectionRegexp     = regexp.MustCompile(`\\section\*?\{([^}]*)\}`)
+)
+
+// ParseStatement parses LaTeX statement in problemtools format.
+func ParseStatement(data string) Statement {
+	var statement Statement
+	if match := problemNameRegexp.FindStringSubmatchIndex(data); match != nil {
+		statement.Name = strings.TrimSpace(data[match[2]:match[3]])
+		data = data[match[1]:]
+	}
+	sections := sectionRegexp.FindAllStringSubmatchIndex(data, -1)
+	end := len(data)
+	if len(sections) > 0 {
+		end = sections[0][0]
+	}
+	statement.Legend = strings.TrimSpace(data[:end])
+	for i, section := range sections {
+		end := len(data)
+		if i+1 < len(sections) {
+			end = sections[i+1][0]
+		}
+		text := strings.TrimSpace(data[section[1]:end])
+		switch strings.ToLower(strings.TrimSpace(data[section[2]:section[3]])) {
+		case "input", "interaction":
+			statement.Input = text
+		case "output":
+			statement.Output = text
+		default:
+			if len(statement.Notes) > 0 {
+				statement.Notes += "\n\n"
+			}
+			statement.Notes += text
+		}
+	}
+	return statement
+}
+
+// ReadStatement reads LaTeX statement from file.
+func ReadStatement(path string) (Statement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Statement{}, err
+	}
+	return ParseStatement(string(data)), nil
+}
@@ -0,0 +1,102 @@
+package icpc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "problem.yaml"), []byte(
+		"name:\n  en: Sum\n  ru: Сумма\n"+
+			"validation: custom\n"+
+			"limits:\n  time_limit: 1.5\n  memory: 512\n",
+	), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem, err := ReadProblemConfig(filepath.Join(dir, "problem.yaml"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	names := problem.GetNames()
+	if names["en"] != "Sum" || names["ru"] != "Сумма" {
+		t.Fatal("Invalid names:", names)
+	}
+	if !problem.IsCustomValidation() {
+		t.Fatal("Expected custom validation")
+	}
+	if problem.IsInteractive() {
+		t.Fatal("Unexpected interactive validation")
+	}
+	if problem.Limits.TimeLimit != 1.5 || problem.Limits.Memory != 512 {
+		t.Fatal("Invalid limits:", problem.Limits)
+	}
+}
+
+func TestSimpleNameProblem(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "problem.yaml"), []byte(
+		"name: Sum\nvalidator_flags: float_tolerance 1e-6\n",
+	), 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	problem, err := ReadProblemConfig(filepath.Join(dir, "problem.yaml"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if names := problem.GetNames(); names["en"] != "Sum" {
+		t.Fatal("Invalid names:", names)
+	}
+	if problem.IsCustomValidation() {
+		t.Fatal("Expected default validation")
+	}
+	if problem.IsInteractive() {
+		t.Fatal("Unexpected interactive validation")
+	}
+}
+
+func TestReadTests(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"2.in", "2.ans", "1.in", "1.ans", "readme.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	tests, err := ReadTests(dir)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if len(tests) != 2 {
+		t.Fatal("Invalid tests:", tests)
+	}
+	if tests[0].Answer != filepath.Join(dir, "1.ans") {
+		t.Fatal("Invalid answer:", tests[0].Answer)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "3.in"), nil, 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if _, err := ReadTests(dir); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestParseStatement(t *testing.T) {
+	statement := ParseStatement(
+		"\\problemname{Sum}\n" +
+			"Compute $a+b$.\n" +
+			"\\section*{Input}\nTwo integers.\n" +
+			"\\section*{Output}\nOne integer.\n" +
+			"\\section*{Notes}\nBe careful.\n",
+	)
+	expected := Statement{
+		Name:   "Sum",
+		Legend: "Compute $a+b$.",
+		Input:  "Two integers.",
+		Output: "One integer.",
+		Notes:  "Be careful.",
+	}
+	if statement != expected {
+		t.Fatalf("Expected %v, got %v", expected, statement)
+	}
+}
@@ -0,0 +1,719 @@
+// Package markup implements rendering of problem statements written in
+// mix of Markdown and LaTeX to sanitized HTML.
+//
+// Rendered HTML never contains raw HTML from source: all text is escaped
+// and only fixed set of tags is produced. Math formulas are preserved
+// as is and wrapped with \( \) or \[ \] delimiters, so they can be
+// rendered on client side with MathJax or KaTeX.
+package markup
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Options represents options for rendering.
+type Options struct {
+	// ResourceURL returns URL for statement resource with specified name.
+	//
+	// If ResourceURL is nil, then local resources are not rendered.
+	ResourceURL func(name string) string
+}
+
+// RenderHTML renders statement source to sanitized HTML.
+func RenderHTML(source string, options Options) string {
+	r := renderer{
+		src:     strings.ReplaceAll(source, "\r\n", "\n"),
+		options: options,
+	}
+	r.render()
+	return strings.TrimSpace(r.out.String())
+}
+
+type environment struct {
+	name     string
+	closeTag string
+	itemOpen bool
+}
+
+type renderer struct {
+	src     string
+	pos     int
+	out     strings.Builder
+	options Options
+	// paragraph is true when paragraph is opened.
+	paragraph bool
+	// groups contains closing tags for opened brace groups.
+	groups []string
+	// inlines contains opened Markdown inline tags.
+	inlines []string
+	// envs contains opened LaTeX environments.
+	envs []environment
+	// list contains closing tag of opened Markdown list.
+	list     string
+	listItem bool
+	// newline is true when line break should be written before
+	// next inline content.
+	newline bool
+}
+
+func (r *renderer) render() {
+	for r.pos < len(r.src) {
+		if r.atLineStart() && r.renderLineStart() {
+			continue
+		}
+		r.renderInline()
+	}
+	r.closeBlock()
+	for len(r.envs) > 0 {
+		r.closeEnv()
+	}
+}
+
+func (r *renderer) atLineStart() bool {
+	return r.pos == 0 || r.src[r.pos-1] == '\n'
+}
+
+// renderLineStart handles block level constructions.
+//
+// Returns true if something was consumed.
+func (r *renderer) renderLineStart() bool {
+	end := strings.IndexByte(r.src[r.pos:], '\n')
+	if end < 0 {
+		end = len(r.src)
+	} else {
+		end += r.pos
+	}
+	line := r.src[r.pos:end]
+	if strings.TrimSpace(line) == "" {
+		if len(r.envs) == 0 {
+			r.closeBlock()
+		}
+		r.pos = end
+		if r.pos < len(r.src) {
+			r.pos++
+		}
+		return true
+	}
+	if len(r.envs) != 0 {
+		return false
+	}
+	if strings.HasPrefix(line, "```") {
+		r.renderFence()
+		return true
+	}
+	if n, ordered := listMarker(line); n > 0 {
+		closeTag := "</ul>"
+		if ordered {
+			closeTag = "</ol>"
+		}
+		if r.list != closeTag {
+			r.closeBlock()
+			if ordered {
+				r.out.WriteString("<ol>")
+			} else {
+				r.out.WriteString("<ul>")
+			}
+			r.list = closeTag
+		}
+		r.closeInlines()
+		if r.listItem {
+			r.out.WriteString("</li>")
+		}
+		r.out.WriteString("<li>")
+		r.listItem = true
+		r.pos += n
+		return true
+	}
+	return false
+}
+
+// listMarker returns length of Markdown list item marker.
+func listMarker(line string) (int, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := len(line) - len(trimmed)
+	if len(trimmed) >= 2 && (trimmed[0] == '-' || trimmed[0] == '*') &&
+		trimmed[1] == ' ' {
+		return indent + 2, false
+	}
+	digits := 0
+	for digits < len(trimmed) && trimmed[digits] >= '0' && trimmed[digits] <= '9' {
+		digits++
+	}
+	if digits > 0 && digits+1 < len(trimmed) &&
+		trimmed[digits] == '.' && trimmed[digits+1] == ' ' {
+		return indent + digits + 2, true
+	}
+	return 0, false
+}
+
+func (r *renderer) renderFence() {
+	start := strings.IndexByte(r.src[r.pos:], '\n')
+	if start < 0 {
+		r.pos = len(r.src)
+		return
+	}
+	start += r.pos + 1
+	end := strings.Index(r.src[start:], "\n```")
+	var code string
+	if end < 0 {
+		code = r.src[start:]
+		r.pos = len(r.src)
+	} else {
+		code = r.src[start : start+end+1]
+		r.pos = start + end + 4
+		if next := strings.IndexByte(r.src[r.pos:], '\n'); next >= 0 {
+			r.pos += next + 1
+		} else {
+			r.pos = len(r.src)
+		}
+	}
+	r.closeBlock()
+	r.out.WriteString("<pre><code>")
+	r.out.WriteString(escapeHTML(code))
+	r.out.WriteString("</code></pre>")
+}
+
+func (r *renderer) openParagraph() {
+	if r.paragraph || r.list != "" || len(r.envs) != 0 {
+		return
+	}
+	r.out.WriteString("<p>")
+	r.paragraph = true
+}
+
+func (r *renderer) closeInlines() {
+	r.newline = false
+	for i := len(r.groups) - 1; i >= 0; i-- {
+		r.out.WriteString(r.groups[i])
+	}
+	r.groups = nil
+	for i := len(r.inlines) - 1; i >= 0; i-- {
+		r.out.WriteString("</" + r.inlines[i] + ">")
+	}
+	r.inlines = nil
+}
+
+func (r *renderer) closeBlock() {
+	r.closeInlines()
+	if r.paragraph {
+		r.out.WriteString("</p>")
+		r.paragraph = false
+	}
+	if r.list != "" {
+		if r.listItem {
+			r.out.WriteString("</li>")
+		}
+		r.out.WriteString(r.list)
+		r.list = ""
+		r.listItem = false
+	}
+}
+
+func (r *renderer) closeEnv() {
+	env := r.envs[len(r.envs)-1]
+	r.envs = r.envs[:len(r.envs)-1]
+	r.closeInlines()
+	if env.itemOpen {
+		r.out.WriteString("</li>")
+	}
+	r.out.WriteString(env.closeTag)
+}
+
+func (r *renderer) write(text string) {
+	r.openParagraph()
+	if r.newline {
+		r.out.WriteByte('\n')
+		r.newline = false
+	}
+	r.out.WriteString(text)
+}
+
+func (r *renderer) renderInline() {
+	c := r.src[r.pos]
+	rest := r.src[r.pos:]
+	switch {
+	case c == '\\':
+		r.renderCommand()
+	case strings.HasPrefix(rest, "$$"):
+		r.renderMath("$$", "$$", true)
+	case c == '$':
+		r.renderMath("$", "$", false)
+	case c == '%':
+		// LaTeX comment lasts until end of line.
+		if end := strings.IndexByte(rest, '\n'); end >= 0 {
+			r.pos += end
+		} else {
+			r.pos = len(r.src)
+		}
+	case c == '{':
+		r.groups = append(r.groups, "")
+		r.pos++
+	case c == '}':
+		if len(r.groups) > 0 {
+			r.write(r.groups[len(r.groups)-1])
+			r.groups = r.groups[:len(r.groups)-1]
+		}
+		r.pos++
+	case c == '`':
+		end := strings.IndexByte(rest[1:], '`')
+		if end < 0 {
+			r.write("`")
+			r.pos++
+			return
+		}
+		r.write("<code>" + escapeHTML(rest[1:end+1]) + "</code>")
+		r.pos += end + 2
+	case strings.HasPrefix(rest, "**"):
+		r.toggleInline("strong")
+		r.pos += 2
+	case c == '*' && r.canToggleEmphasis():
+		r.toggleInline("em")
+		r.pos++
+	case strings.HasPrefix(rest, "!["):
+		if !r.renderLink(true) {
+			r.write("!")
+			r.pos++
+		}
+	case c == '[':
+		if !r.renderLink(false) {
+			r.write("[")
+			r.pos++
+		}
+	case c == '~':
+		r.write("&nbsp;")
+		r.pos++
+	case strings.HasPrefix(rest, "---"):
+		r.write("&mdash;")
+		r.pos += 3
+	case strings.HasPrefix(rest, "--"):
+		r.write("&ndash;")
+		r.pos += 2
+	case strings.HasPrefix(rest, "<<"):
+		r.write("&laquo;")
+		r.pos += 2
+	case strings.HasPrefix(rest, ">>"):
+		r.write("&raquo;")
+		r.pos += 2
+	case c == '\n':
+		r.newline = true
+		r.pos++
+	default:
+		end := r.pos + 1
+		for end < len(r.src) && !isSpecial(r.src[end]) {
+			end++
+		}
+		r.write(escapeHTML(r.src[r.pos:end]))
+		r.pos = end
+	}
+}
+
+func isSpecial(c byte) bool {
+	return strings.IndexByte("\\$%{}`*![~-<>\n", c) >= 0
+}
+
+func (r *renderer) canToggleEmphasis() bool {
+	for _, tag := range r.inlines {
+		if tag == "em" {
+			return true
+		}
+	}
+	// Opening emphasis should be followed by non-space character.
+	return r.pos+1 < len(r.src) && !unicode.IsSpace(rune(r.src[r.pos+1]))
+}
+
+func (r *renderer) toggleInline(tag string) {
+	for i := len(r.inlines) - 1; i >= 0; i-- {
+		if r.inlines[i] == tag {
+			for j := len(r.inlines) - 1; j >= i; j-- {
+				r.write("</" + r.inlines[j] + ">")
+			}
+			r.inlines = r.inlines[:i]
+			return
+		}
+	}
+	r.write("<" + tag + ">")
+	r.inlines = append(r.inlines, tag)
+}
+
+func (r *renderer) renderMath(begin, end string, display bool) {
+	start := r.pos + len(begin)
+	stop := findUnescaped(r.src[start:], end)
+	if stop < 0 {
+		r.write(escapeHTML(begin))
+		r.pos = start
+		return
+	}
+	r.writeMath(r.src[start:start+stop], display)
+	r.pos = start + stop + len(end)
+}
+
+func (r *renderer) writeMath(math string, display bool) {
+	if display {
+		r.write(`<span class="math math-display">\[` + escapeHTML(math) + `\]</span>`)
+	} else {
+		r.write(`<span class="math math-inline">\(` + escapeHTML(math) + `\)</span>`)
+	}
+}
+
+// findUnescaped returns index of first occurrence of sep that is not
+// prefixed with backslash.
+func findUnescaped(s, sep string) int {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+var inlineCommands = map[string][2]string{
+	"textbf":    {"<strong>", "</strong>"},
+	"bf":        {"<strong>", "</strong>"},
+	"textit":    {"<em>", "</em>"},
+	"it":        {"<em>", "</em>"},
+	"emph":      {"<em>", "</em>"},
+	"texttt":    {"<code>", "</code>"},
+	"tt":        {"<code>", "</code>"},
+	"underline": {"<u>", "</u>"},
+	"sout":      {"<s>", "</s>"},
+	"textsc":    {`<span class="small-caps">`, "</span>"},
+}
+
+var symbolCommands = map[string]string{
+	"ldots":    "&hellip;",
+	"dots":     "&hellip;",
+	"le":       "&le;",
+	"ge":       "&ge;",
+	"times":    "&times;",
+	"cdot":     "&middot;",
+	"quad":     "&emsp;",
+	"LaTeX":    "LaTeX",
+	"TeX":      "TeX",
+	"noindent": "",
+	"par":      "",
+}
+
+func (r *renderer) renderCommand() {
+	rest := r.src[r.pos:]
+	if len(rest) < 2 {
+		r.write("\\")
+		r.pos++
+		return
+	}
+	switch rest[1] {
+	case '\\':
+		r.write("<br>")
+		r.pos += 2
+		return
+	case '[':
+		r.pos += 2
+		if end := strings.Index(r.src[r.pos:], `\]`); end >= 0 {
+			r.writeMath(r.src[r.pos:r.pos+end], true)
+			r.pos += end + 2
+		} else {
+			r.write(escapeHTML(`\[`))
+		}
+		return
+	case '(':
+		r.pos += 2
+		if end := strings.Index(r.src[r.pos:], `\)`); end >= 0 {
+			r.writeMath(r.src[r.pos:r.pos+end], false)
+			r.pos += end + 2
+		} else {
+			r.write(escapeHTML(`\(`))
+		}
+		return
+	case '$', '%', '&', '_', '{', '}', '#', '*', '`', ']', '~':
+		r.write(escapeHTML(rest[1:2]))
+		r.pos += 2
+		return
+	}
+	name := commandName(rest[1:])
+	if name == "" {
+		r.write("\\")
+		r.pos++
+		return
+	}
+	r.pos += len(name) + 1
+	if tags, ok := inlineCommands[name]; ok {
+		r.skipSpaces()
+		if r.pos < len(r.src) && r.src[r.pos] == '{' {
+			r.write(tags[0])
+			r.groups = append(r.groups, tags[1])
+			r.pos++
+		}
+		return
+	}
+	if symbol, ok := symbolCommands[name]; ok {
+		r.write(symbol)
+		// Command name is terminated by one space.
+		if r.pos < len(r.src) && r.src[r.pos] == ' ' && symbol != "" {
+			r.pos++
+		}
+		return
+	}
+	switch name {
+	case "begin":
+		r.beginEnv(r.readArgument())
+	case "end":
+		env := r.readArgument()
+		for i := len(r.envs) - 1; i >= 0; i-- {
+			if r.envs[i].name == env {
+				for len(r.envs) > i {
+					r.closeEnv()
+				}
+				break
+			}
+		}
+	case "item":
+		if len(r.envs) == 0 {
+			return
+		}
+		env := &r.envs[len(r.envs)-1]
+		if env.name != "itemize" && env.name != "enumerate" {
+			return
+		}
+		r.closeInlines()
+		if env.itemOpen {
+			r.out.WriteString("</li>")
+		}
+		r.out.WriteString("<li>")
+		env.itemOpen = true
+		r.skipSpaces()
+	case "includegraphics":
+		r.readOptionalArgument()
+		r.writeImage(r.readArgument(), "")
+	case "url":
+		link := r.readArgument()
+		r.writeLink(link, escapeHTML(link))
+	case "href":
+		link := r.readArgument()
+		r.skipSpaces()
+		if r.pos < len(r.src) && r.src[r.pos] == '{' {
+			if href := sanitizeURL(link); href != "" {
+				r.write(`<a href="` + escapeHTML(href) + `" rel="nofollow noopener">`)
+				r.groups = append(r.groups, "</a>")
+			} else {
+				r.groups = append(r.groups, "")
+			}
+			r.pos++
+		}
+	default:
+		// Unknown commands are dropped, but their arguments are
+		// rendered as regular text.
+	}
+}
+
+func commandName(s string) string {
+	end := 0
+	for end < len(s) && (s[end] >= 'a' && s[end] <= 'z' || s[end] >= 'A' && s[end] <= 'Z') {
+		end++
+	}
+	return s[:end]
+}
+
+func (r *renderer) skipSpaces() {
+	for r.pos < len(r.src) && (r.src[r.pos] == ' ' || r.src[r.pos] == '\t') {
+		r.pos++
+	}
+}
+
+// readArgument reads raw content of braced argument.
+func (r *renderer) readArgument() string {
+	r.skipSpaces()
+	if r.pos >= len(r.src) || r.src[r.pos] != '{' {
+		return ""
+	}
+	depth := 0
+	for i := r.pos; i < len(r.src); i++ {
+		switch r.src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				arg := r.src[r.pos+1 : i]
+				r.pos = i + 1
+				return arg
+			}
+		}
+	}
+	arg := r.src[r.pos+1:]
+	r.pos = len(r.src)
+	return arg
+}
+
+func (r *renderer) readOptionalArgument() string {
+	r.skipSpaces()
+	if r.pos >= len(r.src) || r.src[r.pos] != '[' {
+		return ""
+	}
+	end := strings.IndexByte(r.src[r.pos:], ']')
+	if end < 0 {
+		return ""
+	}
+	arg := r.src[r.pos+1 : r.pos+end]
+	r.pos += end + 1
+	return arg
+}
+
+func (r *renderer) beginEnv(name string) {
+	switch name {
+	case "itemize", "enumerate", "center", "tabular":
+	case "verbatim", "lstlisting":
+		r.readOptionalArgument()
+		end := strings.Index(r.src[r.pos:], `\end{`+name+`}`)
+		var code string
+		if end < 0 {
+			code = r.src[r.pos:]
+			r.pos = len(r.src)
+		} else {
+			code = r.src[r.pos : r.pos+end]
+			r.pos += end + len(`\end{`+name+`}`)
+		}
+		r.closeBlock()
+		r.out.WriteString("<pre><code>")
+		r.out.WriteString(escapeHTML(strings.Trim(code, "\n")))
+		r.out.WriteString("</code></pre>")
+		return
+	case "equation", "equation*", "align", "align*", "displaymath":
+		end := strings.Index(r.src[r.pos:], `\end{`+name+`}`)
+		if end < 0 {
+			return
+		}
+		math := `\begin{` + name + `}` + r.src[r.pos:r.pos+end] + `\end{` + name + `}`
+		r.writeMath(math, true)
+		r.pos += end + len(`\end{`+name+`}`)
+		return
+	default:
+		return
+	}
+	r.closeBlock()
+	env := environment{name: name}
+	switch name {
+	case "itemize":
+		r.out.WriteString("<ul>")
+		env.closeTag = "</ul>"
+	case "enumerate":
+		r.out.WriteString("<ol>")
+		env.closeTag = "</ol>"
+	case "center":
+		r.out.WriteString(`<div class="center">`)
+		env.closeTag = "</div>"
+	case "tabular":
+		// Tables are rendered as plain text.
+		r.readArgument()
+		r.out.WriteString(`<div class="tabular">`)
+		env.closeTag = "</div>"
+	}
+	r.envs = append(r.envs, env)
+}
+
+// renderLink renders Markdown link or image.
+func (r *renderer) renderLink(image bool) bool {
+	start := r.pos + 1
+	if image {
+		start++
+	}
+	textEnd := strings.IndexByte(r.src[start:], ']')
+	if textEnd < 0 {
+		return false
+	}
+	textEnd += start
+	if textEnd+1 >= len(r.src) || r.src[textEnd+1] != '(' {
+		return false
+	}
+	linkEnd, depth := -1, 0
+	for i := textEnd + 2; i < len(r.src) && linkEnd < 0; i++ {
+		switch r.src[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				linkEnd = i
+			}
+			depth--
+		case '\n':
+			return false
+		}
+	}
+	if linkEnd < 0 {
+		return false
+	}
+	text := r.src[start:textEnd]
+	link := strings.TrimSpace(r.src[textEnd+2 : linkEnd])
+	r.pos = linkEnd + 1
+	if image {
+		r.writeImage(link, text)
+	} else {
+		r.writeLink(link, escapeHTML(text))
+	}
+	return true
+}
+
+func (r *renderer) writeLink(link, html string) {
+	href := sanitizeURL(link)
+	if href == "" {
+		r.write(html)
+		return
+	}
+	r.write(`<a href="` + escapeHTML(href) + `" rel="nofollow noopener">` + html + "</a>")
+}
+
+func (r *renderer) writeImage(name, alt string) {
+	src := ""
+	if isLocalResource(name) {
+		if r.options.ResourceURL != nil {
+			src = r.options.ResourceURL(name)
+		}
+	} else {
+		src = sanitizeURL(name)
+	}
+	if src == "" {
+		r.write(escapeHTML(alt))
+		return
+	}
+	r.write(`<img src="` + escapeHTML(src) + `" alt="` + escapeHTML(alt) + `">`)
+}
+
+func isLocalResource(name string) bool {
+	return name != "" && !strings.Contains(name, ":") &&
+		!strings.HasPrefix(name, "/") && !strings.Contains(name, "..")
+}
+
+// sanitizeURL returns URL if it has safe scheme or empty string.
+func sanitizeURL(link string) string {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return ""
+	}
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https", "mailto":
+		return parsed.String()
+	case "":
+		if parsed.Host != "" {
+			return ""
+		}
+		return parsed.String()
+	default:
+		return ""
+	}
+}
+
+var htmlReplacer = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&#34;",
+	"'", "&#39;",
+)
+
+func escapeHTML(s string) string {
+	return htmlReplacer.Replace(s)
+}
@@ -0,0 +1,42 @@
+package markup
+
+import (
+	"testing"
+)
+
+func TestRenderHTML(t *testing.T) {
+	options := Options{
+		ResourceURL: func(name string) string {
+			return "/v0/problems/1/resources/" + name
+		},
+	}
+	tests := []struct {
+		Source string
+		HTML   string
+	}{
+		{"Hello, world!", "<p>Hello, world!</p>"},
+		{"First\n\nSecond", "<p>First</p><p>Second</p>"},
+		{"<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"Sum $a + b < c$.", `<p>Sum <span class="math math-inline">\(a + b &lt; c\)</span>.</p>`},
+		{"$$\\sum_{i=1}^n a_i$$", `<p><span class="math math-display">\[\sum_{i=1}^n a_i\]</span></p>`},
+		{"\\textbf{bold} and \\emph{it}", "<p><strong>bold</strong> and <em>it</em></p>"},
+		{"**bold** and *it* and `code`", "<p><strong>bold</strong> and <em>it</em> and <code>code</code></p>"},
+		{"\\begin{itemize}\n\\item One\n\\item Two\n\\end{itemize}", "<ul><li>One</li><li>Two</li></ul>"},
+		{"- One\n- Two", "<ul><li>One</li><li>Two</li></ul>"},
+		{"1. One\n2. Two", "<ol><li>One</li><li>Two</li></ol>"},
+		{"\\includegraphics[width=5cm]{a.png}", `<p><img src="/v0/problems/1/resources/a.png" alt=""></p>`},
+		{"![Picture](b.png)", `<p><img src="/v0/problems/1/resources/b.png" alt="Picture"></p>`},
+		{"[link](javascript:alert(1))", "<p>link</p>"},
+		{"[link](https://example.com)", `<p><a href="https://example.com" rel="nofollow noopener">link</a></p>`},
+		{"a~--- b <<c>>", "<p>a&nbsp;&mdash; b &laquo;c&raquo;</p>"},
+		{"Text % comment\nmore", "<p>Text \nmore</p>"},
+		{"\\textbf{unclosed", "<p><strong>unclosed</strong></p>"},
+		{"100\\%", "<p>100%</p>"},
+	}
+	for _, test := range tests {
+		html := RenderHTML(test.Source, options)
+		if html != test.HTML {
+			t.Fatalf("Expected %q, got %q", test.HTML, html)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+// Package oci implements import of container images saved with
+// "docker save" or stored in OCI image layout.
+package oci
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Layer represents image layer.
+type Layer struct {
+	// Digest contains digest of uncompressed layer content.
+	Digest string
+	// Path contains path to layer tarball.
+	Path string
+}
+
+// ImageConfig represents runtime config of image.
+type ImageConfig struct {
+	Env        []string `json:"Env"`
+	WorkingDir string   `json:"WorkingDir"`
+	Entrypoint []string `json:"Entrypoint"`
+	Cmd        []string `json:"Cmd"`
+}
+
+// Image represents extracted image.
+type Image struct {
+	// Layers contains layers ordered from base to top.
+	Layers []Layer
+	// Config contains runtime config of image.
+	Config ImageConfig
+}
+
+type imageConfigFile struct {
+	Config ImageConfig `json:"config"`
+	RootFS struct {
+		DiffIDs []string `json:"diff_ids"`
+	} `json:"rootfs"`
+}
+
+type dockerManifest struct {
+	Config string   `json:"Config"`
+	Layers []string `json:"Layers"`
+}
+
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+}
+
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Config    ociDescriptor   `json:"config"`
+	Layers    []ociDescriptor `json:"layers"`
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+const (
+	dockerManifestName = "manifest.json"
+	ociLayoutName      = "oci-layout"
+	ociIndexName       = "index.json"
+)
+
+// IsImageArchive returns true if file is "docker save" or OCI layout
+// tarball.
+func IsImageArchive(source string) (bool, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = file.Close() }()
+	reader, err := openTar(file)
+	if err != nil {
+		return false, nil
+	}
+	defer func() { _ = reader.Close() }()
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			// Not a tarball, so it can not be an image.
+			return false, nil
+		}
+		switch path.Clean(header.Name) {
+		case dockerManifestName, ociLayoutName:
+			return true, nil
+		}
+	}
+}
+
+// ExtractImage extracts image tarball into target directory and reads
+// image manifest.
+func ExtractImage(source, target string) (Image, error) {
+	if err := extractImageArchive(source, target); err != nil {
+		return Image{}, err
+	}
+	if _, err := os.Stat(filepath.Join(target, ociLayoutName)); err == nil {
+		return readOCIImage(target)
+	}
+	return readDockerImage(target)
+}
+
+func readDockerImage(dir string) (Image, error) {
+	var manifests []dockerManifest
+	if err := readJSON(filepath.Join(dir, dockerManifestName), &manifests); err != nil {
+		return Image{}, fmt.Errorf("cannot read manifest: %w", err)
+	}
+	if len(manifests) != 1 {
+		return Image{}, fmt.Errorf("expected single image, got %d", len(manifests))
+	}
+	manifest := manifests[0]
+	configPath, err := securePath(dir, manifest.Config)
+	if err != nil {
+		return Image{}, err
+	}
+	var layers []string
+	for _, layer := range manifest.Layers {
+		layerPath, err := securePath(dir, layer)
+		if err != nil {
+			return Image{}, err
+		}
+		layers = append(layers, layerPath)
+	}
+	return makeImage(configPath, layers)
+}
+
+func readOCIImage(dir string) (Image, error) {
+	var index ociIndex
+	if err := readJSON(filepath.Join(dir, ociIndexName), &index); err != nil {
+		return Image{}, fmt.Errorf("cannot read index: %w", err)
+	}
+	descriptors := index.Manifests
+	// Nested indexes are resolved until image manifest is found.
+	for depth := 0; depth < 8; depth++ {
+		if len(descriptors) != 1 {
+			return Image{}, fmt.Errorf("expected single manifest, got %d", len(descriptors))
+		}
+		manifestPath, err := blobPath(dir, descriptors[0].Digest)
+		if err != nil {
+			return Image{}, err
+		}
+		var manifest ociManifest
+		if err := readJSON(manifestPath, &manifest); err != nil {
+			return Image{}, fmt.Errorf("cannot read manifest: %w", err)
+		}
+		if len(manifest.Manifests) > 0 {
+			descriptors = manifest.Manifests
+			continue
+		}
+		configPath, err := blobPath(dir, manifest.Config.Digest)
+		if err != nil {
+			return Image{}, err
+		}
+		var layers []string
+		for _, layer := range manifest.Layers {
+			layerPath, err := blobPath(dir, layer.Digest)
+			if err != nil {
+				return Image{}, err
+			}
+			layers = append(layers, layerPath)
+		}
+		return makeImage(configPath, layers)
+	}
+	return Image{}, fmt.Errorf("too deep nesting of indexes")
+}
+
+func makeImage(configPath string, layers []string) (Image, error) {
+	var config imageConfigFile
+	if err := readJSON(configPath, &config); err != nil {
+		return Image{}, fmt.Errorf("cannot read image config: %w", err)
+	}
+	image := Image{Config: config.Config}
+	for i, layerPath := range layers {
+		layer := Layer{Path: layerPath}
+		if len(config.RootFS.DiffIDs) == len(layers) {
+			layer.Digest = config.RootFS.DiffIDs[i]
+		} else {
+			digest, err := layerDigest(layerPath)
+			if err != nil {
+				return Image{}, err
+			}
+			layer.Digest = digest
+		}
+		image.Layers = append(image.Layers, layer)
+	}
+	return image, nil
+}
+
+func blobPath(dir, digest string) (string, error) {
+	algorithm, hash, ok := strings.Cut(digest, ":")
+	if !ok {
+		return "", fmt.Errorf("invalid digest %q", digest)
+	}
+	return securePath(dir, path.Join("blobs", algorithm, hash))
+}
+
+// securePath returns path inside of directory.
+func securePath(dir, name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return "", fmt.Errorf("illegal file path: %q", name)
+	}
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+// maxSymlinks contains maximal amount of symlinks that can be
+// followed during resolving of single path.
+const maxSymlinks = 255
+
+// resolvePath returns path inside of directory with resolved parents.
+//
+// Symlinks in parent directories are resolved as if directory was
+// root of filesystem, so entries of archive can not be written outside
+// of directory through previously extracted symlinks.
+func resolvePath(dir, name string) (string, error) {
+	clean := path.Clean("/" + name)
+	if clean == "/" {
+		return "", fmt.Errorf("illegal file path: %q", name)
+	}
+	parent, base := path.Split(clean)
+	parts := strings.Split(parent, "/")
+	resolved := "/"
+	links := 0
+	for len(parts) > 0 {
+		part := parts[0]
+		parts = parts[1:]
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = path.Dir(resolved)
+			continue
+		}
+		next := path.Join(resolved, part)
+		nextPath := filepath.Join(dir, filepath.FromSlash(next))
+		info, err := os.Lstat(nextPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				resolved = next
+				continue
+			}
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		links++
+		if links > maxSymlinks {
+			return "", fmt.Errorf("too many symlinks in %q", name)
+		}
+		link, err := os.Readlink(nextPath)
+		if err != nil {
+			return "", err
+		}
+		if path.IsAbs(link) {
+			resolved = "/"
+		}
+		parts = append(strings.Split(link, "/"), parts...)
+	}
+	return filepath.Join(dir, filepath.FromSlash(path.Join(resolved, base))), nil
+}
+
+func readJSON(path string, value any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+func layerDigest(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	reader, err := openTar(file)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = reader.Close() }()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// openTar returns reader for tarball that may be compressed with gzip.
+func openTar(file io.Reader) (io.ReadCloser, error) {
+	reader := bufio.NewReader(file)
+	magic, err := reader.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	if magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(reader)
+	}
+	return io.NopCloser(reader), nil
+}
+
+func extractImageArchive(source, target string) (errRes error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("cannot open file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	reader, err := openTar(file)
+	if err != nil {
+		return fmt.Errorf("cannot open archive: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+	if err := os.MkdirAll(target, os.ModePerm); err != nil {
+		return fmt.Errorf("cannot prepare target directory: %w", err)
+	}
+	defer func() {
+		if errRes != nil {
+			_ = os.RemoveAll(target)
+		}
+	}()
+	archive := tar.NewReader(reader)
+	// Symlinks are created after all files, so files can not be
+	// written through symlinks.
+	symlinks := map[string]string{}
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot get header: %w", err)
+		}
+		path, err := securePath(target, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(path, archive, 0644); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			// Docker save uses symlinks for shared layers.
+			link := header.Linkname
+			if !filepath.IsAbs(link) {
+				link = filepath.Join(filepath.Dir(header.Name), link)
+			}
+			linkPath, err := securePath(target, link)
+			if err != nil {
+				return err
+			}
+			symlinks[path] = linkPath
+		}
+	}
+	for path, link := range symlinks {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			return err
+		}
+		if err := os.Symlink(link, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFile(path string, reader io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,295 @@
+package oci
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+type testEntry struct {
+	Name string
+	Data string
+	Dir  bool
+	Link string
+}
+
+func makeTestTar(t testing.TB, entries ...testEntry) []byte {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := tar.Header{Name: entry.Name, Mode: 0644, Typeflag: tar.TypeReg}
+		if entry.Dir {
+			header.Mode = 0755
+			header.Typeflag = tar.TypeDir
+		} else if entry.Link != "" {
+			header.Mode = 0777
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.Link
+		} else {
+			header.Size = int64(len(entry.Data))
+		}
+		if err := writer.WriteHeader(&header); err != nil {
+			t.Fatal("Error:", err)
+		}
+		if _, err := writer.Write([]byte(entry.Data)); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal("Error:", err)
+	}
+	return buffer.Bytes()
+}
+
+func makeTestJSON(t testing.TB, value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	return string(data)
+}
+
+func testDigest(data []byte) string {
+	hash := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+func makeTestLayers(t testing.TB) [][]byte {
+	base := makeTestTar(t,
+		testEntry{Name: "etc/", Dir: true},
+		testEntry{Name: "etc/removed", Data: "removed"},
+		testEntry{Name: "etc/kept", Data: "kept"},
+		testEntry{Name: "opt/dir/old", Data: "old"},
+	)
+	top := makeTestTar(t,
+		testEntry{Name: "etc/.wh.removed"},
+		testEntry{Name: "etc/kept", Data: "updated"},
+		testEntry{Name: "opt/dir/.wh..wh..opq"},
+		testEntry{Name: "opt/dir/new", Data: "new"},
+	)
+	return [][]byte{base, top}
+}
+
+func makeTestConfig(t testing.TB, layers [][]byte) string {
+	config := imageConfigFile{
+		Config: ImageConfig{Env: []string{"PATH=/usr/bin", "LANG=C"}},
+	}
+	for _, layer := range layers {
+		config.RootFS.DiffIDs = append(config.RootFS.DiffIDs, testDigest(layer))
+	}
+	return makeTestJSON(t, config)
+}
+
+func writeTestFile(t testing.TB, path string, data []byte) {
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func checkTestImage(t *testing.T, source string, layers [][]byte) {
+	ok, err := IsImageArchive(source)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if !ok {
+		t.Fatal("Expected image archive")
+	}
+	image, err := ExtractImage(source, filepath.Join(t.TempDir(), "image"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if env := []string{"PATH=/usr/bin", "LANG=C"}; !reflect.DeepEqual(image.Config.Env, env) {
+		t.Fatalf("Expected %v, got %v", env, image.Config.Env)
+	}
+	if len(image.Layers) != len(layers) {
+		t.Fatalf("Expected %d layers, got %d", len(layers), len(image.Layers))
+	}
+	rootfs := filepath.Join(t.TempDir(), "rootfs")
+	for i, layer := range image.Layers {
+		if digest := testDigest(layers[i]); layer.Digest != digest {
+			t.Fatalf("Expected %q, got %q", digest, layer.Digest)
+		}
+		if err := applyLayer(layer.Path, rootfs); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	for name, data := range map[string]string{
+		"etc/kept":    "updated",
+		"opt/dir/new": "new",
+	} {
+		content, err := os.ReadFile(filepath.Join(rootfs, name))
+		if err != nil {
+			t.Fatal("Error:", err)
+		}
+		if string(content) != data {
+			t.Fatalf("Expected %q, got %q", data, content)
+		}
+	}
+	for _, name := range []string{"etc/removed", "opt/dir/old"} {
+		if _, err := os.Stat(filepath.Join(rootfs, name)); !os.IsNotExist(err) {
+			t.Fatalf("Expected %q to be removed", name)
+		}
+	}
+}
+
+func TestDockerImage(t *testing.T) {
+	layers := makeTestLayers(t)
+	manifest := []dockerManifest{{
+		Config: "config.json",
+		Layers: []string{"base/layer.tar", "top/layer.tar"},
+	}}
+	archive := makeTestTar(t,
+		testEntry{Name: "manifest.json", Data: makeTestJSON(t, manifest)},
+		testEntry{Name: "config.json", Data: makeTestConfig(t, layers)},
+		testEntry{Name: "base/layer.tar", Data: string(layers[0])},
+		testEntry{Name: "top/layer.tar", Data: string(layers[1])},
+	)
+	source := filepath.Join(t.TempDir(), "image.tar")
+	writeTestFile(t, source, archive)
+	checkTestImage(t, source, layers)
+}
+
+func TestOCIImage(t *testing.T) {
+	layers := makeTestLayers(t)
+	config := makeTestConfig(t, layers)
+	manifest := ociManifest{
+		Config: ociDescriptor{Digest: testDigest([]byte(config))},
+	}
+	for _, layer := range layers {
+		manifest.Layers = append(manifest.Layers, ociDescriptor{Digest: testDigest(layer)})
+	}
+	manifestData := makeTestJSON(t, manifest)
+	index := ociIndex{Manifests: []ociDescriptor{{Digest: testDigest([]byte(manifestData))}}}
+	blob := func(data []byte) testEntry {
+		return testEntry{Name: "blobs/sha256/" + testDigest(data)[7:], Data: string(data)}
+	}
+	archive := makeTestTar(t,
+		testEntry{Name: "oci-layout", Data: `{"imageLayoutVersion":"1.0.0"}`},
+		testEntry{Name: "index.json", Data: makeTestJSON(t, index)},
+		blob([]byte(manifestData)),
+		blob([]byte(config)),
+		blob(layers[0]),
+		blob(layers[1]),
+	)
+	source := filepath.Join(t.TempDir(), "image.tar")
+	writeTestFile(t, source, archive)
+	checkTestImage(t, source, layers)
+}
+
+func TestNotImageArchive(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "rootfs.tar")
+	writeTestFile(t, source, makeTestTar(t, testEntry{Name: "bin/sh", Data: "sh"}))
+	ok, err := IsImageArchive(source)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if ok {
+		t.Fatal("Expected not image archive")
+	}
+}
+
+func TestExtractLayer(t *testing.T) {
+	layers := makeTestLayers(t)
+	source := filepath.Join(t.TempDir(), "layer.tar")
+	writeTestFile(t, source, layers[1])
+	target := filepath.Join(t.TempDir(), "layer")
+	layer := Layer{Digest: testDigest(layers[1]), Path: source}
+	if err := ExtractLayer(layer, target); err != nil {
+		if os.IsPermission(err) || errors.Is(err, unix.EPERM) || errors.Is(err, unix.ENOTSUP) {
+			t.Skip("Overlay whiteouts are not supported:", err)
+		}
+		t.Fatal("Error:", err)
+	}
+	info, err := os.Lstat(filepath.Join(target, "etc/removed"))
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if info.Mode()&os.ModeCharDevice == 0 {
+		t.Fatal("Expected whiteout device, got", info.Mode())
+	}
+	value := make([]byte, 1)
+	if _, err := unix.Getxattr(filepath.Join(target, "opt/dir"), opaqueXattr, value); err != nil {
+		t.Fatal("Error:", err)
+	}
+	if string(value) != "y" {
+		t.Fatalf("Expected %q, got %q", "y", value)
+	}
+}
+
+func TestExtractLayerDigestMismatch(t *testing.T) {
+	layers := makeTestLayers(t)
+	source := filepath.Join(t.TempDir(), "layer.tar")
+	writeTestFile(t, source, layers[1])
+	target := filepath.Join(t.TempDir(), "layer")
+	layer := Layer{Digest: testDigest(layers[0]), Path: source}
+	if err := ExtractLayer(layer, target); err == nil {
+		t.Fatal("Expected error")
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatal("Expected removed target, got", err)
+	}
+}
+
+func checkTestOutside(t *testing.T, outside string, expected ...string) {
+	entries, err := os.ReadDir(outside)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestApplyLayerSymlinkTraversal(t *testing.T) {
+	outside := t.TempDir()
+	writeTestFile(t, filepath.Join(outside, "kept"), []byte("kept"))
+	base := makeTestTar(t,
+		testEntry{Name: "evil", Link: outside},
+		testEntry{Name: "evil/pwn", Data: "pwn"},
+		testEntry{Name: "rel", Link: "../../../../../../../../.." + outside},
+		testEntry{Name: "rel/pwn", Data: "pwn"},
+	)
+	top := makeTestTar(t,
+		testEntry{Name: "evil/.wh..wh..opq"},
+		testEntry{Name: "rel/.wh.kept"},
+	)
+	rootfs := filepath.Join(t.TempDir(), "rootfs")
+	for _, layer := range [][]byte{base, top} {
+		source := filepath.Join(t.TempDir(), "layer.tar")
+		writeTestFile(t, source, layer)
+		if err := applyLayer(source, rootfs); err != nil {
+			t.Fatal("Error:", err)
+		}
+	}
+	checkTestOutside(t, outside, "kept")
+	if _, err := os.Lstat(filepath.Join(rootfs, "evil")); err != nil {
+		t.Fatal("Error:", err)
+	}
+}
+
+func TestImageArchiveSymlinkTraversal(t *testing.T) {
+	outside := t.TempDir()
+	archive := makeTestTar(t,
+		testEntry{Name: "evil", Link: outside},
+		testEntry{Name: "evil/pwn", Data: "pwn"},
+		testEntry{Name: "rel", Link: "../../../../../../../../.." + outside},
+		testEntry{Name: "rel/pwn", Data: "pwn"},
+	)
+	source := filepath.Join(t.TempDir(), "image.tar")
+	writeTestFile(t, source, archive)
+	_ = extractImageArchive(source, filepath.Join(t.TempDir(), "image"))
+	checkTestOutside(t, outside)
+}
@@ -0,0 +1,213 @@
+package oci
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	whiteoutPrefix = ".wh."
+	whiteoutOpaque = whiteoutPrefix + whiteoutPrefix + ".opq"
+	opaqueXattr    = "trusted.overlay.opaque"
+)
+
+// applyLayer applies layer tarball on top of target directory.
+//
+// Whiteout files remove corresponding paths from target directory, so
+// applying all layers of image in order produces flattened rootfs.
+func applyLayer(source, target string) error {
+	_, err := walkLayer(source, target, func(path string, opaque bool) error {
+		if !opaque {
+			return os.RemoveAll(path)
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		for _, entry := range entries {
+			if err := os.RemoveAll(filepath.Join(path, entry.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// ExtractLayer extracts layer tarball into empty target directory.
+//
+// Whiteout files are converted into overlayfs format, so extracted
+// layers can be stacked as lower directories of overlay mount. Digest
+// of uncompressed content is verified against layer digest and target
+// directory is removed on mismatch.
+func ExtractLayer(layer Layer, target string) (errRes error) {
+	defer func() {
+		if errRes != nil {
+			_ = os.RemoveAll(target)
+		}
+	}()
+	if !strings.HasPrefix(layer.Digest, "sha256:") {
+		return fmt.Errorf("unsupported layer digest %q", layer.Digest)
+	}
+	digest, err := walkLayer(layer.Path, target, func(path string, opaque bool) error {
+		if opaque {
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+			return unix.Setxattr(path, opaqueXattr, []byte("y"), 0)
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+		return unix.Mknod(path, unix.S_IFCHR, 0)
+	})
+	if err != nil {
+		return err
+	}
+	if digest != layer.Digest {
+		return fmt.Errorf("expected layer digest %q, got %q", layer.Digest, digest)
+	}
+	return nil
+}
+
+type whiteoutFunc func(path string, opaque bool) error
+
+// walkLayer applies entries of layer tarball to target directory and
+// returns digest of uncompressed layer content.
+func walkLayer(source, target string, whiteout whiteoutFunc) (string, error) {
+	file, err := os.Open(source)
+	if err != nil {
+		return "", fmt.Errorf("cannot open layer: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	reader, err := openTar(file)
+	if err != nil {
+		return "", fmt.Errorf("cannot open layer: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+	if err := os.MkdirAll(target, os.ModePerm); err != nil {
+		return "", fmt.Errorf("cannot prepare target directory: %w", err)
+	}
+	hash := sha256.New()
+	content := io.TeeReader(reader, hash)
+	archive := tar.NewReader(content)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			// Tarball can contain padding after end of archive.
+			if _, err := io.Copy(io.Discard, content); err != nil {
+				return "", fmt.Errorf("cannot read layer: %w", err)
+			}
+			return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("cannot get header: %w", err)
+		}
+		name := path.Clean("/" + header.Name)
+		if name == "/" {
+			continue
+		}
+		dir, base := path.Split(name)
+		if base == whiteoutOpaque {
+			// Directory itself can be a symlink, so we resolve path
+			// of whiteout and take its parent.
+			whiteoutPath, err := resolvePath(target, name)
+			if err != nil {
+				return "", err
+			}
+			dirPath := filepath.Dir(whiteoutPath)
+			if err := whiteout(dirPath, true); err != nil {
+				return "", fmt.Errorf("cannot apply whiteout %q: %w", name, err)
+			}
+			continue
+		}
+		if strings.HasPrefix(base, whiteoutPrefix) {
+			path, err := resolvePath(target, dir+strings.TrimPrefix(base, whiteoutPrefix))
+			if err != nil {
+				return "", err
+			}
+			if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+				return "", err
+			}
+			if err := whiteout(path, false); err != nil {
+				return "", fmt.Errorf("cannot apply whiteout %q: %w", name, err)
+			}
+			continue
+		}
+		path, err := resolvePath(target, name)
+		if err != nil {
+			return "", err
+		}
+		if err := extractLayerEntry(target, path, header, archive); err != nil {
+			return "", fmt.Errorf("cannot extract %q: %w", name, err)
+		}
+	}
+}
+
+func extractLayerEntry(
+	target, path string, header *tar.Header, reader io.Reader,
+) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	mode := header.FileInfo().Mode().Perm()
+	// Upper layers replace files of lower layers, but directories
+	// should be merged.
+	if info, err := os.Lstat(path); err == nil {
+		if !info.IsDir() || header.Typeflag != tar.TypeDir {
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+	}
+	switch header.Typeflag {
+	case tar.TypeDir:
+		if err := os.MkdirAll(path, mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			return err
+		}
+	case tar.TypeReg:
+		if err := writeFile(path, reader, mode); err != nil {
+			return err
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			return err
+		}
+	case tar.TypeSymlink:
+		if err := os.Symlink(header.Linkname, path); err != nil {
+			return err
+		}
+	case tar.TypeLink:
+		linkPath, err := resolvePath(target, header.Linkname)
+		if err != nil {
+			return err
+		}
+		if err := os.Link(linkPath, path); err != nil {
+			return err
+		}
+	case tar.TypeFifo:
+		if err := unix.Mkfifo(path, uint32(mode)); err != nil {
+			return err
+		}
+	default:
+		// Device nodes are not required inside of sandbox.
+		return nil
+	}
+	// Ownership is preserved only when we have enough privileges.
+	_ = os.Lchown(path, header.Uid, header.Gid)
+	return nil
+}
@@ -26,6 +26,10 @@ type Test struct {
 	Method string `xml:"method,attr"`
 	Sample bool   `xml:"sample,attr"`
 	Cmd    string `xml:"cmd,attr"`
+	// FromFile contains name of file created by generator.
+	//
+	// If FromFile is empty, then generator prints test to stdout.
+	FromFile string `xml:"from-file,attr"`
 }
 
 // TestSet represents a group of tests.
@@ -37,6 +41,8 @@ type TestSet struct {
 	InputPathPattern  string `xml:"input-path-pattern"`
 	AnswerPathPattern string `xml:"answer-path-pattern"`
 	Tests             []Test `xml:"tests>test"`
+	// Script contains test generation script.
+	Script string `xml:"script"`
 }
 
 type Resource struct {
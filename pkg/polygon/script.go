@@ -0,0 +1,146 @@
+package polygon
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ScriptCommand represents generator command from test generation script.
+type ScriptCommand struct {
+	// Line contains line number of command in script.
+	Line int
+	// Generator contains name of generator.
+	Generator string
+	// Args contains generator arguments.
+	Args []string
+	// Tests contains indices of generated tests.
+	//
+	// If command generates single test, then generator prints test
+	// to stdout. Otherwise generator writes tests to files with
+	// names "1", "2", and so on in working directory.
+	Tests []int
+}
+
+// Cmd returns generator command line.
+func (c ScriptCommand) Cmd() string {
+	return strings.Join(append([]string{c.Generator}, c.Args...), " ")
+}
+
+// MultiTest returns true if command generates several tests in one run.
+func (c ScriptCommand) MultiTest() bool {
+	return len(c.Tests) != 1
+}
+
+// ParseScript parses Polygon test generation script.
+//
+// Script consists of lines in the following forms:
+//
+//	gen 1 2 3 > 4       generates test 4 from stdout
+//	gen 1 2 3 > $       generates next free test from stdout
+//	multigen 5 > {5-9}  generates tests from 5 to 9 from files
+//	multigen 5 > {1,3}  generates tests 1 and 3 from files
+//
+// Manual contains indices of manual tests. As in Polygon, they are
+// skipped by "$" and can not be generated by script.
+//
+// FreeMarker templates are not supported.
+func ParseScript(script string, manual ...int) ([]ScriptCommand, error) {
+	var commands []ScriptCommand
+	used := map[int]struct{}{}
+	manualTests := map[int]struct{}{}
+	for _, test := range manual {
+		manualTests[test] = struct{}{}
+	}
+	next := 1
+	for i, line := range strings.Split(script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Contains(line, "<#") || strings.Contains(line, "${") {
+			return nil, fmt.Errorf(
+				"line %d: freemarker templates are not supported", i+1,
+			)
+		}
+		pos := strings.LastIndexByte(line, '>')
+		if pos < 0 {
+			return nil, fmt.Errorf("line %d: expected output redirect", i+1)
+		}
+		args := strings.Fields(line[:pos])
+		if len(args) == 0 {
+			return nil, fmt.Errorf("line %d: expected generator", i+1)
+		}
+		command := ScriptCommand{
+			Line:      i + 1,
+			Generator: args[0],
+			Args:      args[1:],
+		}
+		target := strings.TrimSpace(line[pos+1:])
+		switch {
+		case target == "$":
+			for {
+				_, isUsed := used[next]
+				_, isManual := manualTests[next]
+				if !isUsed && !isManual {
+					break
+				}
+				next++
+			}
+			command.Tests = []int{next}
+		case strings.HasPrefix(target, "{") && strings.HasSuffix(target, "}"):
+			tests, err := parseScriptTests(target[1 : len(target)-1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			command.Tests = tests
+		default:
+			test, err := strconv.Atoi(target)
+			if err != nil || test <= 0 {
+				return nil, fmt.Errorf("line %d: invalid test index %q", i+1, target)
+			}
+			command.Tests = []int{test}
+		}
+		for _, test := range command.Tests {
+			if _, ok := manualTests[test]; ok {
+				return nil, fmt.Errorf("line %d: test %d is manual", i+1, test)
+			}
+			if _, ok := used[test]; ok {
+				return nil, fmt.Errorf("line %d: test %d is already generated", i+1, test)
+			}
+			used[test] = struct{}{}
+		}
+		commands = append(commands, command)
+	}
+	return commands, nil
+}
+
+func parseScriptTests(s string) ([]int, error) {
+	var tests []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if begin, end, ok := strings.Cut(part, "-"); ok {
+			first, err := strconv.Atoi(strings.TrimSpace(begin))
+			if err != nil || first <= 0 {
+				return nil, fmt.Errorf("invalid test range %q", part)
+			}
+			last, err := strconv.Atoi(strings.TrimSpace(end))
+			if err != nil || last < first {
+				return nil, fmt.Errorf("invalid test range %q", part)
+			}
+			for test := first; test <= last; test++ {
+				tests = append(tests, test)
+			}
+			continue
+		}
+		test, err := strconv.Atoi(part)
+		if err != nil || test <= 0 {
+			return nil, fmt.Errorf("invalid test index %q", part)
+		}
+		tests = append(tests, test)
+	}
+	if len(tests) == 0 {
+		return nil, fmt.Errorf("empty test list")
+	}
+	return tests, nil
+}
@@ -0,0 +1,61 @@
+package polygon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	commands, err := ParseScript(`
+gen 1 2 > 2
+gen 3 > $
+multigen 5 > {3-5}
+multigen 6 -test-index > {7,9}
+gen 7 > $
+`)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	expected := []ScriptCommand{
+		{Line: 2, Generator: "gen", Args: []string{"1", "2"}, Tests: []int{2}},
+		{Line: 3, Generator: "gen", Args: []string{"3"}, Tests: []int{1}},
+		{Line: 4, Generator: "multigen", Args: []string{"5"}, Tests: []int{3, 4, 5}},
+		{Line: 5, Generator: "multigen", Args: []string{"6", "-test-index"}, Tests: []int{7, 9}},
+		{Line: 6, Generator: "gen", Args: []string{"7"}, Tests: []int{6}},
+	}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Fatalf("Expected %v, got %v", expected, commands)
+	}
+	if cmd := commands[0].Cmd(); cmd != "gen 1 2" {
+		t.Fatalf("Expected %q, got %q", "gen 1 2", cmd)
+	}
+	if !commands[2].MultiTest() {
+		t.Fatal("Expected multi test command")
+	}
+	// Manual tests are skipped by automatic numbering.
+	commands, err = ParseScript("gen 1 > $\ngen 2 > $\n", 1, 3)
+	if err != nil {
+		t.Fatal("Error:", err)
+	}
+	if commands[0].Tests[0] != 2 || commands[1].Tests[0] != 4 {
+		t.Fatalf("Expected tests 2 and 4, got %v", commands)
+	}
+	if _, err := ParseScript("multigen > {1-3}\n", 2); err == nil {
+		t.Fatal("Expected error")
+	}
+}
+
+func TestParseInvalidScript(t *testing.T) {
+	for _, script := range []string{
+		"gen 1",
+		"> 1",
+		"gen 1 > 0",
+		"gen 1 > {3-1}",
+		"gen 1 > 1\ngen 2 > 1",
+		"<#list 1..5 as i>gen ${i} > $</#list>",
+	} {
+		if _, err := ParseScript(script); err == nil {
+			t.Fatalf("Expected error for %q", script)
+		}
+	}
+}
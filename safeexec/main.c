@@ -5,7 +5,9 @@
 #include <fcntl.h>
 #include <errno.h>
 #include <time.h>
+#include <stddef.h>
 #include <sys/mount.h>
+#include <sys/prctl.h>
 #include <sys/resource.h>
 #include <sys/sendfile.h>
 #include <sys/stat.h>
@@ -15,6 +17,9 @@
 #include <string.h>
 #include <stdlib.h>
 #include <limits.h>
+#include <linux/audit.h>
+#include <linux/filter.h>
+#include <linux/seccomp.h>
 
 #define STACK_SIZE 8096
 #define OVERLAY_DATA "lowerdir=%s,upperdir=%s,workdir=%s"
@@ -24,6 +29,143 @@
 #define CGROUP_MEMORY_SWAP_MAX_FILE "memory.swap.max"
 #define CGROUP_MEMORY_CURRENT_FILE "memory.current"
 #define CGROUP_MEMORY_EVENTS_FILE "memory.events"
+#define CGROUP_MEMORY_PEAK_FILE "memory.peak"
+#define CGROUP_CPU_STAT_FILE "cpu.stat"
+#define CGROUP_PIDS_MAX_FILE "pids.max"
+#define CGROUP_PIDS_EVENTS_FILE "pids.events"
+
+#if defined(__x86_64__)
+#define SECCOMP_AUDIT_ARCH AUDIT_ARCH_X86_64
+#elif defined(__aarch64__)
+#define SECCOMP_AUDIT_ARCH AUDIT_ARCH_AARCH64
+#endif
+
+typedef struct {
+	const char* name;
+	int number;
+} Syscall;
+
+#define SYSCALL(name) {#name, SYS_##name}
+
+static const Syscall syscalls[] = {
+	SYSCALL(read),
+	SYSCALL(write),
+	SYSCALL(openat),
+	SYSCALL(close),
+	SYSCALL(fstat),
+	SYSCALL(newfstatat),
+	SYSCALL(statx),
+	SYSCALL(lseek),
+	SYSCALL(mmap),
+	SYSCALL(mprotect),
+	SYSCALL(munmap),
+	SYSCALL(mremap),
+	SYSCALL(madvise),
+	SYSCALL(brk),
+	SYSCALL(rt_sigaction),
+	SYSCALL(rt_sigprocmask),
+	SYSCALL(rt_sigreturn),
+	SYSCALL(sigaltstack),
+	SYSCALL(ioctl),
+	SYSCALL(pread64),
+	SYSCALL(pwrite64),
+	SYSCALL(readv),
+	SYSCALL(writev),
+	SYSCALL(faccessat),
+	SYSCALL(pipe2),
+	SYSCALL(dup),
+	SYSCALL(dup3),
+	SYSCALL(nanosleep),
+	SYSCALL(clock_nanosleep),
+	SYSCALL(clock_gettime),
+	SYSCALL(clock_getres),
+	SYSCALL(gettimeofday),
+	SYSCALL(getpid),
+	SYSCALL(getppid),
+	SYSCALL(gettid),
+	SYSCALL(getuid),
+	SYSCALL(geteuid),
+	SYSCALL(getgid),
+	SYSCALL(getegid),
+	SYSCALL(clone),
+	SYSCALL(execve),
+	SYSCALL(exit),
+	SYSCALL(exit_group),
+	SYSCALL(wait4),
+	SYSCALL(kill),
+	SYSCALL(tgkill),
+	SYSCALL(uname),
+	SYSCALL(fcntl),
+	SYSCALL(flock),
+	SYSCALL(fsync),
+	SYSCALL(ftruncate),
+	SYSCALL(getdents64),
+	SYSCALL(getcwd),
+	SYSCALL(chdir),
+	SYSCALL(fchdir),
+	SYSCALL(renameat),
+	SYSCALL(mkdirat),
+	SYSCALL(unlinkat),
+	SYSCALL(readlinkat),
+	SYSCALL(fchmod),
+	SYSCALL(fchmodat),
+	SYSCALL(umask),
+	SYSCALL(getrlimit),
+	SYSCALL(setrlimit),
+	SYSCALL(prlimit64),
+	SYSCALL(getrusage),
+	SYSCALL(sysinfo),
+	SYSCALL(times),
+	SYSCALL(prctl),
+	SYSCALL(futex),
+	SYSCALL(set_tid_address),
+	SYSCALL(set_robust_list),
+	SYSCALL(get_robust_list),
+	SYSCALL(sched_yield),
+	SYSCALL(sched_getaffinity),
+	SYSCALL(getrandom),
+	SYSCALL(membarrier),
+	SYSCALL(rseq),
+	SYSCALL(epoll_create1),
+	SYSCALL(epoll_ctl),
+	SYSCALL(epoll_pwait),
+	SYSCALL(ppoll),
+	SYSCALL(pselect6),
+	SYSCALL(eventfd2),
+	SYSCALL(socketpair),
+#ifdef SYS_clone3
+	SYSCALL(clone3),
+#endif
+#ifdef SYS_faccessat2
+	SYSCALL(faccessat2),
+#endif
+#ifdef SYS_open
+	SYSCALL(open),
+	SYSCALL(stat),
+	SYSCALL(lstat),
+	SYSCALL(access),
+	SYSCALL(pipe),
+	SYSCALL(dup2),
+	SYSCALL(fork),
+	SYSCALL(vfork),
+	SYSCALL(getdents),
+	SYSCALL(readlink),
+	SYSCALL(rename),
+	SYSCALL(mkdir),
+	SYSCALL(rmdir),
+	SYSCALL(unlink),
+	SYSCALL(chmod),
+	SYSCALL(poll),
+	SYSCALL(select),
+	SYSCALL(time),
+	SYSCALL(arch_prctl),
+	SYSCALL(epoll_wait),
+	SYSCALL(epoll_create),
+	SYSCALL(getpgrp),
+#endif
+};
+
+#undef SYSCALL
 
 typedef struct {
 	char* rootfs;
@@ -38,9 +180,13 @@ typedef struct {
 	char* cgroupPath;
 	int memoryLimit;
 	int timeLimit;
+	int pidsLimit;
+	long fileSizeLimit;
+	char* seccompAllow;
 	char* report;
 	int initializePipe[2];
 	int finalizePipe[2];
+	int statusPipe[2];
 } Context;
 
 static inline void ensure(int value, const char* message) {
@@ -225,6 +371,18 @@ static inline void prepareCgroupNamespace(const Context* ctx, int pid) {
 		ensure(write(fd, "0", strlen("0")) != -1, "cannot write memory.swap.max");
 		close(fd);
 	}
+	if (ctx->pidsLimit > 0) {
+		strcpy(cgroupPath, ctx->cgroupPath);
+		strcat(cgroupPath, "/");
+		strcat(cgroupPath, CGROUP_PIDS_MAX_FILE);
+		int fd = open(cgroupPath, O_WRONLY);
+		ensure(fd != -1, "cannot open pids.max");
+		char pidsStr[21];
+		// Init process of sandbox is also counted.
+		sprintf(pidsStr, "%d", ctx->pidsLimit + 1);
+		ensure(write(fd, pidsStr, strlen(pidsStr)) != -1, "cannot write pids.max");
+		close(fd);
+	}
 	free(cgroupPath);
 }
 
@@ -258,6 +416,15 @@ static inline void initContext(Context* ctx, int argc, char* argv[]) {
 		} else if (strcmp(argv[i], "--memory-limit") == 0) {
 			++i;
 			ensure(i < argc, "--memory-limit requires argument");
+		} else if (strcmp(argv[i], "--pids-limit") == 0) {
+			++i;
+			ensure(i < argc, "--pids-limit requires argument");
+		} else if (strcmp(argv[i], "--file-size-limit") == 0) {
+			++i;
+			ensure(i < argc, "--file-size-limit requires argument");
+		} else if (strcmp(argv[i], "--seccomp-allow") == 0) {
+			++i;
+			ensure(i < argc, "--seccomp-allow requires argument");
 		} else if (strcmp(argv[i], "--report") == 0) {
 			++i;
 			ensure(i < argc, "--report requires argument");
@@ -302,6 +469,15 @@ static inline void initContext(Context* ctx, int argc, char* argv[]) {
 		} else if (strcmp(argv[i], "--memory-limit") == 0) {
 			++i;
 			ensure(sscanf(argv[i], "%d", &ctx->memoryLimit) == 1, "--memory-limit has invalid argument");
+		} else if (strcmp(argv[i], "--pids-limit") == 0) {
+			++i;
+			ensure(sscanf(argv[i], "%d", &ctx->pidsLimit) == 1, "--pids-limit has invalid argument");
+		} else if (strcmp(argv[i], "--file-size-limit") == 0) {
+			++i;
+			ensure(sscanf(argv[i], "%ld", &ctx->fileSizeLimit) == 1, "--file-size-limit has invalid argument");
+		} else if (strcmp(argv[i], "--seccomp-allow") == 0) {
+			++i;
+			ctx->seccompAllow = argv[i];
 		} else if (strcmp(argv[i], "--report") == 0) {
 			++i;
 			ctx->report = argv[i];
@@ -342,6 +518,9 @@ static inline Context* newContext() {
 	ctx->cgroupPath = "";
 	ctx->timeLimit = 0;
 	ctx->memoryLimit = 0;
+	ctx->pidsLimit = 0;
+	ctx->fileSizeLimit = 0;
+	ctx->seccompAllow = "";
 	ctx->report = "";
 	return ctx;
 }
@@ -352,11 +531,71 @@ static inline void freeContext(Context* ctx) {
 	free(ctx);
 }
 
+static inline int findSyscall(const char* name, size_t len) {
+	for (size_t i = 0; i < sizeof(syscalls) / sizeof(syscalls[0]); ++i) {
+		if (strlen(syscalls[i].name) == len && strncmp(syscalls[i].name, name, len) == 0) {
+			return syscalls[i].number;
+		}
+	}
+	return -1;
+}
+
+// setupSeccomp installs filter that kills process on any syscall
+// that is not specified in comma-separated allowlist.
+static inline void setupSeccomp(const Context* ctx) {
+#ifdef SECCOMP_AUDIT_ARCH
+	int count = 1;
+	for (const char* it = ctx->seccompAllow; *it; ++it) {
+		if (*it == ',') {
+			++count;
+		}
+	}
+	// Execve should be always allowed because filter is installed before it.
+	int numbers[count + 1];
+	int numbersLen = 0;
+	numbers[numbersLen++] = SYS_execve;
+	for (const char* it = ctx->seccompAllow; *it;) {
+		const char* end = strchr(it, ',');
+		size_t len = end ? (size_t)(end - it) : strlen(it);
+		if (len > 0) {
+			int number = findSyscall(it, len);
+			ensure(number != -1, "--seccomp-allow has unknown syscall");
+			numbers[numbersLen++] = number;
+		}
+		it += len;
+		if (*it == ',') {
+			++it;
+		}
+	}
+	int filterLen = numbersLen * 2 + 5;
+	struct sock_filter filter[filterLen];
+	int pos = 0;
+	filter[pos++] = (struct sock_filter)BPF_STMT(BPF_LD | BPF_W | BPF_ABS, offsetof(struct seccomp_data, arch));
+	filter[pos++] = (struct sock_filter)BPF_JUMP(BPF_JMP | BPF_JEQ | BPF_K, SECCOMP_AUDIT_ARCH, 1, 0);
+	filter[pos++] = (struct sock_filter)BPF_STMT(BPF_RET | BPF_K, SECCOMP_RET_KILL_PROCESS);
+	filter[pos++] = (struct sock_filter)BPF_STMT(BPF_LD | BPF_W | BPF_ABS, offsetof(struct seccomp_data, nr));
+	for (int i = 0; i < numbersLen; ++i) {
+		filter[pos++] = (struct sock_filter)BPF_JUMP(BPF_JMP | BPF_JEQ | BPF_K, numbers[i], 0, 1);
+		filter[pos++] = (struct sock_filter)BPF_STMT(BPF_RET | BPF_K, SECCOMP_RET_ALLOW);
+	}
+	filter[pos++] = (struct sock_filter)BPF_STMT(BPF_RET | BPF_K, SECCOMP_RET_KILL_PROCESS);
+	struct sock_fprog prog = {
+		.len = (unsigned short)pos,
+		.filter = filter,
+	};
+	ensure(prctl(PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0) == 0, "cannot set no_new_privs");
+	ensure(prctl(PR_SET_SECCOMP, SECCOMP_MODE_FILTER, &prog) == 0, "cannot setup seccomp");
+#else
+	ensure(0, "seccomp is not supported on current architecture");
+#endif
+}
+
 int entrypoint(void* arg) {
 	ensure(arg != 0, "cannot get config");
 	Context* ctx = (Context*)arg;
 	close(ctx->initializePipe[1]);
 	close(ctx->finalizePipe[0]);
+	close(ctx->statusPipe[0]);
 	// Setup user namespace first of all.
 	setupUserNamespace(ctx);
 	setupCgroupNamespace(ctx);
@@ -368,9 +607,35 @@ int entrypoint(void* arg) {
 	limit.rlim_cur = RLIM_INFINITY;
 	limit.rlim_max = RLIM_INFINITY;
 	ensure(setrlimit(RLIMIT_STACK, &limit) == 0, "cannot set stack limit");
+	// Setup file size limit.
+	if (ctx->fileSizeLimit > 0) {
+		limit.rlim_cur = ctx->fileSizeLimit;
+		limit.rlim_max = ctx->fileSizeLimit;
+		ensure(setrlimit(RLIMIT_FSIZE, &limit) == 0, "cannot set file size limit");
+	}
 	// Unlock parent process.
 	close(ctx->finalizePipe[1]);
-	return execvpe(ctx->args[0], ctx->args, ctx->environ);
+	// Current process is init of PID namespace, so it ignores signals
+	// without handlers. Run command as child process to keep default
+	// actions of signals such as SIGXFSZ.
+	int pid = fork();
+	ensure(pid != -1, "cannot fork()");
+	if (pid == 0) {
+		close(ctx->statusPipe[1]);
+		// Seccomp should be installed last because it can forbid syscalls above.
+		if (strlen(ctx->seccompAllow) != 0) {
+			setupSeccomp(ctx);
+		}
+		return execvpe(ctx->args[0], ctx->args, ctx->environ);
+	}
+	int status;
+	while (waitpid(pid, &status, __WALL) != pid) {
+		ensure(errno == EINTR, "cannot wait for command process");
+	}
+	// Pass status of command to parent process.
+	ensure(write(ctx->statusPipe[1], &status, sizeof(status)) == sizeof(status), "cannot write status pipe");
+	close(ctx->statusPipe[1]);
+	return WIFEXITED(status) ? WEXITSTATUS(status) : EXIT_FAILURE;
 }
 
 static inline void readCgroupMemory(const char* path, long* value) {
@@ -409,6 +674,70 @@ static inline void readCgroupOomCount(const Context* ctx, long* value) {
 	free(filePath);
 }
 
+static inline int readCgroupMemoryPeak(const Context* ctx, long* value) {
+	char* filePath = malloc(strlen(ctx->cgroupPath) + strlen(CGROUP_MEMORY_PEAK_FILE) + 2);
+	ensure(filePath != NULL, "cannot allocate memory.peak path");
+	strcpy(filePath, ctx->cgroupPath);
+	strcat(filePath, "/");
+	strcat(filePath, CGROUP_MEMORY_PEAK_FILE);
+	// File memory.peak is available only since Linux 5.19.
+	if (access(filePath, R_OK) != 0) {
+		free(filePath);
+		return 0;
+	}
+	readCgroupMemory(filePath, value);
+	free(filePath);
+	return 1;
+}
+
+static inline void readCgroupCpuStat(const Context* ctx, long* userTime, long* systemTime) {
+	char* filePath = malloc(strlen(ctx->cgroupPath) + strlen(CGROUP_CPU_STAT_FILE) + 2);
+	ensure(filePath != NULL, "cannot allocate cpu.stat path");
+	strcpy(filePath, ctx->cgroupPath);
+	strcat(filePath, "/");
+	strcat(filePath, CGROUP_CPU_STAT_FILE);
+	FILE* file = fopen(filePath, "re");
+	ensure(file != NULL, "cannot open cpu.stat file");
+	char* data = NULL;
+	size_t len = 0;
+	ssize_t bytes = 0;
+	while ((bytes = getline(&data, &len, file)) != -1) {
+		if (bytes > 10 && memcmp(data, "user_usec ", 10) == 0) {
+			*userTime = strtol(&data[10], NULL, 10);
+			ensure(*userTime != LONG_MAX, "invalid cpu.stat user_usec value");
+		} else if (bytes > 12 && memcmp(data, "system_usec ", 12) == 0) {
+			*systemTime = strtol(&data[12], NULL, 10);
+			ensure(*systemTime != LONG_MAX, "invalid cpu.stat system_usec value");
+		}
+	}
+	fclose(file);
+	free(data);
+	free(filePath);
+}
+
+static inline void readCgroupPidsMaxCount(const Context* ctx, long* value) {
+	char* filePath = malloc(strlen(ctx->cgroupPath) + strlen(CGROUP_PIDS_EVENTS_FILE) + 2);
+	ensure(filePath != NULL, "cannot allocate pids.events path");
+	strcpy(filePath, ctx->cgroupPath);
+	strcat(filePath, "/");
+	strcat(filePath, CGROUP_PIDS_EVENTS_FILE);
+	FILE* file = fopen(filePath, "re");
+	ensure(file != NULL, "cannot open pids.events file");
+	char* data = NULL;
+	size_t len = 0;
+	ssize_t bytes = 0;
+	while ((bytes = getline(&data, &len, file)) != -1) {
+		if (bytes < 6 || memcmp(data, "max ", 4)) {
+			continue;
+		}
+		*value = strtol(&data[4], NULL, 10);
+		ensure(*value != LONG_MAX, "invalid pids.events max value");
+	}
+	fclose(file);
+	free(data);
+	free(filePath);
+}
+
 int main(int argc, char* argv[]) {
 	Context* ctx = newContext();
 	initContext(ctx, argc, argv);
@@ -422,6 +751,7 @@ int main(int argc, char* argv[]) {
 	ensure(ctx->memoryLimit, "--memory-limit is required");
 	ensure(pipe(ctx->initializePipe) == 0, "cannot create initialize pipe");
 	ensure(pipe(ctx->finalizePipe) == 0, "cannot create finalize pipe");
+	ensure(pipe2(ctx->statusPipe, O_CLOEXEC) == 0, "cannot create status pipe");
 	char* stack = malloc(STACK_SIZE);
 	ensure(stack != NULL, "cannot allocate stack");
 	int pid = clone(
@@ -433,6 +763,7 @@ int main(int argc, char* argv[]) {
 	ensure(pid != -1, "cannot clone()");
 	close(ctx->initializePipe[0]);
 	close(ctx->finalizePipe[1]);
+	close(ctx->statusPipe[1]);
 	// Setup user namespace.
 	prepareUserNamespace(pid);
 	// Setup cgroup namespace.
@@ -478,15 +809,32 @@ int main(int argc, char* argv[]) {
 		}
 		nanosleep(&sleepSpec, NULL);
 	} while (result == 0);
+	// Status of command is not available when init process is killed.
+	int commandStatus;
+	if (read(ctx->statusPipe[0], &commandStatus, sizeof(commandStatus)) == sizeof(commandStatus)) {
+		status = commandStatus;
+	}
+	close(ctx->statusPipe[0]);
 	readCgroupMemory(memoryCurrentPath, &currentMemory);
+	long peakMemory = memory;
+	if (readCgroupMemoryPeak(ctx, &peakMemory) && peakMemory < memory) {
+		peakMemory = memory;
+	}
+	long userTime = 0, systemTime = 0;
+	readCgroupCpuStat(ctx, &userTime, &systemTime);
 	int exitCode = WIFEXITED(status) ? WEXITSTATUS(status) : -1;
+	long oomCount = 0;
 	if (exitCode != 0) {
-		long oomCount = 0;
 		readCgroupOomCount(ctx, &oomCount);
 		if (oomCount > 0) {
 			memory = ctx->memoryLimit + 1024;
 		}
 	}
+	int termSignal = WIFSIGNALED(status) ? WTERMSIG(status) : 0;
+	long pidsMaxCount = 0;
+	if (ctx->pidsLimit > 0) {
+		readCgroupPidsMaxCount(ctx, &pidsMaxCount);
+	}
 	if (strlen(ctx->report) != 0) {
 		char line[60];
 		int fd = open(ctx->report, O_WRONLY | O_TRUNC | O_CREAT, 0644);
@@ -497,6 +845,34 @@ int main(int argc, char* argv[]) {
 		ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
 		sprintf(line, "exit_code %d\n", exitCode);
 		ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		sprintf(line, "wall_time %ld\n", getTimeDiff(currentTime, startTime));
+		ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		sprintf(line, "user_time %ld\n", userTime / 1000);
+		ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		sprintf(line, "system_time %ld\n", systemTime / 1000);
+		ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		sprintf(line, "memory_peak %ld\n", peakMemory);
+		ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		if (termSignal != 0) {
+			sprintf(line, "signal %d\n", termSignal);
+			ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		}
+		if (oomCount > 0) {
+			sprintf(line, "oom_killed 1\n");
+			ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		}
+		if (pidsMaxCount > 0) {
+			sprintf(line, "pids_limit_exceeded 1\n");
+			ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		}
+		if (termSignal == SIGXFSZ) {
+			sprintf(line, "file_size_limit_exceeded 1\n");
+			ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		}
+		if (termSignal == SIGSYS) {
+			sprintf(line, "security_violation 1\n");
+			ensure(write(fd, line, strlen(line)) != -1, "cannot write report file");
+		}
 		close(fd);
 	}
 	free(memoryCurrentPath);